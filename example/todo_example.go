@@ -48,7 +48,7 @@ func addTodoExample() {
 	todo := &sqlitedb.Todo{
 		Title:       "准备会议材料",
 		Description: "为下周的产品讨论会准备演示文稿和演示材料",
-		Status:      "未开始",
+		Status:      string(sqlitedb.TodoStatusNotStarted),
 		Priority:    1,                              // 高优先级
 		DueDate:     time.Now().Add(72 * time.Hour), // 3天后截止
 		MeetingID:   "meeting123",
@@ -126,7 +126,7 @@ func updateTodoExample() {
 	fmt.Printf("更新前: %s (状态: %s, 优先级: %d)\n", todo.Title, todo.Status, todo.Priority)
 
 	// 修改状态和优先级
-	todo.Status = "进行中"
+	todo.Status = string(sqlitedb.TodoStatusInProgress)
 	todo.Priority = 2
 	todo.Description = todo.Description + " [已更新]"
 
@@ -154,7 +154,7 @@ func deleteTodoExample() {
 	tempTodo := &sqlitedb.Todo{
 		Title:       "临时任务",
 		Description: "这是一个将被删除的临时任务",
-		Status:      "未开始",
+		Status:      string(sqlitedb.TodoStatusNotStarted),
 		MeetingID:   "meeting_temp",
 	}
 
@@ -197,7 +197,7 @@ func batchAddTodoExample() {
 		{
 			Title:       "编写文档",
 			Description: "编写API文档",
-			Status:      "未开始",
+			Status:      string(sqlitedb.TodoStatusNotStarted),
 			Priority:    2,
 			MeetingID:   "meeting456",
 			AssignedTo:  "李四",
@@ -205,7 +205,7 @@ func batchAddTodoExample() {
 		{
 			Title:       "代码审核",
 			Description: "审核前端代码",
-			Status:      "未开始",
+			Status:      string(sqlitedb.TodoStatusNotStarted),
 			Priority:    1,
 			MeetingID:   "meeting456",
 			AssignedTo:  "王五",
@@ -213,7 +213,7 @@ func batchAddTodoExample() {
 		{
 			Title:       "单元测试",
 			Description: "编写单元测试用例",
-			Status:      "未开始",
+			Status:      string(sqlitedb.TodoStatusNotStarted),
 			Priority:    3,
 			MeetingID:   "meeting456",
 			AssignedTo:  "赵六",
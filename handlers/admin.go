@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"meetingagent/models"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// AdminAuth 管理接口鉴权中间件，要求请求头 Authorization: Bearer <admin.token>。
+// 未配置 admin.token 时管理接口不可用，返回 403
+func AdminAuth() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		token := models.GetAdminToken()
+		if token == "" {
+			c.AbortWithStatusJSON(consts.StatusForbidden, utils.H{"error": "未配置 admin.token，管理接口不可用"})
+			return
+		}
+
+		provided, ok := bearerToken(c)
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(consts.StatusUnauthorized, utils.H{"error": "管理接口访问令牌无效"})
+			return
+		}
+		c.Next(ctx)
+	}
+}
+
+// bearerToken 读取请求头 Authorization: Bearer <token> 中的访问令牌
+func bearerToken(c *app.RequestContext) (string, bool) {
+	provided, ok := strings.CutPrefix(string(c.GetHeader("Authorization")), "Bearer ")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(provided), true
+}
+
+// ReloadConfig 处理重新加载配置文件请求。新配置校验失败时保留当前配置并返回 400；
+// 待办默认值无效时仍然生效，新建待办使用内置默认值，并在 warnings 中说明
+func ReloadConfig(ctx context.Context, c *app.RequestContext) {
+	if _, err := models.ReloadConfig(); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "重新加载配置失败，仍使用当前配置: " + err.Error()})
+		return
+	}
+
+	warnings := []string{}
+	if err := ValidateTodoDefaults(); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"message":  "配置已重新加载",
+		"warnings": warnings,
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// cacheableCacheControl 可缓存接口的 Cache-Control：允许客户端缓存，但每次使用前都需要用 ETag 重新验证
+const cacheableCacheControl = "private, no-cache"
+
+// jsonWithETag 返回带弱 ETag 的 JSON 响应，ETag 为响应体的哈希。
+// 请求头 If-None-Match 与 ETag 匹配时返回 304 且不返回响应体，适用于前端轮询的 GET 接口，流式接口不使用
+func jsonWithETag(c *app.RequestContext, obj interface{}) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "序列化响应失败: " + err.Error()})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Response.Header.Set("ETag", etag)
+	c.Response.Header.Set("Cache-Control", cacheableCacheControl)
+
+	if etagMatches(string(c.GetHeader("If-None-Match")), etag) {
+		c.Status(consts.StatusNotModified)
+		return
+	}
+	c.Data(consts.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches 按弱比较判断 If-None-Match 中是否有与 etag 相同的值，"*" 匹配任意值
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"meetingagent/models"
+	"meetingagent/sql"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// Version 服务版本，构建时通过 -ldflags "-X meetingagent/handlers.Version=v1.2.3" 注入
+var Version = "dev"
+
+// readyCheckTimeout 就绪检查中单项检查的超时时间
+const readyCheckTimeout = 2 * time.Second
+
+// startTime 服务启动时间，用于计算运行时长
+var startTime = time.Now()
+
+// ReadyCheck 一项就绪检查的结果
+type ReadyCheck struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// HealthCheck 处理存活检查请求，只要进程能响应请求即返回200
+func HealthCheck(ctx context.Context, c *app.RequestContext) {
+	c.JSON(consts.StatusOK, utils.H{
+		"status":         "ok",
+		"version":        Version,
+		"uptime_seconds": int64(time.Since(startTime).Seconds()),
+	})
+}
+
+// ReadinessCheck 处理就绪检查请求，检查配置和待办数据库，任一项失败或服务正在关闭时返回503。
+// 检查不调用模型接口，可被频繁轮询
+func ReadinessCheck(ctx context.Context, c *app.RequestContext) {
+	checks := map[string]ReadyCheck{
+		"config":  checkConfig(),
+		"todo_db": checkTodoDB(ctx),
+	}
+
+	select {
+	case <-shuttingDown:
+		checks["shutdown"] = ReadyCheck{Error: "服务正在关闭"}
+	default:
+	}
+
+	status, code := "ready", consts.StatusOK
+	for _, check := range checks {
+		if !check.OK {
+			status, code = "not_ready", consts.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, utils.H{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+// checkConfig 检查配置文件已加载且模型配置完整
+func checkConfig() ReadyCheck {
+	if _, err := models.LoadConfig(); err != nil {
+		return ReadyCheck{Error: err.Error()}
+	}
+	if modelName, _ := models.GetLLMModelName(); modelName == "" {
+		return ReadyCheck{Error: fmt.Sprintf("%s模型名称未配置", models.GetLLMProvider())}
+	}
+	return ReadyCheck{OK: true}
+}
+
+// checkTodoDB 检查待办数据库可查询
+func checkTodoDB(ctx context.Context) ReadyCheck {
+	ctx, cancel := context.WithTimeout(ctx, readyCheckTimeout)
+	defer cancel()
+
+	if err := sql.PingDatabase(ctx, dbName); err != nil {
+		return ReadyCheck{Error: err.Error()}
+	}
+	return ReadyCheck{OK: true}
+}
@@ -3,11 +3,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net/url"
+	"strconv"
 	"strings"
-	"time"
 
 	"meetingagent/models"
 	sqldb "meetingagent/sql"
@@ -18,6 +18,26 @@ import (
 	"github.com/hertz-contrib/sse"
 )
 
+// meetingQueue 会议抽取任务队列，所有创建会议请求都经由队列按优先级处理以平滑模型调用速率
+var meetingQueue = newMeetingQueue()
+
+// newMeetingQueue 根据配置创建会议抽取任务队列
+func newMeetingQueue() *models.MeetingJobQueue {
+	workers, maxPending := models.GetQueueSettings()
+	return models.NewMeetingJobQueue(workers, maxPending, processMeetingJob)
+}
+
+// StartMeetingQueue 启动会议抽取任务队列的worker
+func StartMeetingQueue(ctx context.Context) {
+	meetingQueue.Start(ctx)
+}
+
+// 创建会议的幂等键请求头
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
 // CreateMeeting 处理创建会议请求
 func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 	var reqBody map[string]interface{}
@@ -32,17 +52,7 @@ func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	fmt.Printf("create meeting: %s\n", string(jsonBody))
-
-	// 生成会议ID
-	meetingID := "meeting_" + time.Now().Format("20060102150405")
-
-	// 创建存储目录
-	storageDir := "./storage/meetings"
-	if err := os.MkdirAll(storageDir, 0755); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法创建存储目录"})
-		return
-	}
+	models.Logf(ctx, "create meeting: %s\n", string(jsonBody))
 
 	// 从原始文档中提取文本内容
 	documentText := ""
@@ -50,705 +60,1454 @@ func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 		documentText = content
 	} else {
 		// 如果内容不是字符串，尝试转换整个请求体为字符串
-		contentBytes, err := json.Marshal(reqBody)
-		if err == nil {
-			documentText = string(contentBytes)
-		}
+		documentText = string(jsonBody)
 	}
 
-	// 调用LLM抽取会议信息
-	meetingInfo, err := models.ExtractMeetingInfo(ctx, documentText)
-	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法分析会议内容: " + err.Error()})
+	filename, _ := reqBody["filename"].(string)
+	documentText, ok := subtitleToText(c, filename, documentText)
+	if !ok {
 		return
 	}
 
-	// 将会议中的待办事项添加到数据库
-	if todoList, ok := meetingInfo["todo_list"].([]interface{}); ok && len(todoList) > 0 {
-		// 提取会议标题作为任务描述前缀
-		meetingTitle := ""
-		if title, ok := meetingInfo["title"].(string); ok {
-			meetingTitle = title
-		}
+	createMeetingFromText(ctx, c, reqBody, documentText)
+}
 
-		// 将todo_list中的每一项添加到数据库
-		var todos []*sqldb.Todo
-		for _, item := range todoList {
-			if todoStr, ok := item.(string); ok && todoStr != "" {
-				todo := &sqldb.Todo{
-					Title:       todoStr,
-					Description: fmt.Sprintf("来自会议: %s", meetingTitle),
-					Status:      "未开始",
-					Priority:    2, // 默认中等优先级
-					MeetingID:   meetingID,
-				}
-				todos = append(todos, todo)
-			}
-		}
+// subtitleToText 会议内容是 VTT 或 SRT 字幕时（按文件名扩展名或内容判断）转换为按说话人分行的纯文本，
+// 其他内容原样返回。字幕格式错误时返回 400 并返回 false
+func subtitleToText(c *app.RequestContext, filename, documentText string) (string, bool) {
+	format := models.DetectSubtitleFormat(filename, documentText)
+	if format == "" {
+		return documentText, true
+	}
 
-		// 批量添加待办事项
-		if len(todos) > 0 {
-			todoDbName := "./storage/todo.db"
-			if err := sqldb.BatchAddTodos(todoDbName, todos); err != nil {
-				fmt.Printf("添加会议待办事项失败: %v\n", err)
-				// 这里我们只记录错误，不中断会议创建流程
-			} else {
-				fmt.Printf("成功添加 %d 个会议待办事项到数据库\n", len(todos))
-			}
-		}
+	text, err := models.ParseSubtitle(format, documentText)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return "", false
 	}
+	fmt.Printf("会议内容为 %s 字幕，已转换为纯文本\n", format)
+	return text, true
+}
 
-	// 构建完整的会议内容
-	meetingData := map[string]interface{}{
-		"metadata":    meetingInfo,
-		"raw_content": documentText,
+// CreateMeetingFromURL 处理从URL创建会议的请求：拉取远程会议记录后按创建会议的流程抽取并保存
+func CreateMeetingFromURL(ctx context.Context, c *app.RequestContext) {
+	var reqBody map[string]interface{}
+	if err := c.BindJSON(&reqBody); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
 	}
 
-	// 将处理后的会议数据序列化为JSON
-	processedJSON, err := json.Marshal(meetingData)
-	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法序列化会议数据"})
+	rawURL, _ := reqBody["url"].(string)
+	if strings.TrimSpace(rawURL) == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "url是必需的"})
 		return
 	}
 
-	// 将JSON内容写入文件
-	filePath := filepath.Join(storageDir, meetingID+".json")
-	if err := os.WriteFile(filePath, processedJSON, 0644); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法保存会议文档"})
+	models.Logf(ctx, "create meeting from url: %s\n", rawURL)
+
+	documentText, err := models.FetchTranscript(ctx, rawURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrFetchURLNotAllowed):
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		case errors.Is(err, models.ErrFetchUnsupportedContent):
+			c.JSON(consts.StatusUnsupportedMediaType, utils.H{"error": err.Error()})
+		case errors.Is(err, models.ErrFetchTooLarge):
+			c.JSON(consts.StatusRequestEntityTooLarge, utils.H{"error": err.Error()})
+		default:
+			c.JSON(consts.StatusBadGateway, utils.H{"error": err.Error()})
+		}
+		return
+	}
+	if strings.TrimSpace(documentText) == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "远程内容为空"})
 		return
 	}
 
-	// 返回响应
-	response := models.PostMeetingResponse{
-		ID: meetingID,
+	// URL 路径的扩展名用于判断字幕格式，例如 .../meeting.vtt
+	filename := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		filename = parsed.Path
+	}
+	documentText, ok := subtitleToText(c, filename, documentText)
+	if !ok {
+		return
 	}
 
-	c.JSON(consts.StatusOK, response)
+	createMeetingFromText(ctx, c, reqBody, documentText)
 }
 
-// ListMeetings 处理获取会议列表请求
-func ListMeetings(ctx context.Context, c *app.RequestContext) {
-	storageDir := "./storage/meetings"
+// createMeetingFromText 校验会议内容并提交抽取任务，按请求中的 async 决定是否等待任务完成。
+// reqBody 中的 tags、urgent、async、force、callback_url、meeting_type 与创建会议接口含义相同
+func createMeetingFromText(ctx context.Context, c *app.RequestContext, reqBody map[string]interface{}, documentText string) {
+	// 生成会议ID
+	meetingID := models.NewMeetingID()
+
+	// 过长的会议内容在入队前拒绝，避免占用队列后才失败
+	if err := models.ValidateMeetingContent(documentText); err != nil {
+		c.JSON(consts.StatusRequestEntityTooLarge, utils.H{"error": err.Error()})
+		return
+	}
 
-	// 读取目录中的所有文件
-	files, err := os.ReadDir(storageDir)
+	tags, err := models.ParseTags(reqBody["tags"])
 	if err != nil {
-		if os.IsNotExist(err) {
-			// 如果目录不存在，返回空列表
-			c.JSON(consts.StatusOK, models.GetMeetingsResponse{
-				Meetings: []models.Meeting{},
-			})
-			return
-		}
-		// 其他错误返回500
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议列表"})
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
 
-	// 存储所有会议的切片
-	var meetings []models.Meeting
+	meetingType, err := createMeetingType(c, reqBody)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
 
-	// 遍历所有文件
-	for _, file := range files {
-		// 跳过目录和非json文件
-		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
-			continue
+	// 回调地址在入队前校验，避免任务结束后才发现地址不可用
+	callbackURL, _ := reqBody["callback_url"].(string)
+	callbackURL = strings.TrimSpace(callbackURL)
+	if callbackURL != "" {
+		if err := models.ValidateCallbackURL(callbackURL); err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "callback_url 无效: " + err.Error()})
+			return
 		}
+	}
 
-		// 读取文件内容
-		filePath := filepath.Join(storageDir, file.Name())
-		data, err := os.ReadFile(filePath)
+	// 携带幂等键的重复请求直接返回之前创建的会议，不再重复创建
+	idempotencyKey := strings.TrimSpace(string(c.GetHeader(idempotencyKeyHeader)))
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("%s 长度不能超过%d", idempotencyKeyHeader, maxIdempotencyKeyLength)})
+		return
+	}
+	if idempotencyKey != "" {
+		record, reserved, err := sqldb.ReserveIdempotencyKey(dbName, idempotencyKey, meetingID, models.GetIdempotencyTTL())
+		if err == nil && !reserved && meetingJobFailed(record.JobID) {
+			// 之前的异步任务已失败，允许使用同一幂等键重新创建
+			if err = sqldb.ReleaseIdempotencyKey(dbName, idempotencyKey); err == nil {
+				record, reserved, err = sqldb.ReserveIdempotencyKey(dbName, idempotencyKey, meetingID, models.GetIdempotencyTTL())
+			}
+		}
 		if err != nil {
-			// 记录错误但继续处理其他文件
-			fmt.Printf("读取文件 %s 失败: %v\n", filePath, err)
-			continue
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "处理幂等键失败: " + err.Error()})
+			return
 		}
-
-		// 解析JSON内容
-		var meetingData map[string]interface{}
-		if err := json.Unmarshal(data, &meetingData); err != nil {
-			fmt.Printf("解析文件 %s 失败: %v\n", filePath, err)
-			continue
+		if !reserved {
+			c.Response.Header.Set("Idempotent-Replayed", "true")
+			c.JSON(consts.StatusOK, models.PostMeetingResponse{
+				ID:    record.MeetingID,
+				JobID: record.JobID,
+			})
+			return
 		}
-
-		// 从文件名中提取ID (去掉.json后缀)
-		meetingID := strings.TrimSuffix(file.Name(), ".json")
-
-		// 获取元数据信息
-		var content map[string]interface{}
-
-		if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-			// 使用LLM提取的元数据
-			content = metadata
-			// 确保原始内容也包含在内
-			if rawContent, ok := meetingData["raw_content"].(string); ok {
-				content["content"] = rawContent
-			}
-		} else {
-			// 兼容旧格式，或者使用整个数据
-			content = meetingData
+	}
+	// 创建失败时释放幂等键，允许客户端使用同一幂等键重试
+	releaseIdempotencyKey := func() {
+		if idempotencyKey == "" {
+			return
 		}
-
-		// 创建Meeting对象并添加到列表
-		meeting := models.Meeting{
-			ID:      meetingID,
-			Content: content,
+		if err := sqldb.ReleaseIdempotencyKey(dbName, idempotencyKey); err != nil {
+			models.Logf(ctx, "释放幂等键失败: %v\n", err)
 		}
-		meetings = append(meetings, meeting)
 	}
 
-	// 返回所有会议
-	response := models.GetMeetingsResponse{
-		Meetings: meetings,
+	// 开启重复检测时，内容与近期会议相同或高度相似的请求返回已有会议，传 force=true 时仍然创建
+	if settings := models.GetDedupSettings(); settings.Enabled && !forceCreateMeeting(c, reqBody) {
+		duplicate, err := models.FindDuplicateMeeting(documentText, settings)
+		if err != nil {
+			// 检测失败不阻止创建会议
+			models.Logf(ctx, "检查重复会议失败: %v\n", err)
+		} else if duplicate != nil {
+			releaseIdempotencyKey()
+			c.JSON(consts.StatusConflict, utils.H{
+				"error":               "已存在内容相同或相似的会议，如需重复创建请传 force=true",
+				"existing_meeting_id": duplicate.MeetingID,
+				"title":               duplicate.Title,
+				"match":               duplicate.Match,
+				"similarity":          duplicate.Similarity,
+			})
+			return
+		}
 	}
 
-	c.JSON(consts.StatusOK, response)
-}
-
-// GetMeetingSummary 处理获取会议摘要请求
-func GetMeetingSummary(ctx context.Context, c *app.RequestContext) {
-	meetingID := c.Query("meeting_id")
-	if meetingID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id is required"})
+	// 抽取任务入队，队列积压超限时拒绝请求
+	job := &models.MeetingJob{
+		MeetingID:    meetingID,
+		Priority:     meetingJobPriority(c, reqBody),
+		DocumentText: documentText,
+		Tags:         tags,
+		CallbackURL:  callbackURL,
+		MeetingType:  meetingType,
+	}
+	if err := meetingQueue.Enqueue(job); err != nil {
+		releaseIdempotencyKey()
+		if errors.Is(err, models.ErrQueueFull) || errors.Is(err, models.ErrQueueClosed) {
+			c.Response.Header.Set("Retry-After", "30")
+			c.JSON(consts.StatusServiceUnavailable, utils.H{"error": err.Error()})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "提交会议处理任务失败: " + err.Error()})
 		return
 	}
-	fmt.Printf("meetingID: %s\n", meetingID)
-
-	// 读取对应会议文件内容
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
+	if idempotencyKey != "" {
+		if err := sqldb.SetIdempotencyJobID(dbName, idempotencyKey, job.ID); err != nil {
+			models.Logf(ctx, "记录幂等键任务ID失败: %v\n", err)
+		}
+	}
 
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+	// 异步模式下立即返回任务ID，客户端通过 GET /meeting/jobs/:id 查询进度
+	if async, _ := reqBody["async"].(bool); async || c.Query("async") == "true" {
+		c.JSON(consts.StatusAccepted, models.PostMeetingResponse{
+			ID:    meetingID,
+			JobID: job.ID,
+		})
 		return
 	}
 
-	// 读取会议文件
-	data, err := os.ReadFile(filePath)
+	// 同步模式下等待任务完成
+	result, err := meetingQueue.Wait(ctx, job.ID)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "等待会议处理任务失败: " + err.Error()})
 		return
 	}
-
-	// 解析JSON内容
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法解析会议数据"})
+	if result.Status == models.JobStatusFailed {
+		releaseIdempotencyKey()
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": result.Error})
 		return
 	}
 
-	// 从meetingData中提取摘要信息
-	var summary string
-
-	// 尝试从新格式中获取元数据
-	if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-		// 提取摘要
-		if sum, ok := metadata["summary"].(string); ok {
-			summary = sum
-		} else {
-			summary = "无摘要信息"
-		}
-	} else {
-		// 兼容旧格式，或者使用整个数据
-		summary = "无法从会议数据中提取摘要信息"
+	// 返回响应，待办超出上限时附带未自动创建的待办，由用户按需手动添加
+	response := models.PostMeetingResponse{
+		ID: meetingID,
 	}
-
-	// 构建响应
-	response := map[string]interface{}{
-		"summary": summary,
+	if meetingData, err := models.LoadMeeting(meetingID); err == nil {
+		metadata, _ := models.GetMeetingMetadata(meetingData)
+		response.UntrackedTodos = metadata.UntrackedTodos
+		response.Warnings = metadata.Timing().Warnings
 	}
 
 	c.JSON(consts.StatusOK, response)
 }
 
-// HandleChat 处理SSE聊天会话
-func HandleChat(ctx context.Context, c *app.RequestContext) {
-	meetingID := c.Query("meeting_id")
-	sessionID := c.Query("session_id")
-	message := c.Query("message")
+// meetingJobFailed 判断会议处理任务是否已失败，任务不存在（例如服务重启后）时视为未失败
+func meetingJobFailed(jobID string) bool {
+	if jobID == "" {
+		return false
+	}
+	job, err := meetingQueue.Get(jobID)
+	return err == nil && job.Status == models.JobStatusFailed
+}
 
-	if meetingID == "" || sessionID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id and session_id are required"})
-		return
+// forceCreateMeeting 判断请求是否要求跳过重复会议检测，查询参数 force=true 或请求体 "force": true
+func forceCreateMeeting(c *app.RequestContext, reqBody map[string]interface{}) bool {
+	if force, _ := reqBody["force"].(bool); force {
+		return true
+	}
+	return c.Query("force") == "true"
+}
+
+// createMeetingType 读取用户指定的会议类型，查询参数 type 优先于请求体 "meeting_type"，都没有时返回空字符串
+func createMeetingType(c *app.RequestContext, reqBody map[string]interface{}) (string, error) {
+	value := c.Query("type")
+	if value == "" {
+		value, _ = reqBody["meeting_type"].(string)
 	}
+	return models.ParseMeetingType(value)
+}
 
-	if message == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "message is required"})
-		return
+// meetingJobPriority 确定会议处理任务的优先级：持有VIP访问令牌的调用方最高，其次是标记为紧急的请求。
+// VIP 只认配置的访问令牌，不信任客户端可以任意设置的用户ID请求头
+func meetingJobPriority(c *app.RequestContext, reqBody map[string]interface{}) int {
+	if token, ok := bearerToken(c); ok && models.IsVIPToken(token) {
+		return models.JobPriorityVIP
 	}
 
-	fmt.Printf("meetingID: %s, sessionID: %s, message: %s\n", meetingID, sessionID, message)
+	if urgent, _ := reqBody["urgent"].(bool); urgent {
+		return models.JobPriorityUrgent
+	}
+
+	return models.JobPriorityNormal
+}
 
-	// 读取对应会议文件内容
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
+// processMeetingJob 执行会议抽取任务：调用LLM抽取会议信息、保存会议文件并写入待办事项。
+// 会议文件先于待办落盘，保存失败时不会留下关联到不存在会议的待办
+func processMeetingJob(ctx context.Context, job *models.MeetingJob) error {
+	meetingID := job.MeetingID
+	documentText := job.DocumentText
 
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
-		return
+	// 后台任务不经过请求日志中间件，单独统计模型用量
+	ctx, usageTracker := models.WithUsageTracker(ctx)
+	defer func() {
+		usage := usageTracker.Snapshot()
+		RecordJobUsage("meeting_job", usage)
+		models.Logf(ctx, "会议 %s 处理任务模型用量: calls=%d prompt_tokens=%d completion_tokens=%d cost=%.4f\n",
+			meetingID, usage.Calls, usage.PromptTokens, usage.CompletionTokens, usage.Cost)
+	}()
+
+	if job.Reextract {
+		return reextractMeeting(ctx, job)
 	}
 
-	// 读取会议文件
-	data, err := os.ReadFile(filePath)
+	// 没有说话人标签的会议记录先标注说话人，参会人员从标注版本中抽取
+	speakerContent := models.DiarizeMeetingContent(ctx, documentText)
+	extractFrom := documentText
+	if speakerContent != "" {
+		extractFrom = speakerContent
+	}
+
+	// 调用LLM抽取会议信息
+	meetingInfo, err := models.ExtractMeetingInfo(ctx, extractFrom, job.MeetingType)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
-		return
+		return fmt.Errorf("无法分析会议内容: %v", err)
 	}
 
-	// 解析JSON内容
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法解析会议数据"})
-		return
+	// 新会议的待办都未完成，状态按即将写入的待办数计算
+	todos := meetingTodosFromMetadata(meetingID, *meetingInfo)
+	metadata := meetingInfo.ToMap()
+	metadata["status"] = models.ComputeMeetingStatus(len(todos), 0)
+	metadata["tags"] = job.Tags
+
+	// 构建完整的会议内容
+	meetingData := map[string]interface{}{
+		"metadata":    metadata,
+		"raw_content": documentText,
+	}
+	if speakerContent != "" {
+		meetingData["speaker_content"] = speakerContent
 	}
 
-	// 提取会议内容
-	var meetingContent string
+	// 将会议数据写入文件，SaveMeeting 先写临时文件再重命名，失败时不会留下半个会议文件
+	if err := models.SaveMeeting(meetingID, meetingData); err != nil {
+		return fmt.Errorf("无法保存会议文档: %v", err)
+	}
 
-	// 尝试从新格式中获取原始内容
-	if rawContent, ok := meetingData["raw_content"].(string); ok {
-		meetingContent = rawContent
-	} else {
-		// 尝试获取content字段
-		if content, ok := meetingData["content"].(string); ok {
-			meetingContent = content
+	// 会议落盘后再将待办事项添加到数据库，批量添加在同一事务中，失败时不会写入部分待办
+	if len(todos) > 0 {
+		if err := sqldb.BatchAddTodos(dbName, todos); err != nil {
+			// 这里我们只记录错误，不中断会议创建流程，按实际的待办重新计算会议状态
+			models.Logf(ctx, "添加会议待办事项失败: %v\n", err)
+			refreshMeetingStatus(meetingID)
 		} else {
-			// 如果没有找到适合的字段，将整个JSON作为内容
-			contentBytes, _ := json.MarshalIndent(meetingData, "", "  ")
-			meetingContent = string(contentBytes)
+			models.Logf(ctx, "成功添加 %d 个会议待办事项到数据库\n", len(todos))
 		}
 	}
 
-	// 提取会议元数据
-	meetingInfo := "会议信息:\n"
-
-	// 尝试从新格式中获取元数据
-	if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-		// 添加标题
-		if title, ok := metadata["title"].(string); ok && title != "" {
-			meetingInfo += "标题: " + title + "\n"
-		}
-
-		// 添加描述
-		if description, ok := metadata["description"].(string); ok && description != "" {
-			meetingInfo += "描述: " + description + "\n"
-		}
-
-		// 添加参会人员
-		if participants, ok := metadata["participants"].([]interface{}); ok && len(participants) > 0 {
-			meetingInfo += "参会人员: "
-			for i, p := range participants {
-				if i > 0 {
-					meetingInfo += ", "
-				}
-				if pStr, ok := p.(string); ok {
-					meetingInfo += pStr
-				}
-			}
-			meetingInfo += "\n"
-		}
+	// 敏感内容扫描，命中时告警合规负责人
+	models.CheckCompliance(meetingID, "meeting", documentText)
 
-		// 添加时间信息
-		if startTime, ok := metadata["start_time"].(string); ok && startTime != "" {
-			meetingInfo += "开始时间: " + startTime + "\n"
-		}
-		if endTime, ok := metadata["end_time"].(string); ok && endTime != "" {
-			meetingInfo += "结束时间: " + endTime + "\n"
-		}
+	// 计算语义搜索使用的会议向量，失败时在搜索时补算
+	if err := models.IndexMeetingEmbedding(ctx, meetingID, *meetingInfo); err != nil {
+		models.Logf(ctx, "计算会议 %s 向量失败: %v\n", meetingID, err)
+	}
 
-		// 添加摘要
-		if summary, ok := metadata["summary"].(string); ok && summary != "" {
-			meetingInfo += "摘要: " + summary + "\n"
-		}
+	return nil
+}
 
-		// 添加任务
-		// 添加参会人员
-		if todo_list, ok := metadata["todo_list"].([]interface{}); ok && len(todo_list) > 0 {
-			meetingInfo += "会议任务: "
-			for i, p := range todo_list {
-				if i > 0 {
-					meetingInfo += ", "
-				}
-				if pStr, ok := p.(string); ok {
-					meetingInfo += pStr
-				}
-			}
-			meetingInfo += "\n"
+// reextractMeeting 按会议的最新内容重新抽取会议信息并同步会议待办，保留已有待办上的手动修改和用户指定的会议类型
+func reextractMeeting(ctx context.Context, job *models.MeetingJob) error {
+	meetingType := job.MeetingType
+	if meetingData, err := models.LoadMeeting(job.MeetingID); err == nil {
+		if metadata, _ := models.GetMeetingMetadata(meetingData); metadata.MeetingTypeSource == models.MeetingTypeSourceUser {
+			meetingType = metadata.MeetingType
 		}
 	}
 
-	// 合并会议信息和内容
-	msg := meetingInfo + "\n会议内容:\n" + meetingContent
-
-	// Set SSE headers
-	c.Response.Header.Set("Content-Type", "text/event-stream")
-	c.Response.Header.Set("Cache-Control", "no-cache")
-	c.Response.Header.Set("Connection", "keep-alive")
+	speakerContent := models.DiarizeMeetingContent(ctx, job.DocumentText)
+	extractFrom := job.DocumentText
+	if speakerContent != "" {
+		extractFrom = speakerContent
+	}
 
-	// Create SSE stream
-	stream := sse.NewStream(c)
+	meetingInfo, err := models.ExtractMeetingInfo(ctx, extractFrom, meetingType)
+	if err != nil {
+		return fmt.Errorf("无法分析会议内容: %v", err)
+	}
 
-	// 使用会议信息和用户消息调用ChatMessage.Process进行流式处理
-	chatMsg := models.ChatMessage{
-		Data: msg,
+	metadata, err := models.ApplyReextractedInfo(job.MeetingID, job.DocumentText, speakerContent, meetingInfo)
+	if err != nil {
+		return fmt.Errorf("无法保存会议信息: %v", err)
 	}
-	if err := chatMsg.Process(message, stream, meetingID, sessionID); err != nil {
-		c.AbortWithStatus(consts.StatusInternalServerError)
-		return
+
+	diff, err := syncMeetingTodos(job.MeetingID, metadata, models.DefaultLocale)
+	if err != nil {
+		// 只记录错误，会议信息已更新，可以稍后通过待办同步接口重试
+		models.Logf(ctx, "同步会议 %s 待办事项失败: %v\n", job.MeetingID, err)
+		return nil
 	}
+	models.Logf(ctx, "会议 %s 重新抽取完成，新增 %d 个待办，删除 %d 个待办\n", job.MeetingID, len(diff.Added), len(diff.Removed))
+
+	return nil
 }
 
-// GetMeetingMermaid 处理获取会议流程图请求
-func GetMeetingMermaid(ctx context.Context, c *app.RequestContext) {
-	meetingID := c.Query("meeting_id")
-	if meetingID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id is required"})
-		return
-	}
-	fmt.Printf("处理会议流程图请求，meetingID: %s\n", meetingID)
+// AppendMeetingRequest 追加会议内容请求
+type AppendMeetingRequest struct {
+	Content string `json:"content"`
+	Extract bool   `json:"extract"` // 为 true 时立即重新抽取会议信息，否则只将会议标记为 stale
+}
 
-	// 读取对应会议文件内容
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
+// AppendMeeting 处理向进行中的会议追加内容请求，返回追加后的内容长度
+func AppendMeeting(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
 
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+	var req AppendMeetingRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
 
-	// 读取会议文件
-	data, err := os.ReadFile(filePath)
+	content, contentLength, err := models.AppendMeetingContent(meetingID, req.Content)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		switch {
+		case errors.Is(err, models.ErrMeetingNotFound):
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+		case errors.Is(err, models.ErrEmptyContent):
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		case errors.Is(err, models.ErrContentTooLarge):
+			c.JSON(consts.StatusRequestEntityTooLarge, utils.H{"error": err.Error()})
+		default:
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "追加会议内容失败: " + err.Error()})
+		}
 		return
 	}
 
-	// 解析JSON内容
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法解析会议数据"})
-		return
-	}
+	// 敏感内容扫描只针对新追加的内容
+	models.CheckCompliance(meetingID, "meeting", req.Content)
 
-	// 提取会议内容
-	var meetingContent string
+	response := utils.H{
+		"meeting_id":     meetingID,
+		"content_length": contentLength,
+		"stale":          true,
+	}
 
-	// 尝试从新格式中获取原始内容
-	if rawContent, ok := meetingData["raw_content"].(string); ok {
-		meetingContent = rawContent
-	} else {
-		// 尝试获取content字段
-		if content, ok := meetingData["content"].(string); ok {
-			meetingContent = content
+	if req.Extract || c.Query("extract") == "true" {
+		job := &models.MeetingJob{
+			MeetingID:    meetingID,
+			Priority:     meetingJobPriority(c, nil),
+			DocumentText: content,
+			Reextract:    true,
+		}
+		if err := meetingQueue.Enqueue(job); err != nil {
+			// 内容已经追加成功，抽取失败时保持 stale，客户端可以稍后重试
+			response["extract_error"] = err.Error()
 		} else {
-			// 如果没有找到适合的字段，将整个JSON作为内容
-			contentBytes, _ := json.MarshalIndent(meetingData, "", "  ")
-			meetingContent = string(contentBytes)
+			response["job_id"] = job.ID
 		}
 	}
 
-	// 调用ExtractMermaid生成流程图
-	mermaidCode, err := models.ExtractMermaid(ctx, meetingContent)
-	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "生成流程图失败: " + err.Error()})
-		return
-	}
-
-	// 构建响应
-	response := map[string]interface{}{
-		"mermaid_code": mermaidCode,
-	}
-
 	c.JSON(consts.StatusOK, response)
 }
 
-// HandleRolePlayChat 处理角色扮演聊天会话
-func HandleRolePlayChat(ctx context.Context, c *app.RequestContext) {
-	meetingID := c.Query("meeting_id")
-	sessionID := c.Query("session_id")
-	message := c.Query("message")
-	participantName := c.Query("participant")
+// RegenerateSummaryRequest 重新生成会议摘要的请求体，请求体可以省略
+type RegenerateSummaryRequest struct {
+	Length string `json:"length"` // 摘要长度: short、medium（默认）或 long，查询参数 length 优先
+}
 
-	if meetingID == "" || sessionID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id and session_id are required"})
-		return
-	}
+// RegenerateMeetingSummary 处理只重新生成会议摘要的请求，标题、参会人员和待办事项保持不变
+func RegenerateMeetingSummary(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
 
-	if message == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "message is required"})
+	var req RegenerateSummaryRequest
+	if len(c.Request.Body()) > 0 {
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+			return
+		}
+	}
+	if value := c.Query("length"); value != "" {
+		req.Length = value
+	}
+	length, err := models.ParseSummaryLength(req.Length)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
 
-	if participantName == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "participant is required"})
+	result, err := models.RegenerateMeetingSummary(ctx, meetingID, length)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": fmt.Sprintf("重新生成摘要失败: %v", err)})
 		return
 	}
 
-	fmt.Printf("角色扮演聊天: meetingID: %s, sessionID: %s, participant: %s, message: %s\n",
-		meetingID, sessionID, participantName, message)
-
-	// 读取对应会议文件内容
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
+	c.JSON(consts.StatusOK, result)
+}
 
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+// GetMeetingJob 处理查询会议处理任务状态请求
+func GetMeetingJob(ctx context.Context, c *app.RequestContext) {
+	job, err := meetingQueue.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": err.Error()})
 		return
 	}
 
-	// 读取会议文件
-	data, err := os.ReadFile(filePath)
+	c.JSON(consts.StatusOK, job)
+}
+
+// GetMeetingQueueStats 处理查询会议处理队列监控指标请求
+func GetMeetingQueueStats(ctx context.Context, c *app.RequestContext) {
+	c.JSON(consts.StatusOK, meetingQueue.Stats())
+}
+
+// ListMeetings 处理获取会议列表请求
+func ListMeetings(ctx context.Context, c *app.RequestContext) {
+	// 可选按闭环状态过滤，例如 status=open 只返回未闭环的会议
+	statusFilter := c.Query("status")
+	// 可选按标签过滤，忽略大小写
+	tagFilter := c.Query("tag")
+	// 可选按会议类型过滤，例如 type=standup
+	typeFilter, err := models.ParseMeetingType(c.Query("type"))
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
+	// 默认不返回已归档的会议，only_archived 只返回已归档的会议
+	onlyArchived := c.Query("only_archived") == "true"
+	includeArchived := onlyArchived || c.Query("include_archived") == "true"
 
-	// 解析JSON内容
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法解析会议数据"})
+	// 读取所有会议ID
+	meetingIDs, err := models.ListMeetingIDs()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议列表"})
 		return
 	}
 
-	// 提取会议内容
-	var meetingContent string
+	// 存储所有会议的切片
+	meetings := []models.Meeting{}
 
-	// 尝试从新格式中获取原始内容
-	if rawContent, ok := meetingData["raw_content"].(string); ok {
-		meetingContent = rawContent
-	} else {
-		// 尝试获取content字段
-		if content, ok := meetingData["content"].(string); ok {
-			meetingContent = content
-		} else {
-			// 如果没有找到适合的字段，将整个JSON作为内容
-			contentBytes, _ := json.MarshalIndent(meetingData, "", "  ")
-			meetingContent = string(contentBytes)
+	// 遍历所有会议
+	for _, meetingID := range meetingIDs {
+		meetingData, err := models.LoadMeeting(meetingID)
+		if err != nil {
+			// 记录错误但继续处理其他会议
+			models.Logf(ctx, "读取会议 %s 失败: %v\n", meetingID, err)
+			continue
 		}
-	}
+
+		// 获取元数据信息
+		var content map[string]interface{}
+
+		if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
+			// 使用LLM提取的元数据
+			content = metadata
+			// 确保原始内容也包含在内
+			if rawContent, ok := meetingData["raw_content"].(string); ok {
+				content["content"] = rawContent
+			}
+			if speakerContent, ok := meetingData["speaker_content"].(string); ok {
+				content["speaker_content"] = speakerContent
+			}
+		} else {
+			// 兼容旧格式，或者使用整个数据
+			content = meetingData
+		}
+
+		if statusFilter != "" {
+			if status, _ := content["status"].(string); status != statusFilter {
+				continue
+			}
+		}
+		if tagFilter != "" && !models.HasTag(content, tagFilter) {
+			continue
+		}
+		if typeFilter != "" {
+			if meetingType, _ := content["meeting_type"].(string); meetingType != typeFilter {
+				continue
+			}
+		}
+		if archived := models.IsMeetingArchived(content); (archived && !includeArchived) || (!archived && onlyArchived) {
+			continue
+		}
+
+		// 创建Meeting对象并添加到列表
+		meeting := models.Meeting{
+			ID:      meetingID,
+			Content: content,
+		}
+		meetings = append(meetings, meeting)
+	}
+
+	// 返回所有会议
+	response := models.GetMeetingsResponse{
+		Meetings: meetings,
+	}
+
+	// 会议列表被前端轮询，内容未变化时返回 304
+	jsonWithETag(c, response)
+}
+
+// SearchMeetings 处理会议搜索请求，q 为查询内容，semantic=true 时按语义相似度排序，
+// 未配置向量模型或向量接口不可用时退回关键词搜索
+func SearchMeetings(ctx context.Context, c *app.RequestContext) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "q is required"})
+		return
+	}
+
+	limit, err := optionalIntQuery(c, "limit", models.DefaultSearchLimit)
+	if err != nil || limit == 0 || limit > models.MaxSearchLimit {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("limit 必须在 1 到 %d 之间", models.MaxSearchLimit)})
+		return
+	}
+
+	response, err := models.SearchMeetings(ctx, query, models.MeetingSearchOptions{
+		Semantic:        c.Query("semantic") == "true",
+		Limit:           limit,
+		IncludeArchived: c.Query("include_archived") == "true",
+	})
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "搜索会议失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, response)
+}
+
+// GetMeetingSummary 处理获取会议摘要请求
+func GetMeetingSummary(ctx context.Context, c *app.RequestContext) {
+	var query MeetingQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID := query.MeetingID
+	models.Logf(ctx, "meetingID: %s\n", meetingID)
+
+	// 读取会议文件
+	meetingData, err := models.LoadMeeting(meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	// 指定模板时按模板章节生成结构化摘要
+	if templateName := c.Query("template"); templateName != "" {
+		tmpl, err := models.GetSummaryTemplate(templateName)
+		if err != nil {
+			c.JSON(consts.StatusNotFound, utils.H{"error": err.Error()})
+			return
+		}
+
+		structured, err := models.GetStructuredSummary(ctx, meetingID, tmpl)
+		if err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": fmt.Sprintf("生成结构化摘要失败: %v", err)})
+			return
+		}
+
+		jsonWithETag(c, structured)
+		return
+	}
+
+	// 从meetingData中提取摘要信息
+	var summary string
+
+	// 尝试从新格式中获取元数据
+	if metadata, ok := models.GetMeetingMetadata(meetingData); ok {
+		summary = metadata.Summary
+		if summary == "" {
+			summary = "无摘要信息"
+		}
+	} else {
+		// 兼容旧格式，或者使用整个数据
+		summary = "无法从会议数据中提取摘要信息"
+	}
+
+	// 构建响应
+	response := map[string]interface{}{
+		"summary": summary,
+	}
+
+	jsonWithETag(c, response)
+}
+
+// optionalIntQuery 读取可选的非负整数查询参数，未传时返回 defaultValue
+func optionalIntQuery(c *app.RequestContext, key string, defaultValue int) (int, error) {
+	valueStr := c.Query(key)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return value, nil
+}
+
+// optionalTemperatureQuery 解析可选的 temperature 查询参数，未传时返回 nil
+func optionalTemperatureQuery(c *app.RequestContext) (*float32, error) {
+	valueStr := c.Query("temperature")
+	if valueStr == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid temperature")
+	}
+	temperature := float32(value)
+	if err := models.ValidatePersonaTemperature(&temperature); err != nil {
+		return nil, err
+	}
+	return &temperature, nil
+}
+
+// ListSummaryTemplates 获取所有摘要模板
+func ListSummaryTemplates(ctx context.Context, c *app.RequestContext) {
+	templates, err := models.ListSummaryTemplates()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"templates": templates})
+}
+
+// SaveSummaryTemplate 新增或更新摘要模板
+func SaveSummaryTemplate(ctx context.Context, c *app.RequestContext) {
+	var tmpl models.SummaryTemplate
+	if err := c.BindJSON(&tmpl); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的请求参数"})
+		return
+	}
+
+	if err := models.SaveSummaryTemplate(tmpl); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := models.GetSummaryTemplate(strings.TrimSpace(tmpl.Name))
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, saved)
+}
+
+// HandleChat 处理SSE聊天会话
+func HandleChat(ctx context.Context, c *app.RequestContext) {
+	var query ChatQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID, sessionID, message := query.MeetingID, query.SessionID, query.Message
+
+	// 断线重连时 EventSource 会携带 Last-Event-ID，续传未收到的部分而不是重新提问
+	if lastEventID := string(c.GetHeader("Last-Event-ID")); lastEventID != "" {
+		resumeChat(ctx, c, lastEventID)
+		return
+	}
+
+	// 回答长度和引用数量，未传时使用默认值
+	maxAnswerLength, err := optionalIntQuery(c, "max_answer_length", 0)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "max_answer_length 参数无效"})
+		return
+	}
+	maxCitations, err := optionalIntQuery(c, "max_citations", -1)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "max_citations 参数无效"})
+		return
+	}
+
+	models.Logf(ctx, "meetingID: %s, sessionID: %s, message: %s\n", meetingID, sessionID, message)
+
+	// 读取会议文件
+	meetingData, err := models.LoadMeeting(meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	// 聊天消息的敏感内容扫描不阻塞回答，只扫描已存在会议的消息，避免为任意会议ID写入记录和推送告警
+	go models.CheckCompliance(meetingID, "chat", message)
+
+	// 会议信息和内容作为聊天背景
+	msg := buildChatMeetingContext(meetingData)
+
+	// Set SSE headers
+	c.Response.Header.Set("Content-Type", "text/event-stream")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+
+	// Create SSE stream
+	stream := sse.NewStream(c)
+
+	// 使用会议信息和用户消息调用ChatMessage.Process进行流式处理
+	chatMsg := models.ChatMessage{
+		Data:            msg,
+		MaxAnswerLength: maxAnswerLength,
+		MaxCitations:    maxCitations,
+		Cite:            c.Query("cite") == "true",
+		Transcript:      models.MeetingDataContent(meetingData),
+	}
+	// 生成期间定期发送心跳，避免慢速生成时连接被代理断开
+	publisher, stopHeartbeat := models.StartHeartbeat(ctx, stream, models.GetStreamHeartbeatInterval())
+	defer stopHeartbeat()
+
+	// 回答写入缓冲区，与客户端连接解耦：客户端断开后回答继续生成，重连时按 Last-Event-ID 续传。
+	// 生成沿用请求的 context，保留时长内没有客户端重连时由缓冲区取消
+	genCtx, cancel := context.WithCancel(ctx)
+	buffer := models.NewChatStreamBuffer(cancel)
+	go func() {
+		defer cancel()
+		defer buffer.Finish()
+		if err := chatMsg.Process(genCtx, message, buffer, meetingID, sessionID); err != nil {
+			models.Logf(ctx, "生成聊天回答失败: %v\n", err)
+		}
+	}()
+
+	if err := buffer.Follow(ctx, 0, publisher); err != nil {
+		models.Logf(ctx, "聊天连接已断开，回答继续生成以便续传: %v\n", err)
+	}
+}
+
+// resumeChat 按 Last-Event-ID 续传之前的聊天回答，从客户端收到的最后一个事件之后继续推送。
+// 回答已过期或ID无效时推送错误事件，客户端需要重新提问
+func resumeChat(ctx context.Context, c *app.RequestContext, lastEventID string) {
+	c.Response.Header.Set("Content-Type", "text/event-stream")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+	stream := sse.NewStream(c)
+
+	buffer, after, ok := models.FindChatStreamBuffer(lastEventID)
+	if !ok {
+		data, _ := json.Marshal(utils.H{"error": "回答已过期，无法续传，请重新提问", "resume_expired": true})
+		stream.Publish(models.NewStreamEvent(models.StreamEventError, data))
+		return
+	}
+	models.Logf(ctx, "续传聊天回答, Last-Event-ID: %s\n", lastEventID)
+
+	publisher, stopHeartbeat := models.StartHeartbeat(ctx, stream, models.GetStreamHeartbeatInterval())
+	defer stopHeartbeat()
+	if err := buffer.Follow(ctx, after, publisher); err != nil {
+		models.Logf(ctx, "聊天连接已断开: %v\n", err)
+	}
+}
+
+// buildChatMeetingContext 拼接会议元数据和原始内容，作为聊天的背景信息
+func buildChatMeetingContext(meetingData map[string]interface{}) string {
+	meetingContent := models.MeetingDataContent(meetingData)
 
 	// 提取会议元数据
-	meetingInfo := "会议信息:\n"
+	metadata, _ := models.GetMeetingMetadata(meetingData)
+	meetingInfo := metadata.Describe()
+	if todoItems := metadata.TodoItems(); len(todoItems) > 0 {
+		meetingInfo += "会议任务: " + strings.Join(todoItems, ", ") + "\n"
+	}
+
+	// 合并会议信息和内容
+	return meetingInfo + "\n会议内容:\n" + meetingContent
+}
+
+// GetMeetingMermaid 处理获取会议流程图请求
+func GetMeetingMermaid(ctx context.Context, c *app.RequestContext) {
+	var query MeetingQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID := query.MeetingID
+	models.Logf(ctx, "处理会议流程图请求，meetingID: %s\n", meetingID)
+
+	// 会议内容未变化时使用缓存的流程图，ETag 随缓存的流程图保持不变
+	mermaidCode, cached, err := models.GetCachedMeetingMermaid(ctx, meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "生成流程图失败: " + err.Error()})
+		return
+	}
+	models.Logf(ctx, "会议流程图来自缓存: %v\n", cached)
+
+	// 构建响应
+	response := map[string]interface{}{
+		"mermaid_code": mermaidCode,
+	}
+
+	jsonWithETag(c, response)
+}
+
+// 聊天历史分页的默认和最大条数
+const (
+	defaultChatHistoryLimit = 50
+	maxChatHistoryLimit     = 200
+)
+
+// GetChatHistory 处理获取会话聊天历史的请求，支持 offset/limit 分页
+func GetChatHistory(ctx context.Context, c *app.RequestContext) {
+	var query SessionQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID, sessionID := query.MeetingID, query.SessionID
+
+	offset, err := optionalIntQuery(c, "offset", 0)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+	limit, err := optionalIntQuery(c, "limit", defaultChatHistoryLimit)
+	if err != nil || limit == 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid limit"})
+		return
+	}
+	if limit > maxChatHistoryLimit {
+		limit = maxChatHistoryLimit
+	}
+
+	if _, err := models.LoadMeeting(meetingID); err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	messages, total := models.GetChatHistoryPage(meetingID, sessionID, offset, limit)
+
+	c.JSON(consts.StatusOK, utils.H{
+		"meeting_id": meetingID,
+		"session_id": sessionID,
+		"messages":   messages,
+		"total":      total,
+		"offset":     offset,
+		"limit":      limit,
+	})
+}
+
+// HandleRolePlayChat 处理角色扮演聊天会话
+func HandleRolePlayChat(ctx context.Context, c *app.RequestContext) {
+	var query RolePlayQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID, sessionID, message, participantName := query.MeetingID, query.SessionID, query.Message, query.Participant
+
+	temperature, err := optionalTemperatureQuery(c)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	models.Logf(ctx, "角色扮演聊天: meetingID: %s, sessionID: %s, participant: %s, message: %s\n",
+		meetingID, sessionID, participantName, message)
+
+	// 读取会议文件
+	meetingData, err := models.LoadMeeting(meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	// 只允许扮演会议中实际出席的参会者，避免模型编造不存在的人物
+	participants := models.GetMeetingParticipants(meetingData)
+	matchedName, ok := models.MatchParticipant(participants, participantName)
+	if !ok {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"error":        fmt.Sprintf("%s 不是该会议的参会者", participantName),
+			"participants": participants,
+		})
+		return
+	}
+	participantName = matchedName
+
+	// 提取会议内容，有说话人标注版本时使用标注版本，便于模型找到该参会者的发言
+	meetingContent := models.MeetingSpeakerContent(meetingData)
+
+	// 提取会议元数据
+	metadata, _ := models.GetMeetingMetadata(meetingData)
+	meetingInfo := metadata.Describe()
+
+	// 合并会议信息和内容
+	msg := meetingInfo + "\n会议内容:\n" + meetingContent
+
+	// Set SSE headers
+	c.Response.Header.Set("Content-Type", "text/event-stream")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+
+	// Create SSE stream
+	stream := sse.NewStream(c)
+
+	// 使用会议信息和用户消息调用RolePlayMessage.ProcessRolePlay进行流式处理
+	rolePlayMsg := models.RolePlayMessage{
+		Data:            msg,
+		ParticipantName: participantName,
+		Temperature:     temperature,
+	}
+	publisher, stopHeartbeat := models.StartHeartbeat(ctx, stream, models.GetStreamHeartbeatInterval())
+	defer stopHeartbeat()
+
+	if err := rolePlayMsg.ProcessRolePlay(ctx, message, publisher); err != nil {
+		c.AbortWithStatus(consts.StatusInternalServerError)
+		return
+	}
+}
+
+// MeetingOverviewResponse 会议详情页的汇总数据
+type MeetingOverviewResponse struct {
+	*models.MeetingOverview
+	TodoStats *TodoStatsResponse `json:"todo_stats"` // 查询失败时为 null，原因记录在 errors.todo_stats 中
+}
+
+// GetMeetingOverview 处理获取会议概览请求，一次返回摘要、参会人员、流程图、评分和待办统计，
+// 流程图和评分优先使用缓存，缺少的部分并发生成
+func GetMeetingOverview(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+
+	overview, err := models.GetMeetingOverview(ctx, meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	response := MeetingOverviewResponse{MeetingOverview: overview}
+	if stats, err := meetingTodoStats(meetingID); err != nil {
+		if overview.Errors == nil {
+			overview.Errors = make(map[string]string)
+		}
+		overview.Errors["todo_stats"] = "查询待办事项失败: " + err.Error()
+	} else {
+		response.TodoStats = &stats
+	}
+
+	c.JSON(consts.StatusOK, response)
+}
+
+// GetMeetingParticipants 处理获取会议参会人员请求，返回可用于角色扮演的参会者列表
+func GetMeetingParticipants(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+
+	meetingData, err := models.LoadMeeting(meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"meeting_id":   meetingID,
+		"participants": models.GetMeetingParticipants(meetingData),
+	})
+}
+
+// ListAllParticipants 处理列出所有会议参会人员的请求，支持按姓名前缀 q 筛选
+func ListAllParticipants(ctx context.Context, c *app.RequestContext) {
+	participants, err := models.ListAllParticipants(c.Query("q"))
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"participants": participants})
+}
+
+// GetMeetingParticipation 处理会议发言占比分析请求，按说话人标签统计每位参会者的发言次数和字数，不调用模型
+func GetMeetingParticipation(ctx context.Context, c *app.RequestContext) {
+	analysis, err := models.GetMeetingParticipation(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	c.JSON(consts.StatusOK, analysis)
+}
+
+// GetMeetingRaw 以纯文本下载会议的原始内容，文件名为 <会议ID>.txt；会议没有原始内容时返回空文本
+func GetMeetingRaw(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+	rawContent, err := models.GetMeetingRawContent(meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+
+	c.Response.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.txt"`, meetingID))
+	c.Data(consts.StatusOK, "text/plain; charset=utf-8", []byte(rawContent))
+}
+
+// UpdateMeetingTagsRequest 更新会议标签请求
+type UpdateMeetingTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// UpdateMeetingTags 处理替换会议标签请求
+func UpdateMeetingTags(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+
+	var req UpdateMeetingTagsRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	normalized, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	tags, err := models.SetMeetingTags(meetingID, normalized)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "更新会议标签失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"meeting_id": meetingID,
+		"tags":       tags,
+	})
+}
 
-	// 尝试从新格式中获取元数据
-	if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-		// 添加标题
-		if title, ok := metadata["title"].(string); ok && title != "" {
-			meetingInfo += "标题: " + title + "\n"
-		}
-
-		// 添加描述
-		if description, ok := metadata["description"].(string); ok && description != "" {
-			meetingInfo += "描述: " + description + "\n"
-		}
-
-		// 添加参会人员
-		if participants, ok := metadata["participants"].([]interface{}); ok && len(participants) > 0 {
-			meetingInfo += "参会人员: "
-			for i, p := range participants {
-				if i > 0 {
-					meetingInfo += ", "
-				}
-				if pStr, ok := p.(string); ok {
-					meetingInfo += pStr
-				}
-			}
-			meetingInfo += "\n"
-		}
+// PatchMeeting 处理部分更新会议元数据请求，只修改请求中提供的字段，用于修正抽取结果中的个别错误
+func PatchMeeting(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
 
-		// 添加时间信息
-		if startTime, ok := metadata["start_time"].(string); ok && startTime != "" {
-			meetingInfo += "开始时间: " + startTime + "\n"
-		}
-		if endTime, ok := metadata["end_time"].(string); ok && endTime != "" {
-			meetingInfo += "结束时间: " + endTime + "\n"
-		}
+	patch, err := models.ParseMeetingMetadataPatch(c.Request.Body())
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
 
-		// 添加摘要
-		if summary, ok := metadata["summary"].(string); ok && summary != "" {
-			meetingInfo += "摘要: " + summary + "\n"
+	metadata, err := models.PatchMeetingMetadata(meetingID, patch)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
 		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "更新会议信息失败: " + err.Error()})
+		return
 	}
 
-	// 合并会议信息和内容
-	msg := meetingInfo + "\n会议内容:\n" + meetingContent
+	c.JSON(consts.StatusOK, utils.H{
+		"meeting_id": meetingID,
+		"metadata":   metadata,
+	})
+}
 
-	// Set SSE headers
-	c.Response.Header.Set("Content-Type", "text/event-stream")
-	c.Response.Header.Set("Cache-Control", "no-cache")
-	c.Response.Header.Set("Connection", "keep-alive")
+// ArchiveMeeting 处理归档会议请求，归档后的会议默认不出现在会议列表中，仍可按ID访问
+func ArchiveMeeting(ctx context.Context, c *app.RequestContext) {
+	setMeetingArchived(c, true)
+}
 
-	// Create SSE stream
-	stream := sse.NewStream(c)
+// UnarchiveMeeting 处理取消归档会议请求
+func UnarchiveMeeting(ctx context.Context, c *app.RequestContext) {
+	setMeetingArchived(c, false)
+}
 
-	// 使用会议信息和用户消息调用RolePlayMessage.ProcessRolePlay进行流式处理
-	rolePlayMsg := models.RolePlayMessage{
-		Data:            msg,
-		ParticipantName: participantName,
+// setMeetingArchived 更新会议的归档状态并返回更新后的状态，重复归档或取消归档不报错
+func setMeetingArchived(c *app.RequestContext, archived bool) {
+	meetingID := c.Param("id")
+
+	archivedAt, err := models.SetMeetingArchived(meetingID, archived)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "更新会议归档状态失败: " + err.Error()})
+		return
 	}
-	if err := rolePlayMsg.ProcessRolePlay(message, stream); err != nil {
-		c.AbortWithStatus(consts.StatusInternalServerError)
+
+	response := utils.H{
+		"meeting_id": meetingID,
+		"archived":   archived,
+	}
+	if archivedAt != "" {
+		response["archived_at"] = archivedAt
+	}
+	c.JSON(consts.StatusOK, response)
+}
+
+// ListMeetingTags 处理获取所有会议标签请求，供前端提供标签选择
+func ListMeetingTags(ctx context.Context, c *app.RequestContext) {
+	tags, err := models.ListAllTags()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议标签"})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"tags": tags})
+}
+
+// GetMeetingTypeStats 处理获取会议类型分布请求，include_archived=true 时包含已归档的会议
+func GetMeetingTypeStats(ctx context.Context, c *app.RequestContext) {
+	stats, err := models.GetMeetingTypeStats(c.Query("include_archived") == "true", models.LocaleFromContext(ctx))
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法统计会议类型"})
 		return
 	}
+
+	c.JSON(consts.StatusOK, stats)
 }
 
 // GetMeetingScore 处理获取会议评分请求
 func GetMeetingScore(ctx context.Context, c *app.RequestContext) {
-	meetingID := c.Query("meeting_id")
-	if meetingID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id is required"})
+	var query MeetingQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID := query.MeetingID
+	models.Logf(ctx, "处理会议评分请求，meetingID: %s\n", meetingID)
+
+	// 会议内容未变化时使用缓存的评分，按会议类型选择评分侧重，ETag 随缓存的评分保持不变
+	meetingScore, cached, err := models.GetCachedMeetingScore(ctx, meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "评估会议失败: " + err.Error()})
 		return
 	}
-	fmt.Printf("处理会议评分请求，meetingID: %s\n", meetingID)
+	models.Logf(ctx, "会议评分来自缓存: %v\n", cached)
 
-	// 读取对应会议文件内容
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
+	// 返回评分结果
+	jsonWithETag(c, meetingScore)
+}
 
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+// GetMeetingScoreStream 处理流式获取会议评分请求，边生成边推送评估内容和各指标得分，最后推送完整评分
+func GetMeetingScoreStream(ctx context.Context, c *app.RequestContext) {
+	var query MeetingQuery
+	if !bindQuery(c, &query) {
 		return
 	}
+	meetingID := query.MeetingID
+	models.Logf(ctx, "处理流式会议评分请求，meetingID: %s\n", meetingID)
 
-	// 读取会议文件
-	data, err := os.ReadFile(filePath)
+	meetingData, err := models.LoadMeeting(meetingID)
 	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
 		return
 	}
 
-	// 解析JSON内容
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法解析会议数据"})
+	// Set SSE headers
+	c.Response.Header.Set("Content-Type", "text/event-stream")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+
+	stream := sse.NewStream(c)
+
+	// 生成期间定期发送心跳，避免慢速生成时连接被代理断开
+	publisher, stopHeartbeat := models.StartHeartbeat(ctx, stream, models.GetStreamHeartbeatInterval())
+	defer stopHeartbeat()
+
+	metadata, _ := models.GetMeetingMetadata(meetingData)
+	if err := models.StreamEvaluateMeeting(ctx, buildScoreContent(meetingData), metadata.MeetingType, publisher); err != nil {
+		c.AbortWithStatus(consts.StatusInternalServerError)
 		return
 	}
+}
 
-	// 提取会议内容
-	var meetingContent string
+// buildScoreContent 拼接会议元数据、原始内容和发言统计，作为评分的输入
+func buildScoreContent(meetingData map[string]interface{}) string {
+	meetingContent := models.MeetingDataContent(meetingData)
 
-	// 尝试从新格式中获取原始内容
-	if rawContent, ok := meetingData["raw_content"].(string); ok {
-		meetingContent = rawContent
-	} else {
-		// 尝试获取content字段
-		if content, ok := meetingData["content"].(string); ok {
-			meetingContent = content
-		} else {
-			// 如果没有找到适合的字段，将整个JSON作为内容
-			contentBytes, _ := json.MarshalIndent(meetingData, "", "  ")
-			meetingContent = string(contentBytes)
-		}
+	// 获取会议元数据并添加到内容中，提供更多上下文
+	metadata, _ := models.GetMeetingMetadata(meetingData)
+	meetingInfo := metadata.Describe()
+
+	// 合并会议信息和内容，能按说话人统计发言时附上统计供评价参与度参考
+	return meetingInfo + "\n会议内容:\n" + meetingContent + models.ParticipationScoreNote(meetingData)
+}
+
+// CompareScoresRequest 会议评分对比请求
+type CompareScoresRequest struct {
+	MeetingIDs []string `json:"meeting_ids"`
+}
+
+// CompareMeetingScores 处理多个会议评分对比请求，不存在或评估失败的会议单独列出
+func CompareMeetingScores(ctx context.Context, c *app.RequestContext) {
+	var req CompareScoresRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的请求数据"})
+		return
+	}
+	if len(req.MeetingIDs) == 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_ids不能为空"})
+		return
+	}
+	if len(req.MeetingIDs) > models.MaxCompareMeetings {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("单次最多对比%d个会议", models.MaxCompareMeetings)})
+		return
 	}
 
-	// 获取会议元数据并添加到内容中，提供更多上下文
-	meetingInfo := "会议信息:\n"
+	models.Logf(ctx, "处理会议评分对比请求，会议数: %d\n", len(req.MeetingIDs))
 
-	// 尝试从新格式中获取元数据
-	if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-		// 添加标题
-		if title, ok := metadata["title"].(string); ok && title != "" {
-			meetingInfo += "标题: " + title + "\n"
-		}
-
-		// 添加描述
-		if description, ok := metadata["description"].(string); ok && description != "" {
-			meetingInfo += "描述: " + description + "\n"
-		}
-
-		// 添加参会人员
-		if participants, ok := metadata["participants"].([]interface{}); ok && len(participants) > 0 {
-			meetingInfo += "参会人员: "
-			for i, p := range participants {
-				if i > 0 {
-					meetingInfo += ", "
-				}
-				if pStr, ok := p.(string); ok {
-					meetingInfo += pStr
-				}
-			}
-			meetingInfo += "\n"
-		}
+	c.JSON(consts.StatusOK, models.CompareMeetingScores(ctx, req.MeetingIDs))
+}
 
-		// 添加摘要
-		if summary, ok := metadata["summary"].(string); ok && summary != "" {
-			meetingInfo += "摘要: " + summary + "\n"
-		}
+// PushMeetingReport 处理推送会议报告到飞书或企业微信的请求
+func PushMeetingReport(ctx context.Context, c *app.RequestContext) {
+	// 获取会议ID
+	var query MeetingQuery
+	if !bindQuery(c, &query) {
+		return
 	}
+	meetingID := query.MeetingID
 
-	// 合并会议信息和内容
-	fullContent := meetingInfo + "\n会议内容:\n" + meetingContent
+	// 获取推送渠道，默认推送到飞书
+	notifier, err := models.NewNotifier(c.Query("channel"))
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
 
-	// 调用EvaluateMeeting评估会议
-	meetingScore, err := models.EvaluateMeeting(ctx, fullContent)
+	// 飞书卡片颜色，其他渠道忽略该参数
+	color, err := models.NormalizeFeiShuCardColor(c.Query("color"))
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "评估会议失败: " + err.Error()})
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
 
-	// 返回评分结果
-	c.JSON(consts.StatusOK, meetingScore)
-}
+	// 预览模式只返回将要发送的消息体，不实际推送，也不为生成评分调用模型
+	dryRun := c.Query("dry_run") == "true"
+	report, _, ok := buildMeetingReport(ctx, c, meetingID, models.ReportOptions{Color: color, CachedScoreOnly: dryRun})
+	if !ok {
+		return
+	}
 
-// PushMeetingReport 处理推送会议报告到飞书的请求
-func PushMeetingReport(ctx context.Context, c *app.RequestContext) {
-	// 获取会议ID
-	meetingID := c.Query("meeting_id")
-	if meetingID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id是必需的"})
+	if dryRun {
+		c.JSON(consts.StatusOK, utils.H{
+			"dry_run":  true,
+			"channel":  notifier.Name(),
+			"messages": notifier.BuildReportMessages(report),
+		})
 		return
 	}
 
-	fmt.Printf("推送会议报告到飞书, meetingID: %s\n", meetingID)
+	models.Logf(ctx, "推送会议报告到%s, meetingID: %s\n", notifier.Name(), meetingID)
 
-	// 推送会议报告到飞书
-	if err := models.PushMeetingReportToFeiShu(meetingID); err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": fmt.Sprintf("推送会议报告失败: %v", err)})
+	// 推送会议报告
+	if err := notifier.SendReport(report); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": fmt.Sprintf("推送会议报告到%s失败: %v", notifier.Name(), err)})
 		return
 	}
 
 	// 返回成功响应
 	c.JSON(consts.StatusOK, utils.H{
-		"message": "会议报告已成功推送到飞书",
+		"message": "会议报告已成功推送到" + notifier.Name(),
 	})
 }
 
-// HandleMultiRoleplayMeeting 处理多角色扮演会议请求
-func HandleMultiRoleplayMeeting(ctx context.Context, c *app.RequestContext) {
-	// 获取请求参数
-	var reqBody models.MultiRoleplayRequest
-	if err := c.BindJSON(&reqBody); err != nil {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的请求体: " + err.Error()})
+// MeetingReportResponse 会议报告接口的响应，携带待办状态时附带数据库中的待办明细
+type MeetingReportResponse struct {
+	*models.MeetingReport
+	Todos []TodoResponse `json:"todos,omitempty"`
+}
+
+// GetMeetingReport 处理获取结构化会议报告的请求
+func GetMeetingReport(ctx context.Context, c *app.RequestContext) {
+	report, todos, ok := buildMeetingReport(ctx, c, c.Param("id"), models.ReportOptions{})
+	if !ok {
 		return
 	}
 
-	// 参数验证
-	if reqBody.MeetingID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id 是必需的"})
-		return
+	response := MeetingReportResponse{MeetingReport: report}
+	if todos != nil {
+		response.Todos = make([]TodoResponse, 0, len(todos))
+		locale := models.LocaleFromContext(ctx)
+		for _, todo := range todos {
+			response.Todos = append(response.Todos, toTodoResponse(todo, locale))
+		}
 	}
 
-	if reqBody.Host == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "host 是必需的"})
-		return
+	c.JSON(consts.StatusOK, response)
+}
+
+// buildMeetingReport 按 opts 和查询参数 include_score、include_todo_status 创建会议报告，
+// 携带待办状态时 todo_list 改为展示数据库中的当前状态，并返回对应的待办；
+// 失败时已写入错误响应，返回 false
+func buildMeetingReport(ctx context.Context, c *app.RequestContext, meetingID string, opts models.ReportOptions) (*models.MeetingReport, []*sqldb.Todo, bool) {
+	opts.IncludeScore = c.Query("include_score") == "true"
+	report, err := models.CreateMeetingReportWithOptions(ctx, meetingID, opts)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return nil, nil, false
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": fmt.Sprintf("创建会议报告失败: %v", err)})
+		return nil, nil, false
+	}
+
+	if c.Query("include_todo_status") != "true" {
+		return report, nil, true
+	}
+
+	// 待办事项使用数据库中的当前状态替代会议中抽取的静态列表
+	todos, err := sqldb.GetTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "查询待办事项失败: " + err.Error()})
+		return nil, nil, false
+	}
+
+	report.TodoList = make([]string, 0, len(todos))
+	for _, todo := range todos {
+		item := models.ExtractedTodo{Task: todo.Title, Assignee: todo.AssignedTo}
+		if !todo.DueDate.IsZero() {
+			item.DueDate = todo.DueDate.Format("2006-01-02")
+		}
+		report.TodoList = append(report.TodoList, fmt.Sprintf("【%s】%s", sqldb.TodoStatusLabel(todo.Status, models.LocaleFromContext(ctx)), models.FormatTodoItem(item)))
 	}
 
-	if len(reqBody.Specialists) == 0 {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "至少需要一名专家参与者"})
+	return report, todos, true
+}
+
+// HandleMultiRoleplayMeeting 处理多角色扮演会议请求
+func HandleMultiRoleplayMeeting(ctx context.Context, c *app.RequestContext) {
+	// 获取请求参数
+	var reqBody models.MultiRoleplayRequest
+	if err := c.BindJSON(&reqBody); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的请求体: " + err.Error()})
 		return
 	}
 
-	if reqBody.Rounds <= 0 {
-		reqBody.Rounds = 3 // 默认进行3轮讨论
+	// 参数验证，限制轮数和专家人数以控制模型调用次数
+	if err := models.NormalizeMultiRoleplayRequest(&reqBody); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
 	}
 
 	// 执行多角色扮演会议
-	response, err := models.PerformMultiRoleplayMeeting(&reqBody)
+	response, err := models.PerformMultiRoleplayMeeting(ctx, &reqBody)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "执行多角色扮演会议失败: " + err.Error()})
 		return
@@ -767,26 +1526,12 @@ func HandleStreamMultiRoleplayMeeting(ctx context.Context, c *app.RequestContext
 		return
 	}
 
-	// 参数验证
-	if reqBody.MeetingID == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id 是必需的"})
-		return
-	}
-
-	if reqBody.Host == "" {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "host 是必需的"})
-		return
-	}
-
-	if len(reqBody.Specialists) == 0 {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "至少需要一名专家参与者"})
+	// 参数验证，限制轮数和专家人数以控制模型调用次数
+	if err := models.NormalizeMultiRoleplayRequest(&reqBody); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
 
-	if reqBody.Rounds <= 0 {
-		reqBody.Rounds = 3 // 默认进行3轮讨论
-	}
-
 	// 设置SSE响应头
 	c.Response.Header.Set("Content-Type", "text/event-stream")
 	c.Response.Header.Set("Cache-Control", "no-cache")
@@ -795,9 +1540,209 @@ func HandleStreamMultiRoleplayMeeting(ctx context.Context, c *app.RequestContext
 	// 创建SSE流
 	stream := sse.NewStream(c)
 
+	// 多轮讨论耗时较长，期间定期发送心跳保持连接
+	publisher, stopHeartbeat := models.StartHeartbeat(ctx, stream, models.GetStreamHeartbeatInterval())
+	defer stopHeartbeat()
+
 	// 流式执行多角色扮演会议
-	if err := models.StreamMultiRoleplayMeeting(ctx, &reqBody, stream); err != nil {
+	if err := models.StreamMultiRoleplayMeeting(ctx, &reqBody, publisher); err != nil {
 		c.AbortWithStatus(consts.StatusInternalServerError)
 		return
 	}
 }
+
+// HandleAskPanel 处理向多位参会者提出同一个问题的请求，每位参会者以自己的身份回答一次
+func HandleAskPanel(ctx context.Context, c *app.RequestContext) {
+	var reqBody models.PanelAskRequest
+	if err := c.BindJSON(&reqBody); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的请求体: " + err.Error()})
+		return
+	}
+
+	// 参数验证，限制参会者人数以控制模型调用次数
+	if err := models.NormalizePanelAskRequest(&reqBody); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	models.Logf(ctx, "集体提问: meetingID: %s, 参会者: %v, 问题: %s\n", reqBody.MeetingID, reqBody.Participants, reqBody.Question)
+
+	response, err := models.AskPanel(ctx, &reqBody)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "集体提问失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, response)
+}
+
+// GetMultiRoleplayHistory 处理获取多角色扮演历史讨论请求。
+// 指定 id 时返回该次讨论的完整记录（summary_only=true 时不含发言记录），否则返回 meeting_id 对应会议的讨论列表
+func GetMultiRoleplayHistory(ctx context.Context, c *app.RequestContext) {
+	if id := c.Query("id"); id != "" {
+		discussion, err := models.GetRoleplayDiscussion(id)
+		if err != nil {
+			if errors.Is(err, models.ErrDiscussionNotFound) {
+				c.JSON(consts.StatusNotFound, utils.H{"error": "讨论记录不存在"})
+				return
+			}
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+			return
+		}
+		if c.Query("summary_only") == "true" {
+			discussion.Messages = nil
+		}
+		c.JSON(consts.StatusOK, discussion)
+		return
+	}
+
+	meetingID := c.Query("meeting_id")
+	if meetingID == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id 或 id 是必需的"})
+		return
+	}
+
+	discussions, err := models.ListRoleplayDiscussions(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"discussions": discussions})
+}
+
+// 讨论发言记录分页的默认和最大条数
+const (
+	defaultDiscussionMessagesLimit = 20
+	maxDiscussionMessagesLimit     = 100
+)
+
+// GetMultiRoleplayMessages 处理分页获取历史讨论发言记录的请求，支持按 round 筛选轮次和 offset/limit 分页，
+// 响应中的 rounds 为每轮讨论在完整发言记录中的位置；summary_only=true 时只返回讨论总结
+func GetMultiRoleplayMessages(ctx context.Context, c *app.RequestContext) {
+	discussion, err := models.GetRoleplayDiscussion(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, models.ErrDiscussionNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "讨论记录不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("summary_only") == "true" {
+		c.JSON(consts.StatusOK, utils.H{
+			"id":         discussion.ID,
+			"meeting_id": discussion.MeetingID,
+			"topic":      discussion.Topic,
+			"summary":    discussion.Summary,
+		})
+		return
+	}
+
+	round, err := optionalIntQuery(c, "round", 0)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+	if round > discussion.Rounds {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("round 不能超过讨论轮数 %d", discussion.Rounds)})
+		return
+	}
+	offset, err := optionalIntQuery(c, "offset", 0)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+	limit, err := optionalIntQuery(c, "limit", defaultDiscussionMessagesLimit)
+	if err != nil || limit == 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid limit"})
+		return
+	}
+	if limit > maxDiscussionMessagesLimit {
+		limit = maxDiscussionMessagesLimit
+	}
+
+	messages, total := discussion.MessagesPage(round, offset, limit)
+
+	response := utils.H{
+		"id":         discussion.ID,
+		"meeting_id": discussion.MeetingID,
+		"topic":      discussion.Topic,
+		"summary":    discussion.Summary,
+		"rounds":     discussion.RoundBoundaries(),
+		"messages":   messages,
+		"total":      total,
+		"offset":     offset,
+		"limit":      limit,
+	}
+	if round > 0 {
+		response["round"] = round
+	}
+	c.JSON(consts.StatusOK, response)
+}
+
+// GetMeetingCompliance 处理获取会议合规命中记录请求
+func GetMeetingCompliance(ctx context.Context, c *app.RequestContext) {
+	records, err := models.GetComplianceRecords(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"records": records})
+}
+
+// GetMeetingRisks 处理获取会议风险预警请求
+func GetMeetingRisks(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+	refresh := c.Query("refresh") == "true"
+	models.Logf(ctx, "处理会议风险识别请求，meetingID: %s, refresh: %v\n", meetingID, refresh)
+
+	report, err := models.GetMeetingRisks(ctx, meetingID, refresh)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "识别会议风险失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, report)
+}
+
+// GetMeetingMinutes 处理获取正式会议纪要请求，refresh=true 时忽略缓存重新生成
+func GetMeetingMinutes(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+	refresh := c.Query("refresh") == "true"
+	models.Logf(ctx, "处理会议纪要请求，meetingID: %s, refresh: %v\n", meetingID, refresh)
+
+	minutes, err := models.GetMeetingMinutes(ctx, meetingID, refresh)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "生成会议纪要失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, minutes)
+}
+
+// GetPrompts 处理获取当前生效提示模板的请求
+func GetPrompts(ctx context.Context, c *app.RequestContext) {
+	c.JSON(consts.StatusOK, utils.H{
+		"dir":     models.GetPromptsDir(),
+		"prompts": models.GetResolvedPrompts(),
+	})
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"meetingagent/models"
+	sqldb "meetingagent/sql"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+func TestMain(m *testing.M) {
+	// 测试使用临时目录中的数据库和会议目录，不在源码目录下创建文件
+	dir, err := os.MkdirTemp("", "meetingagent-handlers")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	os.Setenv("MEETINGS_DIR", filepath.Join(dir, "meetings"))
+	os.Setenv("DATA_DIR", dir)
+	os.Setenv("TODO_DB", filepath.Join(dir, "todo.db"))
+	if err := InitDatabase(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// fixedLLM 总是返回同一个输出的模型
+type fixedLLM struct {
+	output string
+}
+
+func (m fixedLLM) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return schema.AssistantMessage(m.output, nil), nil
+}
+
+func (m fixedLLM) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage(m.output, nil)}), nil
+}
+
+// failingMeetingStore 保存总是失败的会议存储
+type failingMeetingStore struct{}
+
+var errMeetingStoreUnavailable = errors.New("存储不可用")
+
+func (failingMeetingStore) Save(meetingID string, data []byte) error {
+	return errMeetingStoreUnavailable
+}
+
+func (failingMeetingStore) Load(meetingID string) ([]byte, error) {
+	return nil, models.ErrMeetingNotFound
+}
+
+func (failingMeetingStore) List() ([]string, error) {
+	return []string{}, nil
+}
+
+func (failingMeetingStore) Delete(meetingID string) error {
+	return models.ErrMeetingNotFound
+}
+
+const testMeetingInfoJSON = `{
+  "title": "预算评审会",
+  "summary": "讨论了下季度预算",
+  "participants": ["张三", "李四"],
+  "start_time": "",
+  "end_time": "",
+  "todo_list": [
+    {"task": "整理预算表", "assignee": "张三", "due_date": ""},
+    {"task": "确认采购清单", "assignee": "李四", "due_date": ""}
+  ]
+}`
+
+func TestProcessMeetingJobSaveFailureLeavesNoTodos(t *testing.T) {
+	factory := models.NewModelFactory(func(ctx context.Context, spec models.ModelSpec) (models.LLM, error) {
+		return fixedLLM{output: testMeetingInfoJSON}, nil
+	})
+	ctx := models.WithModelFactory(context.Background(), factory)
+	newJob := func(meetingID string) *models.MeetingJob {
+		return &models.MeetingJob{
+			MeetingID:    meetingID,
+			DocumentText: "张三: 我们看一下预算\n李四: 我来确认采购清单",
+		}
+	}
+
+	// 会议文件写入失败时任务失败，不写入任何待办
+	models.SetMeetingStore(failingMeetingStore{})
+	defer models.SetMeetingStore(nil)
+	const failedID = "meeting_save_failure_test"
+	err := processMeetingJob(ctx, newJob(failedID))
+	if err == nil || !strings.Contains(err.Error(), errMeetingStoreUnavailable.Error()) {
+		t.Fatalf("错误 = %v，期望会议保存失败", err)
+	}
+	todos, err := sqldb.GetTodosByMeetingID(dbName, failedID)
+	if err != nil {
+		t.Fatalf("查询待办失败: %v", err)
+	}
+	if len(todos) != 0 {
+		t.Errorf("会议保存失败后留下了 %d 个待办", len(todos))
+	}
+
+	// 对照：会议保存成功时写入抽取的待办
+	models.SetMeetingStore(nil)
+	const savedID = "meeting_save_success_test"
+	if err := processMeetingJob(ctx, newJob(savedID)); err != nil {
+		t.Fatalf("处理会议失败: %v", err)
+	}
+	todos, err = sqldb.GetTodosByMeetingID(dbName, savedID)
+	if err != nil {
+		t.Fatalf("查询待办失败: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Errorf("会议保存成功后有 %d 个待办，期望 2", len(todos))
+	}
+}
+
+// withTestConfig 使用临时配置文件运行测试，结束后恢复为只启用模拟模型的配置
+func withTestConfig(t *testing.T, configJSON string) {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("CONFIG_PATH", configPath)
+	writeConfig := func(data string) {
+		if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+			t.Fatalf("写入配置文件失败: %v", err)
+		}
+		if _, err := models.ReloadConfig(); err != nil {
+			t.Fatalf("加载配置失败: %v", err)
+		}
+	}
+	writeConfig(configJSON)
+	t.Cleanup(func() { writeConfig(`{"provider": "mock"}`) })
+}
+
+func TestHandleChatSkipsComplianceForUnknownMeeting(t *testing.T) {
+	withTestConfig(t, `{
+  "provider": "mock",
+  "compliance": {"enabled": true, "rules": [{"name": "泄密", "level": "critical", "keywords": ["机密"]}]}
+}`)
+	h := route.NewEngine(config.NewOptions(nil))
+	h.GET("/chat", HandleChat)
+	chat := func(meetingID string) int {
+		query := url.Values{"meeting_id": {meetingID}, "session_id": {"s1"}, "message": {"这份机密文件发给谁"}}
+		return ut.PerformRequest(h, http.MethodGet, "/chat?"+query.Encode(), nil).Result().StatusCode()
+	}
+	waitRecords := func(meetingID string, timeout time.Duration) []models.ComplianceRecord {
+		deadline := time.Now().Add(timeout)
+		for {
+			records, err := models.GetComplianceRecords(meetingID)
+			if err != nil {
+				t.Fatalf("读取合规记录失败: %v", err)
+			}
+			if len(records) > 0 || time.Now().After(deadline) {
+				return records
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// 对照：该配置下扫描到敏感内容会写入合规记录
+	const knownID = "meeting_chat_compliance_test"
+	models.CheckCompliance(knownID, "chat", "这份机密文件发给谁")
+	if records := waitRecords(knownID, 0); len(records) != 1 {
+		t.Fatalf("合规记录 = %+v，期望 1 条", records)
+	}
+
+	// 不存在的会议返回 404，不扫描也不写入合规记录
+	const unknownID = "meeting_chat_compliance_unknown"
+	if status := chat(unknownID); status != http.StatusNotFound {
+		t.Fatalf("聊天状态码 = %d，期望 %d", status, http.StatusNotFound)
+	}
+	if records := waitRecords(unknownID, 200*time.Millisecond); len(records) != 0 {
+		t.Errorf("不存在的会议写入了合规记录: %+v", records)
+	}
+}
+
+func TestMeetingJobPriorityRequiresVIPToken(t *testing.T) {
+	withTestConfig(t, `{"provider": "mock", "queue": {"vip_tokens": ["vip-secret"]}}`)
+	priority := func(header, value string, reqBody map[string]interface{}) int {
+		c := app.NewContext(0)
+		if header != "" {
+			c.Request.Header.Set(header, value)
+		}
+		return meetingJobPriority(c, reqBody)
+	}
+
+	if got := priority("Authorization", "Bearer vip-secret", nil); got != models.JobPriorityVIP {
+		t.Errorf("持有VIP令牌的优先级 = %d，期望 %d", got, models.JobPriorityVIP)
+	}
+	if got := priority("Authorization", "Bearer guessed", nil); got != models.JobPriorityNormal {
+		t.Errorf("无效令牌的优先级 = %d，期望 %d", got, models.JobPriorityNormal)
+	}
+	// 客户端自行设置的用户ID请求头不能提升优先级
+	if got := priority("X-User-ID", "vip-secret", nil); got != models.JobPriorityNormal {
+		t.Errorf("只设置 X-User-ID 的优先级 = %d，期望 %d", got, models.JobPriorityNormal)
+	}
+	if got := priority("", "", map[string]interface{}{"urgent": true}); got != models.JobPriorityUrgent {
+		t.Errorf("紧急请求的优先级 = %d，期望 %d", got, models.JobPriorityUrgent)
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"meetingagent/models"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// endpointMetrics 单个接口的累计指标
+type endpointMetrics struct {
+	requests map[int]int64 // 按响应状态码统计的请求数
+	errors   int64         // 响应状态码 >= 500 的请求数
+	llm      models.LLMUsage
+}
+
+var (
+	metrics      = make(map[string]*endpointMetrics)
+	metricsMutex sync.Mutex
+)
+
+// RecordRequestMetrics 记录一次请求的结果和模型用量，endpoint 使用路由模板以控制指标数量
+func RecordRequestMetrics(endpoint string, statusCode int, usage models.LLMUsage) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+
+	m := endpointMetricsLocked(endpoint)
+	m.requests[statusCode]++
+	if statusCode >= 500 {
+		m.errors++
+	}
+	addUsage(&m.llm, usage)
+}
+
+// RecordJobUsage 记录后台任务（不经过HTTP请求）的模型用量
+func RecordJobUsage(name string, usage models.LLMUsage) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+
+	addUsage(&endpointMetricsLocked(name).llm, usage)
+}
+
+// endpointMetricsLocked 获取或创建接口的指标，调用方需持有锁
+func endpointMetricsLocked(endpoint string) *endpointMetrics {
+	m, ok := metrics[endpoint]
+	if !ok {
+		m = &endpointMetrics{requests: make(map[int]int64)}
+		metrics[endpoint] = m
+	}
+	return m
+}
+
+// addUsage 累加模型用量
+func addUsage(total *models.LLMUsage, usage models.LLMUsage) {
+	total.Calls += usage.Calls
+	total.Errors += usage.Errors
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+	total.Cost += usage.Cost
+}
+
+// GetMetrics 处理指标查询请求，以 Prometheus 文本格式返回各接口的请求数、错误数和模型用量
+func GetMetrics(ctx context.Context, c *app.RequestContext) {
+	metricsMutex.Lock()
+	endpoints := make([]string, 0, len(metrics))
+	for endpoint := range metrics {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	var sb strings.Builder
+	writeMetricHeader(&sb, "meetingagent_http_requests_total", "HTTP请求数")
+	for _, endpoint := range endpoints {
+		m := metrics[endpoint]
+		codes := make([]int, 0, len(m.requests))
+		for code := range m.requests {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(&sb, "meetingagent_http_requests_total{endpoint=%q,status=\"%d\"} %d\n", endpoint, code, m.requests[code])
+		}
+	}
+
+	writeMetricHeader(&sb, "meetingagent_http_errors_total", "响应状态码>=500的HTTP请求数")
+	for _, endpoint := range endpoints {
+		if len(metrics[endpoint].requests) > 0 {
+			fmt.Fprintf(&sb, "meetingagent_http_errors_total{endpoint=%q} %d\n", endpoint, metrics[endpoint].errors)
+		}
+	}
+
+	writeMetricHeader(&sb, "meetingagent_llm_calls_total", "模型调用次数")
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&sb, "meetingagent_llm_calls_total{endpoint=%q} %d\n", endpoint, metrics[endpoint].llm.Calls)
+	}
+
+	writeMetricHeader(&sb, "meetingagent_llm_errors_total", "模型调用失败次数")
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&sb, "meetingagent_llm_errors_total{endpoint=%q} %d\n", endpoint, metrics[endpoint].llm.Errors)
+	}
+
+	writeMetricHeader(&sb, "meetingagent_llm_tokens_total", "模型消耗的token数")
+	for _, endpoint := range endpoints {
+		llm := metrics[endpoint].llm
+		fmt.Fprintf(&sb, "meetingagent_llm_tokens_total{endpoint=%q,type=\"prompt\"} %d\n", endpoint, llm.PromptTokens)
+		fmt.Fprintf(&sb, "meetingagent_llm_tokens_total{endpoint=%q,type=\"completion\"} %d\n", endpoint, llm.CompletionTokens)
+	}
+
+	writeMetricHeader(&sb, "meetingagent_llm_cost_total", "按配置单价估算的模型费用")
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&sb, "meetingagent_llm_cost_total{endpoint=%q} %s\n", endpoint, strconv.FormatFloat(metrics[endpoint].llm.Cost, 'f', -1, 64))
+	}
+	metricsMutex.Unlock()
+
+	concurrency := models.GetLLMConcurrencyStats()
+	writeGaugeHeader(&sb, "meetingagent_llm_in_flight", "正在进行的模型调用数")
+	fmt.Fprintf(&sb, "meetingagent_llm_in_flight %d\n", concurrency.InFlight)
+	writeGaugeHeader(&sb, "meetingagent_llm_waiting", "等待并发名额的模型调用数")
+	fmt.Fprintf(&sb, "meetingagent_llm_waiting %d\n", concurrency.Waiting)
+	writeGaugeHeader(&sb, "meetingagent_llm_max_concurrent", "模型调用并发上限，0 表示不限制")
+	fmt.Fprintf(&sb, "meetingagent_llm_max_concurrent %d\n", concurrency.Limit)
+
+	c.Data(consts.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(sb.String()))
+}
+
+// writeMetricHeader 写入指标的 HELP 和 TYPE 说明
+func writeMetricHeader(sb *strings.Builder, name, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+}
+
+// writeGaugeHeader 写入瞬时值指标的 HELP 和 TYPE 说明
+func writeGaugeHeader(sb *strings.Builder, name, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// MeetingQuery 只需要会议ID的查询参数，用于摘要、流程图、评分等接口
+type MeetingQuery struct {
+	MeetingID string `query:"meeting_id" vd:"len($)>0; msg:'meeting_id is required'"`
+}
+
+// SessionQuery 会议ID和会话ID，用于聊天历史等按会话读取的接口
+type SessionQuery struct {
+	MeetingID string `query:"meeting_id" vd:"len($)>0; msg:'meeting_id is required'"`
+	SessionID string `query:"session_id" vd:"len($)>0; msg:'session_id is required'"`
+}
+
+// ChatQuery 实时聊天的查询参数
+type ChatQuery struct {
+	MeetingID string `query:"meeting_id" vd:"len($)>0; msg:'meeting_id is required'"`
+	SessionID string `query:"session_id" vd:"len($)>0; msg:'session_id is required'"`
+	Message   string `query:"message" vd:"len($)>0; msg:'message is required'"`
+}
+
+// RolePlayQuery 角色扮演聊天的查询参数
+type RolePlayQuery struct {
+	MeetingID   string `query:"meeting_id" vd:"len($)>0; msg:'meeting_id is required'"`
+	SessionID   string `query:"session_id" vd:"len($)>0; msg:'session_id is required'"`
+	Message     string `query:"message" vd:"len($)>0; msg:'message is required'"`
+	Participant string `query:"participant" vd:"len($)>0; msg:'participant is required'"`
+}
+
+// bindQuery 按结构体的 query 标签绑定查询参数并按 vd 标签校验，校验失败时返回 400，
+// 错误信息为第一个不满足要求的参数的 msg，例如 {"error": "meeting_id is required"}
+func bindQuery(c *app.RequestContext, req interface{}) bool {
+	if err := c.BindAndValidate(req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"meetingagent/models"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// idleBucketTTL 令牌桶闲置超过该时长后被清理，清理时令牌桶必然已经装满，不影响限流结果
+const idleBucketTTL = 10 * time.Minute
+
+// tokenBucket 单个客户端的令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter 按客户端限流的令牌桶，令牌以固定速率补充，桶容量即允许的突发请求数
+type RateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // 每秒补充的令牌数
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+}
+
+// NewRateLimiter 创建限流器，requestsPerMinute 为持续请求速率，burst 为允许的突发请求数
+func NewRateLimiter(requestsPerMinute float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:      requestsPerMinute / 60,
+		burst:     float64(burst),
+		buckets:   make(map[string]*tokenBucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Allow 尝试为客户端消耗一个令牌，被拒绝时返回需要等待的时长
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.pruneLocked(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	// 按流逝的时间补充令牌，不超过桶容量
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// pruneLocked 定期清理闲置的令牌桶，调用方需持有锁
+func (l *RateLimiter) pruneLocked(now time.Time) {
+	if now.Sub(l.lastPrune) < idleBucketTTL {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) > idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// Middleware 返回限流中间件，超出限制时返回429并通过 Retry-After 告知客户端等待的秒数
+func (l *RateLimiter) Middleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		allowed, wait := l.Allow(rateLimitKey(c))
+		if allowed {
+			c.Next(ctx)
+			return
+		}
+
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(consts.StatusTooManyRequests, utils.H{
+			"error":       "请求过于频繁，请稍后重试",
+			"retry_after": retryAfter,
+		})
+	}
+}
+
+// rateLimitKey 按客户端IP限流。服务没有经过验证的用户身份，不能按 Authorization 等客户端可随意伪造的请求头区分，
+// 否则每次换一个请求头就能得到新的令牌桶
+func rateLimitKey(c *app.RequestContext) string {
+	return c.ClientIP()
+}
+
+// llmRateLimiter 调用模型的接口共享的限流器，按启动时的 rate_limit 配置创建，未启用限流时为 nil
+var (
+	llmRateLimiter     *RateLimiter
+	llmRateLimiterOnce sync.Once
+)
+
+// getLLMRateLimiter 返回调用模型的接口共享的限流器，未启用限流时返回 nil
+func getLLMRateLimiter() *RateLimiter {
+	llmRateLimiterOnce.Do(func() {
+		settings := models.GetRateLimitSettings()
+		if settings.Enabled {
+			llmRateLimiter = NewRateLimiter(settings.RequestsPerMinute, settings.Burst)
+		}
+	})
+	return llmRateLimiter
+}
+
+// LLMRateLimit 返回调用模型接口的路由使用的限流中间件，未启用限流时直接放行
+func LLMRateLimit() app.HandlerFunc {
+	limiter := getLLMRateLimiter()
+	if limiter == nil {
+		return func(ctx context.Context, c *app.RequestContext) {
+			c.Next(ctx)
+		}
+	}
+	return limiter.Middleware()
+}
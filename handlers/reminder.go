@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"meetingagent/models"
+	"meetingagent/sql"
+)
+
+// todoReminder 定期扫描逾期待办事项并推送提醒
+type todoReminder struct {
+	notifier models.Notifier
+	within   time.Duration
+	// reminded 记录已提醒过的待办及提醒时的截止时间，截止时间变更后会再次提醒
+	reminded map[int64]time.Time
+}
+
+// StartTodoReminder 启动后台协程，按配置的间隔扫描逾期待办并通过推送渠道提醒，ctx 取消后退出
+func StartTodoReminder(ctx context.Context) {
+	settings := models.GetReminderSettings()
+	if !settings.Enabled {
+		return
+	}
+
+	notifier, err := models.NewNotifier(settings.Channel)
+	if err != nil {
+		fmt.Printf("启动待办提醒失败: %v\n", err)
+		return
+	}
+
+	reminder := &todoReminder{
+		notifier: notifier,
+		within:   settings.Within,
+		reminded: make(map[int64]time.Time),
+	}
+
+	fmt.Printf("待办提醒已启动, 扫描间隔: %v, 推送渠道: %s\n", settings.Interval, notifier.Name())
+
+	go func() {
+		ticker := time.NewTicker(settings.Interval)
+		defer ticker.Stop()
+
+		for {
+			reminder.scan()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// scan 扫描一次逾期待办，只对新出现的逾期项推送提醒
+func (r *todoReminder) scan() {
+	todos, err := sql.GetOverdueTodos(dbName, r.within)
+	if err != nil {
+		fmt.Printf("扫描逾期待办失败: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	current := make(map[int64]bool, len(todos))
+	var pending []*sql.Todo
+	for _, todo := range todos {
+		current[todo.ID] = true
+		if dueDate, ok := r.reminded[todo.ID]; ok && dueDate.Equal(todo.DueDate) {
+			continue
+		}
+		pending = append(pending, todo)
+	}
+
+	// 已完成、已删除或延期的待办不再需要记录
+	for id := range r.reminded {
+		if !current[id] {
+			delete(r.reminded, id)
+		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	for _, todo := range pending {
+		state := "即将到期"
+		if todo.DueDate.Before(now) {
+			state = "已逾期"
+		}
+		assignee := todo.AssignedTo
+		if assignee == "" {
+			assignee = "未指定"
+		}
+		sb.WriteString(fmt.Sprintf("- 【%s】%s（负责人: %s，截止: %s）\n",
+			state, todo.Title, assignee, todo.DueDate.Local().Format("2006-01-02 15:04")))
+	}
+
+	if err := r.notifier.SendText("待办事项到期提醒", sb.String()); err != nil {
+		// 推送失败时不记录，下次扫描重试
+		fmt.Printf("推送待办提醒失败: %v\n", err)
+		return
+	}
+
+	for _, todo := range pending {
+		r.reminded[todo.ID] = todo.DueDate
+	}
+	fmt.Printf("已推送 %d 条待办到期提醒\n", len(pending))
+}
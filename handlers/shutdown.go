@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+var (
+	// shuttingDown 服务关闭时关闭，通知长连接（SSE）尽快结束
+	shuttingDown     = make(chan struct{})
+	shuttingDownOnce sync.Once
+)
+
+// Shutdown 作为服务的 OnShutdown 钩子：结束SSE长连接，停止接收新的会议处理任务，
+// 并等待正在执行的任务写完会议文件和待办事项。
+// 待办数据库每次操作都独立打开和关闭连接，没有需要额外关闭的共享连接
+func Shutdown(ctx context.Context) {
+	shuttingDownOnce.Do(func() {
+		close(shuttingDown)
+	})
+
+	if err := meetingQueue.Shutdown(ctx); err != nil {
+		fmt.Printf("等待会议处理任务完成超时: %v\n", err)
+		return
+	}
+	fmt.Println("会议处理任务已全部完成")
+}
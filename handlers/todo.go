@@ -2,23 +2,38 @@ package handlers
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
+	"meetingagent/models"
 	"meetingagent/sql"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/common/utils"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/hertz-contrib/sse"
 )
 
-const dbName = "./storage/todo.db"
+// dbName 待办事项数据库文件路径，由 InitDatabase 按存储配置设置
+var dbName string
 
-// 初始化数据库
-func init() {
+// InitDatabase 按存储配置 storage.todo_db 打开待办事项数据库并创建所需的表，需在注册路由前调用
+func InitDatabase() error {
+	dbName = models.GetStorageSettings().TodoDB
 	if err := sql.InitTodoTable(dbName); err != nil {
-		panic("初始化Todo数据库失败: " + err.Error())
+		return fmt.Errorf("初始化Todo数据库失败: %w", err)
 	}
+	if err := sql.InitIdempotencyTable(dbName); err != nil {
+		return fmt.Errorf("初始化幂等键表失败: %w", err)
+	}
+	return nil
 }
 
 // TodoRequest 创建或更新待办事项的请求
@@ -30,20 +45,26 @@ type TodoRequest struct {
 	DueDate     time.Time `json:"due_date"`
 	MeetingID   string    `json:"meeting_id"`
 	AssignedTo  string    `json:"assigned_to"`
+	ParentID    *int64    `json:"parent_id"` // 父待办ID，更新时传0表示改为顶层待办
 }
 
 // TodoResponse 返回给客户端的待办事项信息
 type TodoResponse struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    int       `json:"priority"`
-	DueDate     time.Time `json:"due_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	MeetingID   string    `json:"meeting_id"`
-	AssignedTo  string    `json:"assigned_to"`
+	ID            int64      `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Status        string     `json:"status"`       // 状态代码: NOT_STARTED、IN_PROGRESS、DONE
+	StatusLabel   string     `json:"status_label"` // 状态按请求语言的显示名称
+	Priority      int        `json:"priority"`
+	PriorityLabel string     `json:"priority_label"` // 优先级按请求语言的显示名称，例如 高、中、低
+	DueDate       time.Time  `json:"due_date"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	MeetingID     string     `json:"meeting_id"`
+	AssignedTo    string     `json:"assigned_to"`
+	CompletedAt   *time.Time `json:"completed_at"` // 完成时间，未完成时为 null
+	ParentID      *int64     `json:"parent_id"`    // 父待办ID，顶层待办为 null
+	Extracted     bool       `json:"extracted"`    // 是否由会议抽取生成，手动创建的待办为 false
 }
 
 // TodosResponse 返回给客户端的待办事项列表
@@ -66,9 +87,18 @@ func CreateTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	// 默认状态为未开始
+	// 未提供状态和优先级时使用配置的默认值
+	defaultPriority, defaultStatus := todoDefaults()
 	if req.Status == "" {
-		req.Status = "未开始"
+		req.Status = defaultStatus
+	}
+	if req.Priority == 0 {
+		req.Priority = defaultPriority
+	}
+
+	if errMsg := validateTodoRequest(&req); errMsg != "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": errMsg})
+		return
 	}
 
 	// 创建待办事项对象
@@ -81,6 +111,12 @@ func CreateTodo(ctx context.Context, c *app.RequestContext) {
 		MeetingID:   req.MeetingID,
 		AssignedTo:  req.AssignedTo,
 	}
+	if req.ParentID != nil && *req.ParentID != 0 {
+		if !validateTodoParent(c, 0, *req.ParentID) {
+			return
+		}
+		todo.ParentID = req.ParentID
+	}
 
 	// 添加到数据库
 	id, err := sql.AddTodo(dbName, todo)
@@ -89,6 +125,8 @@ func CreateTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	refreshMeetingStatus(todo.MeetingID)
+
 	// 返回成功响应
 	c.JSON(consts.StatusOK, utils.H{
 		"message": "待办事项创建成功",
@@ -96,25 +134,57 @@ func CreateTodo(ctx context.Context, c *app.RequestContext) {
 	})
 }
 
-// GetTodoList 处理获取待办事项列表请求
-func GetTodoList(ctx context.Context, c *app.RequestContext) {
-	// 获取查询参数
-	meetingID := c.Query("meeting_id")
-	status := c.Query("status")
-	priorityStr := c.Query("priority")
-
-	var priority int
-	if priorityStr != "" {
-		var err error
-		priority, err = strconv.Atoi(priorityStr)
+// parseTodoFilter 解析待办列表的筛选参数：meeting_id、status、priority、assigned_to、due_before 和排序参数 sort
+func parseTodoFilter(c *app.RequestContext) (sql.TodoFilter, error) {
+	filter := sql.TodoFilter{
+		MeetingID:  c.Query("meeting_id"),
+		Status:     c.Query("status"),
+		AssignedTo: c.Query("assigned_to"),
+		Sort:       c.Query("sort"),
+	}
+
+	// 状态筛选同样接受显示名称
+	if status, ok := sql.ParseTodoStatus(filter.Status); ok {
+		filter.Status = string(status)
+	}
+
+	if !sql.IsValidTodoSort(filter.Sort) {
+		return filter, fmt.Errorf("排序参数无效，可选值: %s", strings.Join(sql.TodoSorts, "、"))
+	}
+
+	if priorityStr := c.Query("priority"); priorityStr != "" {
+		priority, err := strconv.Atoi(priorityStr)
+		if err != nil || !sql.IsValidTodoPriority(priority) {
+			return filter, fmt.Errorf("优先级参数无效，优先级需在 %d-%d 之间", sql.TodoPriorityHigh, sql.TodoPriorityLow)
+		}
+		filter.Priority = priority
+	}
+
+	// 截止时间支持 RFC3339 或 YYYY-MM-DD 格式，日期格式按当天零点处理
+	if dueBeforeStr := c.Query("due_before"); dueBeforeStr != "" {
+		dueBefore, err := time.Parse(time.RFC3339, dueBeforeStr)
 		if err != nil {
-			c.JSON(consts.StatusBadRequest, utils.H{"error": "优先级参数无效"})
-			return
+			dueBefore, err = time.ParseInLocation("2006-01-02", dueBeforeStr, time.Local)
+		}
+		if err != nil {
+			return filter, errors.New("截止时间参数无效")
 		}
+		filter.DueBefore = dueBefore
+	}
+
+	return filter, nil
+}
+
+// GetTodoList 处理获取待办事项列表请求
+func GetTodoList(ctx context.Context, c *app.RequestContext) {
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
 	}
 
 	// 查询待办事项
-	todos, err := sql.ListTodos(dbName, meetingID, status, priority)
+	todos, err := sql.ListTodosWithFilter(dbName, filter)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "查询待办事项失败: " + err.Error()})
 		return
@@ -122,25 +192,126 @@ func GetTodoList(ctx context.Context, c *app.RequestContext) {
 
 	// 转换为响应格式
 	var response TodosResponse
+	locale := models.LocaleFromContext(ctx)
 	for _, todo := range todos {
-		response.Todos = append(response.Todos, TodoResponse{
-			ID:          todo.ID,
-			Title:       todo.Title,
-			Description: todo.Description,
-			Status:      todo.Status,
-			Priority:    todo.Priority,
-			DueDate:     todo.DueDate,
-			CreatedAt:   todo.CreatedAt,
-			UpdatedAt:   todo.UpdatedAt,
-			MeetingID:   todo.MeetingID,
-			AssignedTo:  todo.AssignedTo,
-		})
+		response.Todos = append(response.Todos, toTodoResponse(todo, locale))
 	}
 
 	// 返回响应
 	c.JSON(consts.StatusOK, response)
 }
 
+// toTodoResponse 将待办记录转换为响应格式，状态和优先级的显示名称使用 locale 对应的语言
+func toTodoResponse(todo *sql.Todo, locale string) TodoResponse {
+	return TodoResponse{
+		ID:            todo.ID,
+		Title:         todo.Title,
+		Description:   todo.Description,
+		Status:        todo.Status,
+		StatusLabel:   sql.TodoStatusLabel(todo.Status, locale),
+		Priority:      todo.Priority,
+		PriorityLabel: sql.TodoPriorityLabel(todo.Priority, locale),
+		DueDate:       todo.DueDate,
+		CreatedAt:     todo.CreatedAt,
+		UpdatedAt:     todo.UpdatedAt,
+		MeetingID:     todo.MeetingID,
+		AssignedTo:    todo.AssignedTo,
+		CompletedAt:   todo.CompletedAt,
+		ParentID:      todo.ParentID,
+		Extracted:     todo.Extracted,
+	}
+}
+
+// todoCSVHeader 待办导出CSV的列
+var todoCSVHeader = []string{"id", "title", "description", "status", "priority", "due_date", "assigned_to", "meeting_id", "created_at", "parent_id"}
+
+// GetTodo 处理获取单个待办事项请求，不存在时返回 404
+func GetTodo(ctx context.Context, c *app.RequestContext) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的ID参数"})
+		return
+	}
+
+	todo, err := sql.GetTodoByID(dbName, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrTodoNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "待办事项不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "获取待办事项失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, toTodoResponse(todo, models.LocaleFromContext(ctx)))
+}
+
+// ExportTodos 处理待办导出请求，按与 GetTodoList 相同的筛选参数逐行流式输出CSV
+func ExportTodos(ctx context.Context, c *app.RequestContext) {
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "不支持的导出格式，目前仅支持 csv"})
+		return
+	}
+
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		// 写入UTF-8 BOM，便于Excel正确识别中文
+		if _, err := writer.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(todoCSVHeader); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		err := sql.IterateTodosWithFilter(dbName, filter, func(todo *sql.Todo) error {
+			dueDate := ""
+			if !todo.DueDate.IsZero() {
+				dueDate = todo.DueDate.Format(time.RFC3339)
+			}
+			parentID := ""
+			if todo.ParentID != nil {
+				parentID = strconv.FormatInt(*todo.ParentID, 10)
+			}
+			return csvWriter.Write([]string{
+				strconv.FormatInt(todo.ID, 10),
+				todo.Title,
+				todo.Description,
+				todo.Status,
+				strconv.Itoa(todo.Priority),
+				dueDate,
+				todo.AssignedTo,
+				todo.MeetingID,
+				todo.CreatedAt.Format(time.RFC3339),
+				parentID,
+			})
+		})
+		if err == nil {
+			csvWriter.Flush()
+			err = csvWriter.Error()
+		}
+		if err != nil {
+			// 响应头已发出，只能中断输出并记录日志
+			models.Logf(ctx, "导出待办事项失败: %v\n", err)
+		}
+		writer.CloseWithError(err)
+	}()
+
+	filename := fmt.Sprintf("todos_%s.csv", time.Now().Format("20060102_150405"))
+	c.Response.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.SetContentType("text/csv; charset=utf-8")
+	c.SetBodyStream(reader, -1)
+}
+
 // UpdateTodo 处理更新待办事项请求
 func UpdateTodo(ctx context.Context, c *app.RequestContext) {
 	// 获取待办事项ID
@@ -165,6 +336,16 @@ func UpdateTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	// 只校验请求中提供的字段，历史数据中的无效值不影响其他字段的更新
+	if errMsg := validateTodoRequest(&req); errMsg != "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": errMsg})
+		return
+	}
+
+	// 记录原关联会议，待办移到其他会议时两边的状态都需要重新计算
+	oldMeetingID := todo.MeetingID
+	oldStatus := todo.Status
+
 	// 更新待办事项字段
 	if req.Title != "" {
 		todo.Title = req.Title
@@ -187,19 +368,226 @@ func UpdateTodo(ctx context.Context, c *app.RequestContext) {
 	if req.AssignedTo != "" {
 		todo.AssignedTo = req.AssignedTo
 	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			todo.ParentID = nil
+		} else {
+			if !validateTodoParent(c, todo.ID, *req.ParentID) {
+				return
+			}
+			todo.ParentID = req.ParentID
+		}
+	}
 
-	// 执行更新
+	// 标记为已完成时检查子任务
+	if todo.Status == string(sql.TodoStatusCompleted) && oldStatus != todo.Status && !checkSubtasksCompleted(ctx, c, todo.ID) {
+		return
+	}
+
+	// 执行更新，并发修改父待办时写入前的检查仍可能发现循环
 	if err := sql.UpdateTodo(dbName, todo); err != nil {
+		if errors.Is(err, sql.ErrTodoParentNotFound) || errors.Is(err, sql.ErrTodoParentCycle) {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+			return
+		}
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "更新待办事项失败: " + err.Error()})
 		return
 	}
 
+	refreshMeetingStatus(todo.MeetingID)
+	if oldMeetingID != todo.MeetingID {
+		refreshMeetingStatus(oldMeetingID)
+	}
+
 	// 返回成功响应
 	c.JSON(consts.StatusOK, utils.H{
 		"message": "待办事项更新成功",
 	})
 }
 
+// CompleteTodo 处理将待办事项标记为已完成的请求，记录完成时间并返回更新后的待办
+func CompleteTodo(ctx context.Context, c *app.RequestContext) {
+	setTodoStatus(ctx, c, sql.TodoStatusCompleted)
+}
+
+// ReopenTodo 处理重新打开已完成待办事项的请求，状态恢复为未开始并清除完成时间
+func ReopenTodo(ctx context.Context, c *app.RequestContext) {
+	setTodoStatus(ctx, c, sql.TodoStatusNotStarted)
+}
+
+// setTodoStatus 只修改待办事项的状态，其他字段保持不变
+func setTodoStatus(ctx context.Context, c *app.RequestContext, status sql.TodoStatus) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的ID参数"})
+		return
+	}
+
+	todo, err := sql.GetTodoByID(dbName, id)
+	if err != nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "待办事项不存在: " + err.Error()})
+		return
+	}
+
+	if status == sql.TodoStatusCompleted && todo.Status != string(status) && !checkSubtasksCompleted(ctx, c, todo.ID) {
+		return
+	}
+
+	todo.Status = string(status)
+	if err := sql.UpdateTodo(dbName, todo); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "更新待办事项失败: " + err.Error()})
+		return
+	}
+
+	refreshMeetingStatus(todo.MeetingID)
+
+	c.JSON(consts.StatusOK, toTodoResponse(todo, models.LocaleFromContext(ctx)))
+}
+
+// validateTodoParent 校验父待办，父待办不存在或会形成循环时返回 400 并返回 false
+func validateTodoParent(c *app.RequestContext, id, parentID int64) bool {
+	err := sql.ValidateTodoParent(dbName, id, parentID)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, sql.ErrTodoParentNotFound) || errors.Is(err, sql.ErrTodoParentCycle) {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return false
+	}
+	c.JSON(consts.StatusInternalServerError, utils.H{"error": "校验父待办失败: " + err.Error()})
+	return false
+}
+
+// checkSubtasksCompleted 检查待办的子任务是否都已完成，配置允许未完成子任务时不检查。
+// 存在未完成的子任务时返回 409 和这些子任务，并返回 false
+func checkSubtasksCompleted(ctx context.Context, c *app.RequestContext, id int64) bool {
+	if models.GetTodoSettings().AllowIncompleteSubtasks {
+		return true
+	}
+
+	incomplete, err := sql.IncompleteSubtasks(dbName, id)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "查询子任务失败: " + err.Error()})
+		return false
+	}
+	if len(incomplete) == 0 {
+		return true
+	}
+
+	subtasks := make([]TodoResponse, 0, len(incomplete))
+	locale := models.LocaleFromContext(ctx)
+	for _, todo := range incomplete {
+		subtasks = append(subtasks, toTodoResponse(todo, locale))
+	}
+	c.JSON(consts.StatusConflict, utils.H{
+		"error":               fmt.Sprintf("还有 %d 个子任务未完成，不能标记为已完成", len(incomplete)),
+		"incomplete_subtasks": subtasks,
+	})
+	return false
+}
+
+// TodoTreeResponse 待办及其子任务树
+type TodoTreeResponse struct {
+	TodoResponse
+	Children []TodoTreeResponse `json:"children"`
+}
+
+// toTodoTreeResponse 将子任务树转换为响应格式
+func toTodoTreeResponse(node *sql.TodoNode, locale string) TodoTreeResponse {
+	response := TodoTreeResponse{
+		TodoResponse: toTodoResponse(node.Todo, locale),
+		Children:     make([]TodoTreeResponse, 0, len(node.Children)),
+	}
+	for _, child := range node.Children {
+		response.Children = append(response.Children, toTodoTreeResponse(child, locale))
+	}
+	return response
+}
+
+// GetTodoTree 处理获取待办子任务树的请求
+func GetTodoTree(ctx context.Context, c *app.RequestContext) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "无效的ID参数"})
+		return
+	}
+
+	tree, err := sql.GetTodoTree(dbName, id)
+	if err != nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "待办事项不存在: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, toTodoTreeResponse(tree, models.LocaleFromContext(ctx)))
+}
+
+// ValidateTodoDefaults 校验配置的待办默认优先级和状态，供启动时检查
+func ValidateTodoDefaults() error {
+	_, _, err := configuredTodoDefaults()
+	return err
+}
+
+// configuredTodoDefaults 读取配置的待办默认优先级和状态，未配置时为中等优先级、未开始
+func configuredTodoDefaults() (int, string, error) {
+	priority, status := sql.TodoPriorityMedium, string(sql.TodoStatusNotStarted)
+	settings := models.GetTodoSettings()
+	if settings.DefaultPriority != 0 {
+		if !sql.IsValidTodoPriority(settings.DefaultPriority) {
+			return priority, status, fmt.Errorf("todo.default_priority 无效: %d，优先级需在 %d-%d 之间", settings.DefaultPriority, sql.TodoPriorityHigh, sql.TodoPriorityLow)
+		}
+		priority = settings.DefaultPriority
+	}
+	if settings.DefaultStatus != "" {
+		// 兼容旧配置中的中文状态
+		parsed, ok := sql.ParseTodoStatus(settings.DefaultStatus)
+		if !ok {
+			return priority, status, fmt.Errorf("todo.default_status 无效: %s，可选值: %v", settings.DefaultStatus, sql.TodoStatuses)
+		}
+		status = string(parsed)
+	}
+	return priority, status, nil
+}
+
+// todoDefaults 返回新建待办的默认优先级和状态，配置无效时使用内置默认值
+func todoDefaults() (int, string) {
+	priority, status, err := configuredTodoDefaults()
+	if err != nil {
+		fmt.Printf("待办默认值配置无效，使用内置默认值: %v\n", err)
+		return sql.TodoPriorityMedium, string(sql.TodoStatusNotStarted)
+	}
+	return priority, status
+}
+
+// validateTodoRequest 校验请求中的状态和优先级，未提供的字段不校验，返回错误信息。
+// 状态可以是状态代码或任一语言的显示名称，校验通过后统一转换为状态代码
+func validateTodoRequest(req *TodoRequest) string {
+	if req.Status != "" {
+		status, ok := sql.ParseTodoStatus(req.Status)
+		if !ok {
+			return fmt.Sprintf("无效的状态: %s，可选值: %v", req.Status, sql.TodoStatuses)
+		}
+		req.Status = string(status)
+	}
+	if req.Priority != 0 && !sql.IsValidTodoPriority(req.Priority) {
+		return fmt.Sprintf("无效的优先级: %d，优先级需在 %d-%d 之间", req.Priority, sql.TodoPriorityHigh, sql.TodoPriorityLow)
+	}
+	return ""
+}
+
+// GetTodoMeta 返回待办事项允许的状态和优先级，供前端构建下拉框
+func GetTodoMeta(ctx context.Context, c *app.RequestContext) {
+	defaultPriority, defaultStatus := todoDefaults()
+	locale := models.LocaleFromContext(ctx)
+	c.JSON(consts.StatusOK, utils.H{
+		"statuses":         sql.TodoStatuses,
+		"status_options":   sql.TodoStatusOptions(locale),
+		"priorities":       sql.TodoPriorities(locale),
+		"sorts":            sql.TodoSorts,
+		"default_status":   defaultStatus,
+		"default_priority": defaultPriority,
+	})
+}
+
 // DeleteTodo 处理删除待办事项请求
 func DeleteTodo(ctx context.Context, c *app.RequestContext) {
 	// 获取待办事项ID
@@ -210,14 +598,385 @@ func DeleteTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	// 删除前记录关联会议，用于更新会议状态
+	var meetingID string
+	if todo, err := sql.GetTodoByID(dbName, id); err == nil {
+		meetingID = todo.MeetingID
+	}
+
 	// 执行删除
 	if err := sql.DeleteTodo(dbName, id); err != nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "删除待办事项失败: " + err.Error()})
 		return
 	}
 
+	refreshMeetingStatus(meetingID)
+
 	// 返回成功响应
 	c.JSON(consts.StatusOK, utils.H{
 		"message": "待办事项删除成功",
 	})
 }
+
+// DeleteMeetingTodos 处理按会议批量删除待办事项请求，用于会议取消后一次清理其全部行动项。
+// meeting_id 必填，不允许不带条件删除全部待办
+func DeleteMeetingTodos(ctx context.Context, c *app.RequestContext) {
+	meetingID := strings.TrimSpace(c.Query("meeting_id"))
+	if meetingID == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id is required"})
+		return
+	}
+
+	deleted, err := sql.DeleteTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "删除会议待办事项失败: " + err.Error()})
+		return
+	}
+
+	if deleted > 0 {
+		refreshMeetingStatus(meetingID)
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"message":    "会议待办事项删除成功",
+		"meeting_id": meetingID,
+		"deleted":    deleted,
+	})
+}
+
+// AssigneeTodoStats 单个负责人的待办统计
+type AssigneeTodoStats struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Overdue   int `json:"overdue"`
+}
+
+// TodoStatsResponse 会议待办事项完成情况统计
+type TodoStatsResponse struct {
+	MeetingID      string                        `json:"meeting_id"`
+	Total          int                           `json:"total"`
+	ByStatus       map[string]int                `json:"by_status"`
+	CompletionRate float64                       `json:"completion_rate"` // 完成百分比，0-100
+	Overdue        int                           `json:"overdue"`         // 已过截止时间且未完成的待办数
+	ByAssignee     map[string]*AssigneeTodoStats `json:"by_assignee"`
+}
+
+// GetMeetingTodoStats 处理获取会议待办完成情况统计请求
+func GetMeetingTodoStats(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "meeting_id is required"})
+		return
+	}
+
+	response, err := meetingTodoStats(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "查询待办事项失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, response)
+}
+
+// meetingTodoStats 统计会议待办事项的完成情况
+func meetingTodoStats(meetingID string) (TodoStatsResponse, error) {
+	todos, err := sql.GetTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		return TodoStatsResponse{}, err
+	}
+
+	// 常用状态始终返回，便于客户端直接展示
+	response := TodoStatsResponse{
+		MeetingID:  meetingID,
+		Total:      len(todos),
+		ByStatus:   make(map[string]int),
+		ByAssignee: make(map[string]*AssigneeTodoStats),
+	}
+
+	for _, status := range sql.TodoStatuses {
+		response.ByStatus[string(status)] = 0
+	}
+
+	now := time.Now()
+	for _, todo := range todos {
+		response.ByStatus[todo.Status]++
+
+		assignee := todo.AssignedTo
+		if assignee == "" {
+			assignee = "未分配"
+		}
+		stats, ok := response.ByAssignee[assignee]
+		if !ok {
+			stats = &AssigneeTodoStats{}
+			response.ByAssignee[assignee] = stats
+		}
+		stats.Total++
+
+		if todo.Status == string(sql.TodoStatusCompleted) {
+			stats.Completed++
+		} else if !todo.DueDate.IsZero() && todo.DueDate.Before(now) {
+			stats.Overdue++
+			response.Overdue++
+		}
+	}
+
+	if response.Total > 0 {
+		rate := float64(response.ByStatus[string(sql.TodoStatusCompleted)]) * 100 / float64(response.Total)
+		response.CompletionRate = math.Round(rate*100) / 100
+	}
+
+	return response, nil
+}
+
+// refreshMeetingStatus 根据会议关联待办的完成情况更新会议闭环状态。
+// 待办可以关联尚未落盘或已删除的会议，此时忽略
+func refreshMeetingStatus(meetingID string) {
+	if meetingID == "" {
+		return
+	}
+
+	todos, err := sql.GetTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		fmt.Printf("查询会议 %s 的待办事项失败: %v\n", meetingID, err)
+		return
+	}
+
+	completed := 0
+	for _, todo := range todos {
+		if todo.Status == string(sql.TodoStatusCompleted) {
+			completed++
+		}
+	}
+
+	status := models.ComputeMeetingStatus(len(todos), completed)
+	if err := models.SetMeetingStatus(meetingID, status); err != nil && !errors.Is(err, models.ErrMeetingNotFound) {
+		fmt.Printf("更新会议 %s 状态失败: %v\n", meetingID, err)
+	}
+}
+
+// meetingTodosFromMetadata 将会议元数据中抽取出的待办事项转换为待办记录，状态和优先级使用配置的默认值。
+// 模型无法确定的负责人使用配置的默认负责人，未配置时与截止日期一样留空
+func meetingTodosFromMetadata(meetingID string, metadata models.MeetingMetadata) []*sql.Todo {
+	var todos []*sql.Todo
+	defaultPriority, defaultStatus := todoDefaults()
+	defaultAssignee := models.DefaultTodoAssignee(metadata.Participants)
+	for _, item := range metadata.TodoList {
+		// 负责人尽量对齐为会议记录中的参会人姓名，便于按负责人筛选
+		assignee := item.Assignee
+		if matched, ok := models.MatchParticipant(metadata.Participants, assignee); ok {
+			assignee = matched
+		}
+		if assignee == "" {
+			assignee = defaultAssignee
+		}
+		todos = append(todos, &sql.Todo{
+			Title:       item.Task,
+			Description: fmt.Sprintf("来自会议: %s", metadata.Title),
+			Status:      defaultStatus,
+			Priority:    defaultPriority,
+			DueDate:     models.ParseDueDate(item.DueDate),
+			MeetingID:   meetingID,
+			AssignedTo:  assignee,
+			Extracted:   true,
+		})
+	}
+	return todos
+}
+
+// TodoSyncResponse 会议待办同步结果
+type TodoSyncResponse struct {
+	MeetingID string         `json:"meeting_id"`
+	Added     []TodoResponse `json:"added"`     // 元数据中新增的待办
+	Removed   []TodoResponse `json:"removed"`   // 元数据中已不存在且尚未开始的抽取待办
+	Unchanged []TodoResponse `json:"unchanged"` // 按标题匹配上的待办，保留负责人、状态等手动修改
+	Kept      []TodoResponse `json:"kept"`      // 元数据中已不存在但已有进展的待办，保留不删除
+}
+
+// normalizeTodoTitle 忽略大小写和多余空白比较待办标题
+func normalizeTodoTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// SyncMeetingTodos 处理按会议最新元数据重新同步待办请求，返回差异
+func SyncMeetingTodos(ctx context.Context, c *app.RequestContext) {
+	meetingID := c.Param("id")
+
+	meetingData, err := models.LoadMeeting(meetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			c.JSON(consts.StatusNotFound, utils.H{"error": "会议不存在"})
+			return
+		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "无法读取会议信息"})
+		return
+	}
+	// 没有元数据时不能按空的待办列表同步，否则会删除会议全部尚未开始的抽取待办
+	metadata, ok := models.GetMeetingMetadata(meetingData)
+	if !ok {
+		c.JSON(consts.StatusConflict, utils.H{"error": "会议没有可用的元数据，无法同步待办事项"})
+		return
+	}
+
+	response, err := syncMeetingTodos(meetingID, metadata, models.LocaleFromContext(ctx))
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "同步待办事项失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, response)
+}
+
+// syncMeetingTodos 按会议元数据中的待办列表同步数据库中的会议待办：
+// 按标题匹配已有待办并保留其手动修改，新增缺少的待办，删除已不存在且尚未开始的抽取待办，
+// 通过 POST /todo 手动创建的待办不会被删除。
+// locale 为返回的待办中状态和优先级显示名称的语言
+func syncMeetingTodos(meetingID string, metadata models.MeetingMetadata, locale string) (*TodoSyncResponse, error) {
+	existing, err := sql.GetTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同名待办可能有多条，逐条一一匹配，优先匹配抽取生成的待办
+	byTitle := make(map[string][]*sql.Todo)
+	for _, extracted := range []bool{true, false} {
+		for _, todo := range existing {
+			if todo.Extracted == extracted {
+				key := normalizeTodoTitle(todo.Title)
+				byTitle[key] = append(byTitle[key], todo)
+			}
+		}
+	}
+
+	response := &TodoSyncResponse{
+		MeetingID: meetingID,
+		Added:     []TodoResponse{},
+		Removed:   []TodoResponse{},
+		Unchanged: []TodoResponse{},
+		Kept:      []TodoResponse{},
+	}
+
+	var toAdd []*sql.Todo
+	for _, todo := range meetingTodosFromMetadata(meetingID, metadata) {
+		key := normalizeTodoTitle(todo.Title)
+		if matches := byTitle[key]; len(matches) > 0 {
+			response.Unchanged = append(response.Unchanged, toTodoResponse(matches[0], locale))
+			byTitle[key] = matches[1:]
+			continue
+		}
+		toAdd = append(toAdd, todo)
+	}
+
+	// 未匹配的抽取待办只删除尚未开始的，已有进展的保留，避免同步丢失进度；手动创建的待办不受影响
+	var toRemove []*sql.Todo
+	for _, todo := range existing {
+		key := normalizeTodoTitle(todo.Title)
+		if !todo.Extracted || !containsTodo(byTitle[key], todo) {
+			continue
+		}
+		if todo.Status == string(sql.TodoStatusNotStarted) {
+			toRemove = append(toRemove, todo)
+		} else {
+			response.Kept = append(response.Kept, toTodoResponse(todo, locale))
+		}
+	}
+
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		if err := sql.ApplyTodoChanges(dbName, toAdd, toRemove); err != nil {
+			return nil, err
+		}
+		refreshMeetingStatus(meetingID)
+	}
+
+	for _, todo := range toAdd {
+		response.Added = append(response.Added, toTodoResponse(todo, locale))
+	}
+	for _, todo := range toRemove {
+		response.Removed = append(response.Removed, toTodoResponse(todo, locale))
+	}
+
+	return response, nil
+}
+
+// containsTodo 判断待办是否在列表中
+func containsTodo(todos []*sql.Todo, target *sql.Todo) bool {
+	for _, todo := range todos {
+		if todo.ID == target.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamTodoEvents 通过SSE向订阅者实时推送指定会议的待办事项变更
+func StreamTodoEvents(ctx context.Context, c *app.RequestContext) {
+	var query MeetingQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	meetingID := query.MeetingID
+
+	// 断线重连时浏览器会携带 Last-Event-ID，用于补发断开期间的变更
+	var lastEventID int64
+	if lastID := c.Request.Header.Get("Last-Event-ID"); lastID != "" {
+		lastEventID, _ = strconv.ParseInt(lastID, 10, 64)
+	}
+
+	// 设置SSE响应头
+	c.Response.Header.Set("Content-Type", "text/event-stream")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+
+	// 创建SSE流
+	stream := sse.NewStream(c)
+
+	sub := sql.SubscribeTodoEvents(meetingID, lastEventID)
+	defer sub.Close()
+
+	models.Logf(ctx, "待办事项变更订阅开始, meetingID: %s\n", meetingID)
+	defer models.Logf(ctx, "待办事项变更订阅结束, meetingID: %s\n", meetingID)
+
+	// 按 stream.heartbeat_seconds 定期发送心跳，用于及时发现已断开的连接；间隔为0时不发送心跳
+	var heartbeat <-chan time.Time
+	if interval := models.GetStreamHeartbeatInterval(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-shuttingDown:
+			// 服务关闭，客户端稍后会自动重连
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// 订阅因消费过慢被断开，结束连接让客户端携带 Last-Event-ID 重连
+				return
+			}
+
+			jsonData, err := json.Marshal(event)
+			if err != nil {
+				models.Logf(ctx, "序列化待办事项事件失败: %v\n", err)
+				continue
+			}
+
+			if err := stream.Publish(&sse.Event{
+				ID:    strconv.FormatInt(event.ID, 10),
+				Event: event.Type,
+				Data:  jsonData,
+			}); err != nil {
+				// 客户端已断开
+				return
+			}
+		case <-heartbeat:
+			if err := stream.Publish(&sse.Event{
+				Event: "heartbeat",
+				Data:  []byte(`{}`),
+			}); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"meetingagent/models"
+	sqldb "meetingagent/sql"
+
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+func TestSyncMeetingTodosKeepsManualTodos(t *testing.T) {
+	const meetingID = "meeting_sync_manual_test"
+	metadata := models.MeetingMetadata{
+		Title:    "预算评审会",
+		TodoList: []models.ExtractedTodo{{Task: "整理预算表"}, {Task: "确认采购清单"}},
+	}
+	if err := sqldb.BatchAddTodos(dbName, meetingTodosFromMetadata(meetingID, metadata)); err != nil {
+		t.Fatalf("写入抽取待办失败: %v", err)
+	}
+	manual := &sqldb.Todo{
+		Title:     "预约会议室",
+		Status:    string(sqldb.TodoStatusNotStarted),
+		Priority:  3,
+		MeetingID: meetingID,
+	}
+	if _, err := sqldb.AddTodo(dbName, manual); err != nil {
+		t.Fatalf("写入手动待办失败: %v", err)
+	}
+
+	// 重新抽取后"确认采购清单"已不存在，手动创建的待办也不在列表中
+	metadata.TodoList = []models.ExtractedTodo{{Task: "整理预算表"}}
+	diff, err := syncMeetingTodos(meetingID, metadata, models.DefaultLocale)
+	if err != nil {
+		t.Fatalf("同步待办失败: %v", err)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Title != "确认采购清单" {
+		t.Errorf("删除的待办 = %+v，期望只删除抽取的\"确认采购清单\"", diff.Removed)
+	}
+
+	todos, err := sqldb.GetTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		t.Fatalf("查询待办失败: %v", err)
+	}
+	titles := make(map[string]bool)
+	for _, todo := range todos {
+		titles[todo.Title] = todo.Extracted
+	}
+	if extracted, ok := titles["预约会议室"]; !ok || extracted {
+		t.Errorf("同步后的待办 = %v，期望保留手动创建的\"预约会议室\"", titles)
+	}
+	if extracted, ok := titles["整理预算表"]; !ok || !extracted {
+		t.Errorf("同步后的待办 = %v，期望保留抽取的\"整理预算表\"", titles)
+	}
+	if len(todos) != 2 {
+		t.Errorf("同步后有 %d 个待办，期望 2", len(todos))
+	}
+
+	// 会议文件没有可用的元数据时返回 409，不按空的待办列表删除抽取的待办
+	if err := models.SaveMeeting(meetingID, map[string]interface{}{"raw_content": "张三: 讨论预算"}); err != nil {
+		t.Fatalf("保存会议失败: %v", err)
+	}
+	h := route.NewEngine(config.NewOptions(nil))
+	h.POST("/meeting/:id/todos/sync", SyncMeetingTodos)
+	resp := ut.PerformRequest(h, http.MethodPost, "/meeting/"+meetingID+"/todos/sync", nil).Result()
+	if resp.StatusCode() != http.StatusConflict {
+		t.Errorf("没有元数据时同步的状态码 = %d，期望 %d", resp.StatusCode(), http.StatusConflict)
+	}
+	todos, err = sqldb.GetTodosByMeetingID(dbName, meetingID)
+	if err != nil {
+		t.Fatalf("查询待办失败: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Errorf("没有元数据时同步后有 %d 个待办，期望保留 2 个", len(todos))
+	}
+}
+
+func TestUpdateTodoRejectsConcurrentParentCycle(t *testing.T) {
+	add := func(title string) *sqldb.Todo {
+		todo := &sqldb.Todo{Title: title, Status: string(sqldb.TodoStatusNotStarted), Priority: 2}
+		if _, err := sqldb.AddTodo(dbName, todo); err != nil {
+			t.Fatalf("写入待办失败: %v", err)
+		}
+		return todo
+	}
+	a, b := add("待办A"), add("待办B")
+
+	// 两个并发请求分别把 A 设为 B 的子任务、把 B 设为 A 的子任务，写入前的校验都能通过
+	if err := sqldb.ValidateTodoParent(dbName, a.ID, b.ID); err != nil {
+		t.Fatalf("校验 A→B 失败: %v", err)
+	}
+	if err := sqldb.ValidateTodoParent(dbName, b.ID, a.ID); err != nil {
+		t.Fatalf("校验 B→A 失败: %v", err)
+	}
+
+	a.ParentID = &b.ID
+	if err := sqldb.UpdateTodo(dbName, a); err != nil {
+		t.Fatalf("更新 A 的父待办失败: %v", err)
+	}
+	b.ParentID = &a.ID
+	if err := sqldb.UpdateTodo(dbName, b); !errors.Is(err, sqldb.ErrTodoParentCycle) {
+		t.Errorf("更新 B 的父待办错误 = %v，期望 ErrTodoParentCycle", err)
+	}
+	stored, err := sqldb.GetTodoByID(dbName, b.ID)
+	if err != nil {
+		t.Fatalf("查询待办失败: %v", err)
+	}
+	if stored.ParentID != nil {
+		t.Errorf("B 的父待办 = %d，期望仍为顶层待办", *stored.ParentID)
+	}
+
+	// 父待办不变时照常更新其他字段
+	a.Title = "待办A（已改名）"
+	if err := sqldb.UpdateTodo(dbName, a); err != nil {
+		t.Errorf("不修改父待办的更新失败: %v", err)
+	}
+
+	missing := int64(1 << 40)
+	b.ParentID = &missing
+	if err := sqldb.UpdateTodo(dbName, b); !errors.Is(err, sqldb.ErrTodoParentNotFound) {
+		t.Errorf("父待办不存在时的错误 = %v，期望 ErrTodoParentNotFound", err)
+	}
+}
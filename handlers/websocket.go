@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"meetingagent/models"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/hertz-contrib/sse"
+	"github.com/hertz-contrib/websocket"
+)
+
+const (
+	// wsMaxMessageSize 单条客户端消息的最大字节数，超过时以 1009 状态码关闭连接
+	wsMaxMessageSize = 1 << 20
+	// wsIdleTimeout 连接空闲超时，超过该时间没有收到客户端数据时关闭连接
+	wsIdleTimeout = 10 * time.Minute
+	// wsCloseTimeout 发送关闭帧的超时时间
+	wsCloseTimeout = time.Second
+)
+
+// wsUpgrader WebSocket 握手，与其他接口一样不限制请求来源
+var wsUpgrader = websocket.HertzUpgrader{
+	CheckOrigin: func(c *app.RequestContext) bool { return true },
+	Error: func(c *app.RequestContext, status int, reason error) {
+		c.JSON(status, utils.H{"error": reason.Error()})
+	},
+}
+
+// wsChatConn 将流式回答的事件作为文本帧发送，使聊天逻辑可以同时输出到SSE和WebSocket
+type wsChatConn struct {
+	*websocket.Conn
+}
+
+// Publish 发送事件数据，格式与SSE事件的 data 相同
+func (c wsChatConn) Publish(event *sse.Event) error {
+	return c.WriteMessage(websocket.TextMessage, event.Data)
+}
+
+// closeWith 发送关闭帧，连接本身在hijack处理函数返回后由服务器关闭
+func (c wsChatConn) closeWith(code int, reason string) {
+	c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsCloseTimeout))
+}
+
+// WSChatRequest WebSocket 聊天的客户端消息
+type WSChatRequest struct {
+	MeetingID       string `json:"meeting_id"`
+	SessionID       string `json:"session_id"`
+	Message         string `json:"message"`
+	MaxAnswerLength int    `json:"max_answer_length"`
+	MaxCitations    *int   `json:"max_citations"`
+	Cite            bool   `json:"cite"`
+}
+
+// HandleWebSocketChat 处理 WebSocket 聊天会话，一个连接内可以发送多轮消息，
+// 回答以与SSE相同的JSON帧流式返回。每条消息都是一次模型调用，与HTTP接口共享限流令牌桶
+func HandleWebSocketChat(ctx context.Context, c *app.RequestContext) {
+	// hijack后的连接在请求结束后才开始处理，只保留请求ID和回答语言，模型用量按连接单独统计
+	connCtx := models.WithRequestID(context.Background(), models.RequestIDFromContext(ctx))
+	connCtx = models.WithLocale(connCtx, models.LocaleFromContext(ctx))
+	limitKey := rateLimitKey(c)
+
+	// 握手失败时 wsUpgrader 已返回错误响应
+	wsUpgrader.Upgrade(c, func(conn *websocket.Conn) {
+		serveChatWebSocket(connCtx, wsChatConn{conn}, getLLMRateLimiter(), limitKey)
+	})
+}
+
+// serveChatWebSocket 循环读取客户端消息并逐条回答，客户端断开时停止正在生成的回答。
+// limiter 不为 nil 时每条消息按 limitKey 消耗一个令牌，超出限制的消息只返回错误帧
+func serveChatWebSocket(ctx context.Context, conn wsChatConn, limiter *RateLimiter, limitKey string) {
+	ctx, usageTracker := models.WithUsageTracker(ctx)
+	defer func() {
+		RecordJobUsage("/ws/chat", usageTracker.Snapshot())
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 读取放在单独的goroutine中，使生成回答时也能及时响应 ping 和发现连接断开。
+	// ping、关闭帧和协议错误由 websocket 库处理，收到任何数据都会延长空闲超时
+	conn.SetReadLimit(wsMaxMessageSize)
+	pingHandler := conn.PingHandler()
+	conn.SetPingHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(wsIdleTimeout))
+		return pingHandler(appData)
+	})
+	messages := make(chan []byte)
+	go func() {
+		defer cancel()
+		for {
+			conn.SetReadDeadline(time.Now().Add(wsIdleTimeout))
+			messageType, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if messageType != websocket.TextMessage {
+				conn.closeWith(websocket.CloseUnsupportedData, "仅支持文本消息")
+				return
+			}
+
+			select {
+			case messages <- payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case payload := <-messages:
+			if limiter != nil {
+				if allowed, wait := limiter.Allow(limitKey); !allowed {
+					retryAfter := int(math.Ceil(wait.Seconds()))
+					if err := conn.WriteJSON(utils.H{"error": "请求过于频繁，请稍后重试", "retry_after": retryAfter}); err != nil {
+						return
+					}
+					continue
+				}
+			}
+			if err := handleWebSocketChatMessage(ctx, conn, payload); err != nil {
+				if ctx.Err() == nil {
+					models.Logf(ctx, "WebSocket聊天失败: %v\n", err)
+					conn.closeWith(websocket.CloseNormalClosure, "")
+				}
+				return
+			}
+		}
+	}
+}
+
+// handleWebSocketChatMessage 回答一条客户端消息，请求参数错误只返回错误帧，
+// 仅在连接不可用时返回错误
+func handleWebSocketChatMessage(ctx context.Context, conn wsChatConn, payload []byte) error {
+	var req WSChatRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return conn.WriteJSON(utils.H{"error": "消息格式无效: " + err.Error()})
+	}
+	if req.MeetingID == "" || req.SessionID == "" {
+		return conn.WriteJSON(utils.H{"error": "meeting_id and session_id are required"})
+	}
+	if req.Message == "" {
+		return conn.WriteJSON(utils.H{"error": "message is required"})
+	}
+
+	maxCitations := -1
+	if req.MaxCitations != nil {
+		maxCitations = *req.MaxCitations
+	}
+
+	models.Logf(ctx, "WebSocket聊天 meetingID: %s, sessionID: %s, message: %s\n", req.MeetingID, req.SessionID, req.Message)
+
+	meetingData, err := models.LoadMeeting(req.MeetingID)
+	if err != nil {
+		if errors.Is(err, models.ErrMeetingNotFound) {
+			return conn.WriteJSON(utils.H{"error": "会议不存在"})
+		}
+		return conn.WriteJSON(utils.H{"error": "无法读取会议信息"})
+	}
+
+	// 聊天消息的敏感内容扫描不阻塞回答，只扫描已存在会议的消息
+	go models.CheckCompliance(req.MeetingID, "chat", req.Message)
+
+	chatMsg := models.ChatMessage{
+		Data:            buildChatMeetingContext(meetingData),
+		MaxAnswerLength: req.MaxAnswerLength,
+		MaxCitations:    maxCitations,
+		Cite:            req.Cite,
+		Transcript:      models.MeetingDataContent(meetingData),
+	}
+	return chatMsg.Process(ctx, req.Message, conn, req.MeetingID, req.SessionID)
+}
@@ -2,60 +2,223 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"meetingagent/handlers"
+	"meetingagent/models"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/google/uuid"
 )
 
+// shutdownTimeout 优雅关闭时等待处理中请求和任务的最长时间
+const shutdownTimeout = 30 * time.Second
+
 func main() {
-	h := server.Default()
-	h.Use(Logger())
+	migrateEncryption := flag.Bool("migrate-encryption", false, "按当前加密配置重写所有会议文件后退出")
+	importMeetings := flag.Bool("import-meetings", false, "将 storage.meetings_dir 中的会议文件导入 SQLite 的 meetings 表后退出")
+	flag.Parse()
+
+	if *importMeetings {
+		if _, err := models.ImportMeetingFiles(); err != nil {
+			fmt.Printf("导入会议失败: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *migrateEncryption {
+		migrated, err := models.MigrateMeetingEncryption()
+		if err != nil {
+			fmt.Printf("加密迁移失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("加密迁移完成，共处理 %d 个会议\n", migrated)
+		return
+	}
+
+	// 启动时校验配置，模型名称或密钥配置错误时直接退出，而不是等到第一次调用模型时才失败
+	if _, err := models.LoadConfig(); err != nil {
+		fmt.Printf("加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+	if err := handlers.ValidateTodoDefaults(); err != nil {
+		fmt.Printf("加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := models.EnsureStorageDirs(); err != nil {
+		fmt.Printf("初始化存储目录失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := handlers.InitDatabase(); err != nil {
+		fmt.Printf("初始化数据库失败: %v\n", err)
+		os.Exit(1)
+	}
+	if err := models.InitMeetingStore(); err != nil {
+		fmt.Printf("初始化会议存储失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := models.LoadPrompts(); err != nil {
+		fmt.Printf("加载提示模板失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 收到 SIGINT/SIGTERM 后优雅关闭
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	h := server.Default(server.WithExitWaitTime(shutdownTimeout))
+	h.Use(Logger(), Locale())
+
+	// 默认的信号处理在 SIGTERM 时直接退出，这里统一改为优雅关闭：
+	// 停止接收新连接，执行 OnShutdown 钩子，并在超时前等待处理中的请求结束
+	h.SetCustomSignalWaiter(func(errCh chan error) error {
+		select {
+		case <-ctx.Done():
+			hlog.Infof("收到退出信号，开始优雅关闭")
+			return nil
+		case err := <-errCh:
+			return err
+		}
+	})
+	h.OnShutdown = append(h.OnShutdown, handlers.Shutdown)
+
+	// 启动会议抽取任务队列。任务不随退出信号取消，由 OnShutdown 钩子等待其完成
+	handlers.StartMeetingQueue(context.Background())
+
+	// 启动待办事项到期提醒
+	handlers.StartTodoReminder(ctx)
+
+	// 健康检查路由，供负载均衡和编排系统探测
+	h.GET("/health", handlers.HealthCheck)
+	h.GET("/ready", handlers.ReadinessCheck)
+	h.GET("/metrics", handlers.GetMetrics)
+
+	// 管理接口，需要 admin.token
+	h.POST("/admin/reload-config", handlers.AdminAuth(), handlers.ReloadConfig)
+
+	// 调用模型的接口共享同一个按客户端限流的令牌桶
+	llmLimit := handlers.LLMRateLimit()
 
 	// 注册API路由
 	h.POST("/meeting", handlers.CreateMeeting)
+	h.POST("/meeting/from-url", handlers.CreateMeetingFromURL)
 	h.GET("/meeting", handlers.ListMeetings)
-	h.GET("/summary", handlers.GetMeetingSummary)
-	h.GET("/mermaid", handlers.GetMeetingMermaid)
-	h.GET("/score", handlers.GetMeetingScore)
-	h.GET("/chat", handlers.HandleChat)
-	h.GET("/roleplay", handlers.HandleRolePlayChat)
-	h.GET("/push-report", handlers.PushMeetingReport)
+	h.GET("/meeting/jobs/:id", handlers.GetMeetingJob)
+	h.GET("/meeting/queue", handlers.GetMeetingQueueStats)
+	h.GET("/meeting/tags", handlers.ListMeetingTags)
+	h.GET("/meeting/types", handlers.GetMeetingTypeStats)
+	h.GET("/meeting/search", llmLimit, handlers.SearchMeetings)
+	h.GET("/participants", handlers.ListAllParticipants)
+	h.GET("/summary", llmLimit, handlers.GetMeetingSummary)
+	h.GET("/summary/templates", handlers.ListSummaryTemplates)
+	h.POST("/summary/templates", handlers.SaveSummaryTemplate)
+	h.GET("/mermaid", llmLimit, handlers.GetMeetingMermaid)
+	h.GET("/score", llmLimit, handlers.GetMeetingScore)
+	h.GET("/score/stream", llmLimit, handlers.GetMeetingScoreStream)
+	h.POST("/score/compare", llmLimit, handlers.CompareMeetingScores)
+	h.GET("/chat", llmLimit, handlers.HandleChat)
+	h.GET("/chat/history", handlers.GetChatHistory)
+	h.GET("/roleplay", llmLimit, handlers.HandleRolePlayChat)
+	h.GET("/ws/chat", handlers.HandleWebSocketChat) // 按每条消息限流
+	h.GET("/push-report", llmLimit, handlers.PushMeetingReport)
+	h.GET("/meeting/:id/risks", llmLimit, handlers.GetMeetingRisks)
+	h.GET("/meeting/:id/minutes", llmLimit, handlers.GetMeetingMinutes)
+	h.GET("/meeting/:id/report", llmLimit, handlers.GetMeetingReport)
+	h.GET("/meeting/:id/todo-stats", handlers.GetMeetingTodoStats)
+	h.POST("/meeting/:id/todos/sync", handlers.SyncMeetingTodos)
+	h.GET("/meeting/:id/compliance", handlers.GetMeetingCompliance)
+	h.GET("/meeting/:id/participants", handlers.GetMeetingParticipants)
+	h.GET("/meeting/:id/participation", handlers.GetMeetingParticipation)
+	h.GET("/meeting/:id/raw", handlers.GetMeetingRaw)
+	h.GET("/meeting/:id/overview", llmLimit, handlers.GetMeetingOverview)
+	h.PATCH("/meeting/:id", handlers.PatchMeeting)
+	h.PUT("/meeting/:id/tags", handlers.UpdateMeetingTags)
+	h.POST("/meeting/:id/append", handlers.AppendMeeting)
+	h.POST("/meeting/:id/regenerate-summary", llmLimit, handlers.RegenerateMeetingSummary)
+	h.POST("/meeting/:id/archive", handlers.ArchiveMeeting)
+	h.POST("/meeting/:id/unarchive", handlers.UnarchiveMeeting)
+	h.GET("/prompts", handlers.GetPrompts)
 
 	// 注册多角色扮演会议路由
-	h.POST("/multi-roleplay", handlers.HandleMultiRoleplayMeeting)
-	h.POST("/multi-roleplay/stream", handlers.HandleStreamMultiRoleplayMeeting)
+	h.POST("/multi-roleplay", llmLimit, handlers.HandleMultiRoleplayMeeting)
+	h.POST("/multi-roleplay/stream", llmLimit, handlers.HandleStreamMultiRoleplayMeeting)
+	h.POST("/multi-roleplay/ask", llmLimit, handlers.HandleAskPanel)
+	h.GET("/multi-roleplay/history", handlers.GetMultiRoleplayHistory)
+	h.GET("/multi-roleplay/history/:id/messages", handlers.GetMultiRoleplayMessages)
 
 	// 注册待办事项路由
 	h.POST("/todo", handlers.CreateTodo)
 	h.GET("/todo", handlers.GetTodoList)
+	h.DELETE("/todo", handlers.DeleteMeetingTodos)
+	h.GET("/todo/stream", handlers.StreamTodoEvents)
+	h.GET("/todo/meta", handlers.GetTodoMeta)
+	h.GET("/todo/export", handlers.ExportTodos)
+	h.GET("/todo/:id", handlers.GetTodo)
 	h.PUT("/todo/:id", handlers.UpdateTodo)
 	h.DELETE("/todo/:id", handlers.DeleteTodo)
+	h.POST("/todo/:id/complete", handlers.CompleteTodo)
+	h.POST("/todo/:id/reopen", handlers.ReopenTodo)
+	h.GET("/todo/:id/tree", handlers.GetTodoTree)
 
 	// 提供静态文件服务
-	h.StaticFS("/", &app.FS{
-		Root:               "./static",
-		PathRewrite:        app.NewPathSlashesStripper(1),
-		IndexNames:         []string{"index.html"},
-		GenerateIndexPages: true,
-	})
+	registerStaticFS(h)
 
 	// 启动服务器
 	h.Spin()
 }
 
-// Logger 请求日志中间件
+// requestIDHeader 请求ID的请求头和响应头
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 接受客户端传入请求ID的最大长度
+const maxRequestIDLength = 128
+
+// requestLogEntry json 格式的请求日志
+type requestLogEntry struct {
+	Time             string  `json:"time"`
+	RequestID        string  `json:"request_id"`
+	Method           string  `json:"method"`
+	Path             string  `json:"path"`
+	Query            string  `json:"query,omitempty"`
+	Status           int     `json:"status"`
+	LatencyMs        float64 `json:"latency_ms"`
+	LLMCalls         int64   `json:"llm_calls,omitempty"`
+	PromptTokens     int64   `json:"prompt_tokens,omitempty"`
+	CompletionTokens int64   `json:"completion_tokens,omitempty"`
+	Cost             float64 `json:"cost,omitempty"`
+}
+
+// Logger 请求日志中间件，为每个请求分配请求ID并按配置的格式（text/json）输出请求日志
 func Logger() app.HandlerFunc {
+	logFormat := models.GetLogFormat()
+
 	return func(c context.Context, ctx *app.RequestContext) {
 		start := time.Now()
 		path := string(ctx.Request.URI().Path())
 		query := string(ctx.Request.URI().QueryString())
-		if query != "" {
-			path = path + "?" + query
+
+		// 沿用客户端传入的请求ID，便于跨服务关联日志，否则生成新的ID
+		requestID := string(ctx.Request.Header.Peek(requestIDHeader))
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
 		}
+		ctx.Response.Header.Set(requestIDHeader, requestID)
+		c = models.WithRequestID(c, requestID)
+
+		// 统计请求内所有模型调用的token用量
+		c, usageTracker := models.WithUsageTracker(c)
 
 		// 处理请求
 		ctx.Next(c)
@@ -66,12 +229,100 @@ func Logger() app.HandlerFunc {
 		// 获取响应状态码
 		statusCode := ctx.Response.StatusCode()
 
+		// 按路由模板记录指标，避免路径参数导致指标数量膨胀
+		usage := usageTracker.Snapshot()
+		handlers.RecordRequestMetrics(ctx.FullPath(), statusCode, usage)
+
 		// 记录请求详情
-		hlog.CtxInfof(c, "[HTTP] %s %s - %d - %v",
+		if logFormat == models.LogFormatJSON {
+			entry := requestLogEntry{
+				Time:             start.Format(time.RFC3339Nano),
+				RequestID:        requestID,
+				Method:           string(ctx.Request.Method()),
+				Path:             path,
+				Query:            query,
+				Status:           statusCode,
+				LatencyMs:        float64(latency.Microseconds()) / 1000,
+				LLMCalls:         usage.Calls,
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+				Cost:             usage.Cost,
+			}
+			line, err := json.Marshal(entry)
+			if err != nil {
+				hlog.CtxErrorf(c, "序列化请求日志失败: %v", err)
+				return
+			}
+			fmt.Println(string(line))
+			return
+		}
+
+		if query != "" {
+			path = path + "?" + query
+		}
+		if usage.Calls == 0 {
+			hlog.CtxInfof(c, "[HTTP] %s %s - %d - %v - request_id=%s",
+				ctx.Request.Method(),
+				path,
+				statusCode,
+				latency,
+				requestID,
+			)
+			return
+		}
+		hlog.CtxInfof(c, "[HTTP] %s %s - %d - %v - request_id=%s llm_calls=%d prompt_tokens=%d completion_tokens=%d cost=%.4f",
 			ctx.Request.Method(),
 			path,
 			statusCode,
 			latency,
+			requestID,
+			usage.Calls,
+			usage.PromptTokens,
+			usage.CompletionTokens,
+			usage.Cost,
 		)
 	}
 }
+
+// Locale 回答语言中间件，按 lang 查询参数或 Accept-Language 请求头确定聊天、评分、摘要等模型回答的语言，
+// 未指定或不支持时使用中文
+func Locale() app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		locale := models.ResolveLocale(ctx.Query("lang"), string(ctx.Request.Header.Peek("Accept-Language")))
+		ctx.Next(models.WithLocale(c, locale))
+	}
+}
+
+// validRequestID 判断客户端传入的请求ID是否可用，只接受长度有限的字母、数字和 -_.:
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, ch := range requestID {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '-' || ch == '_' || ch == '.' || ch == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// registerStaticFS 按配置注册静态文件服务。页面以 /static/ 前缀引用资源，
+// 挂载在根路径并去掉第一段路径，使 /static/app.js 映射到静态目录下的 app.js。
+// 目录请求返回其中的 index.html，默认不生成目录列表，避免暴露静态目录下的其他文件
+func registerStaticFS(h *server.Hertz) {
+	settings := models.GetStaticSettings()
+	if !settings.Enabled {
+		fmt.Println("静态文件服务已关闭，仅提供API")
+		return
+	}
+
+	h.StaticFS("/", &app.FS{
+		Root:               settings.Root,
+		PathRewrite:        app.NewPathSlashesStripper(1),
+		IndexNames:         []string{"index.html"},
+		GenerateIndexPages: settings.GenerateIndexPages,
+	})
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestRegisterStaticFSServesBundledUI(t *testing.T) {
+	h := server.New()
+	registerStaticFS(h)
+
+	for _, path := range []string{"/static/app.js", "/static/styles.css"} {
+		want, err := os.ReadFile("./static" + strings.TrimPrefix(path, "/static"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := ut.PerformRequest(h.Engine, http.MethodGet, path, nil).Result()
+		if resp.StatusCode() != http.StatusOK {
+			t.Fatalf("GET %s 状态码 = %d，期望 %d", path, resp.StatusCode(), http.StatusOK)
+		}
+		if string(resp.Body()) != string(want) {
+			t.Errorf("GET %s 返回的内容与静态目录中的文件不一致", path)
+		}
+	}
+
+	resp := ut.PerformRequest(h.Engine, http.MethodGet, "/", nil).Result()
+	if resp.StatusCode() != http.StatusOK || !strings.Contains(string(resp.Body()), "/static/app.js") {
+		t.Errorf("GET / 状态码 = %d，期望返回引用 /static/app.js 的 index.html", resp.StatusCode())
+	}
+}
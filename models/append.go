@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrEmptyContent 追加的会议内容为空
+var ErrEmptyContent = errors.New("追加的会议内容不能为空")
+
+// AppendMeetingContent 在会议锁内将新的文本追加到会议原始内容末尾，并将会议标记为 stale（抽取结果已过期）。
+// 追加后的内容超出长度上限时返回 ErrContentTooLarge，返回追加后的内容和字符数
+func AppendMeetingContent(meetingID, text string) (string, int, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", 0, ErrEmptyContent
+	}
+
+	var content string
+	err := UpdateMeeting(meetingID, func(meetingData map[string]interface{}) (bool, error) {
+		content, _ = meetingRawContent(meetingData)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += text
+
+		if err := ValidateMeetingContent(content); err != nil {
+			return false, err
+		}
+
+		meetingData["raw_content"] = content
+		// 说话人标注版本不包含追加的内容，重新抽取时再生成
+		delete(meetingData, "speaker_content")
+		metadata, ok := meetingData["metadata"].(map[string]interface{})
+		if !ok {
+			metadata = make(map[string]interface{})
+			meetingData["metadata"] = metadata
+		}
+		metadata["stale"] = true
+		return true, nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	return content, utf8.RuneCountInString(content), nil
+}
+
+// ApplyReextractedInfo 将重新抽取的会议信息写回会议元数据，标签、归档状态等其他字段保持不变。
+// 只有抽取所用的内容仍是会议的最新内容时才清除 stale 标记并保存说话人标注版本 speakerContent（为空时删除），
+// 抽取期间又追加了内容时保持 stale
+func ApplyReextractedInfo(meetingID, extractedFrom, speakerContent string, info *MeetingMetadata) (MeetingMetadata, error) {
+	var updated MeetingMetadata
+	err := UpdateMeeting(meetingID, func(meetingData map[string]interface{}) (bool, error) {
+		metadata, ok := meetingData["metadata"].(map[string]interface{})
+		if !ok {
+			metadata = make(map[string]interface{})
+			meetingData["metadata"] = metadata
+		}
+		for key, value := range info.ToMap() {
+			metadata[key] = value
+		}
+		if content, _ := meetingRawContent(meetingData); content == extractedFrom {
+			delete(metadata, "stale")
+			if speakerContent != "" {
+				meetingData["speaker_content"] = speakerContent
+			} else {
+				delete(meetingData, "speaker_content")
+			}
+		}
+		updated = DecodeMeetingMetadata(metadata)
+		return true, nil
+	})
+	if err != nil {
+		return MeetingMetadata{}, err
+	}
+	return updated, nil
+}
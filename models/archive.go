@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// IsMeetingArchived 判断会议元数据是否标记为已归档
+func IsMeetingArchived(metadata map[string]interface{}) bool {
+	archived, _ := metadata["archived"].(bool)
+	return archived
+}
+
+// SetMeetingArchived 归档或取消归档会议，归档时记录归档时间，返回归档时间（取消归档时为空）。
+// 归档只隐藏会议列表中的会议，会议文件和关联数据保持不变
+func SetMeetingArchived(meetingID string, archived bool) (string, error) {
+	var archivedAt string
+	err := UpdateMeetingMetadata(meetingID, func(metadata map[string]interface{}) bool {
+		if IsMeetingArchived(metadata) == archived {
+			// 状态未变化时保留原来的归档时间
+			archivedAt, _ = metadata["archived_at"].(string)
+			return false
+		}
+
+		metadata["archived"] = archived
+		if archived {
+			archivedAt = time.Now().Format(time.RFC3339)
+			metadata["archived_at"] = archivedAt
+		} else {
+			delete(metadata, "archived_at")
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	return archivedAt, nil
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedArtifact 缓存在磁盘上的会议分析结果
+type cachedArtifact struct {
+	SourceHash string          `json:"source_hash"` // 生成结果时输入内容的哈希，内容变化后缓存自动失效
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// artifactCachePath 返回会议分析结果缓存文件路径
+func artifactCachePath(meetingID, kind string) string {
+	cacheDir := filepath.Join(dataDir(), "cache")
+	return filepath.Join(cacheDir, meetingID, kind+".json")
+}
+
+// HashContent 计算输入内容的哈希，用于判断缓存是否仍然有效
+func HashContent(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LoadCachedArtifact 读取缓存的会议分析结果。
+// 缓存不存在、无法解析或 sourceHash 不一致时返回 false
+func LoadCachedArtifact(meetingID, kind, sourceHash string, v interface{}) bool {
+	data, err := os.ReadFile(artifactCachePath(meetingID, kind))
+	if err != nil {
+		return false
+	}
+
+	var artifact cachedArtifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return false
+	}
+
+	if artifact.SourceHash != sourceHash {
+		return false
+	}
+
+	return json.Unmarshal(artifact.Data, v) == nil
+}
+
+// SaveCachedArtifact 将会议分析结果写入缓存
+func SaveCachedArtifact(meetingID, kind, sourceHash string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("序列化缓存数据失败: %v", err)
+	}
+
+	artifactJSON, err := json.Marshal(cachedArtifact{
+		SourceHash: sourceHash,
+		CreatedAt:  time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化缓存数据失败: %v", err)
+	}
+
+	filePath := artifactCachePath(meetingID, kind)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %v", err)
+	}
+
+	if err := os.WriteFile(filePath, artifactJSON, 0644); err != nil {
+		return fmt.Errorf("写入缓存失败: %v", err)
+	}
+
+	return nil
+}
+
+// InvalidateCachedArtifacts 删除指定会议的全部缓存结果
+func InvalidateCachedArtifacts(meetingID string) error {
+	dir := filepath.Dir(artifactCachePath(meetingID, "_"))
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("清除会议缓存失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// citationQuotePattern 回答中用「」标注的会议原文引用
+var citationQuotePattern = regexp.MustCompile(`「([^「」]+)」`)
+
+// ChatCitation 回答中引用的一处会议原文。Matched 为 false 表示引用的内容在会议原文中找不到，
+// 通常是模型改写或编造了原文，此时 Line 为0、Excerpt 为空
+type ChatCitation struct {
+	Quote   string `json:"quote"`             // 回答中引用的文字
+	Line    int    `json:"line,omitempty"`    // 引用开始处在会议原文中的行号，从1开始
+	Excerpt string `json:"excerpt,omitempty"` // 引用所在的会议原文行
+	Matched bool   `json:"matched"`
+}
+
+// citationPrompt 生成引用模式下要求模型逐字引用会议原文的提示
+func citationPrompt(maxCitations int) string {
+	return fmt.Sprintf("回答中的每个结论都要用「」逐字引用支持它的会议原文，引用必须是会议内容中连续的原话，不要改写、概括或拼接，最多引用 %d 处。", maxCitations)
+}
+
+// extractCitations 提取回答中用「」标注的引用并在会议原文中定位，相同的引用只保留一次，最多返回 maxCitations 处。
+// 比较时忽略空白，引用跨多行时定位到开始的一行
+func extractCitations(answer, transcript string, maxCitations int) []ChatCitation {
+	lines := strings.Split(transcript, "\n")
+	var joined strings.Builder
+	lineStarts := make([]int, len(lines)) // 每行在去除空白后的全文中的起始位置
+	for i, line := range lines {
+		lineStarts[i] = joined.Len()
+		joined.WriteString(removeSpaces(line))
+	}
+	text := joined.String()
+
+	citations := []ChatCitation{}
+	seen := make(map[string]bool)
+	for _, match := range citationQuotePattern.FindAllStringSubmatch(answer, -1) {
+		if len(citations) >= maxCitations {
+			break
+		}
+		quote := strings.TrimSpace(match[1])
+		normalized := removeSpaces(quote)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		citation := ChatCitation{Quote: quote}
+		if pos := strings.Index(text, normalized); pos >= 0 {
+			line := lineAt(lineStarts, pos)
+			citation.Line = line + 1
+			citation.Excerpt = strings.TrimSpace(lines[line])
+			citation.Matched = true
+		}
+		citations = append(citations, citation)
+	}
+	return citations
+}
+
+// lineAt 返回全文位置 pos 所在的行下标。空行与下一行的起始位置相同，
+// 取最后一个起始位置不大于 pos 的行即为实际包含该位置的行
+func lineAt(lineStarts []int, pos int) int {
+	line := 0
+	for i, start := range lineStarts {
+		if start > pos {
+			break
+		}
+		line = i
+	}
+	return line
+}
+
+// removeSpaces 去除所有空白字符
+func removeSpaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// 聊天回答长度（字符数）与引用数量的默认值和上限
+const (
+	DefaultMaxAnswerLength = 500
+	MaxAnswerLengthLimit   = 2000
+	minAnswerLength        = 20
+	DefaultMaxCitations    = 3
+	MaxCitationsLimit      = 10
+)
+
+// NormalizeChatLimits 规范化回答长度和引用数量，超出范围时取边界值。
+// 回答长度不大于0、引用数量小于0时视为未设置，使用默认值
+func NormalizeChatLimits(maxAnswerLength, maxCitations int) (int, int) {
+	switch {
+	case maxAnswerLength <= 0:
+		maxAnswerLength = DefaultMaxAnswerLength
+	case maxAnswerLength < minAnswerLength:
+		maxAnswerLength = minAnswerLength
+	case maxAnswerLength > MaxAnswerLengthLimit:
+		maxAnswerLength = MaxAnswerLengthLimit
+	}
+
+	switch {
+	case maxCitations < 0:
+		maxCitations = DefaultMaxCitations
+	case maxCitations > MaxCitationsLimit:
+		maxCitations = MaxCitationsLimit
+	}
+
+	return maxAnswerLength, maxCitations
+}
+
+// chatLimitPrompt 生成约束回答详略和引用数量的提示，cite 为 true 时要求逐字引用会议原文
+func chatLimitPrompt(maxAnswerLength, maxCitations int, cite bool) string {
+	citationRule := fmt.Sprintf("引用会议原文时用「」标注，最多引用 %d 处。", maxCitations)
+	switch {
+	case cite:
+		citationRule = citationPrompt(maxCitations)
+	case maxCitations == 0:
+		citationRule = "不要引用会议原文。"
+	}
+	return fmt.Sprintf("回答不超过 %d 字，篇幅较短时只给出结论。%s", maxAnswerLength, citationRule)
+}
+
+// answerLimiter 按字符数截断流式回答，保证截断发生在句子边界。
+// 未确认句子结束的文本会暂存，直到出现句子边界或流结束
+type answerLimiter struct {
+	maxRunes int
+	emitted  int
+	pending  []rune
+	done     bool
+}
+
+// newAnswerLimiter 创建回答截断器
+func newAnswerLimiter(maxRunes int) *answerLimiter {
+	return &answerLimiter{maxRunes: maxRunes}
+}
+
+// Push 追加一段模型输出，返回可以立即发送的文本；达到长度上限时 reachedLimit 为 true，之后的输出应被丢弃
+func (l *answerLimiter) Push(chunk string) (out string, reachedLimit bool) {
+	if l.done {
+		return "", true
+	}
+
+	l.pending = append(l.pending, []rune(chunk)...)
+
+	if l.emitted+len(l.pending) <= l.maxRunes {
+		// 未超出上限，发送到最后一个句子边界为止
+		if end := lastSentenceEnd(l.pending, len(l.pending)); end > 0 {
+			return l.take(end), false
+		}
+		return "", false
+	}
+
+	// 超出上限，在上限内最后一个句子边界处收尾
+	l.done = true
+	if end := lastSentenceEnd(l.pending, l.maxRunes-l.emitted); end > 0 {
+		return l.take(end), true
+	}
+
+	// 已发送内容本身就以完整句子结束，丢弃未完成的句子
+	if l.emitted > 0 {
+		l.pending = nil
+		return "", true
+	}
+
+	// 整个回答只有一个超长句子，只能按长度截断
+	out = string(l.pending[:l.maxRunes-1]) + "…"
+	l.emitted = l.maxRunes
+	l.pending = nil
+	return out, true
+}
+
+// Flush 流正常结束时返回暂存的剩余文本
+func (l *answerLimiter) Flush() string {
+	if l.done {
+		return ""
+	}
+	return l.take(len(l.pending))
+}
+
+// take 取出暂存区前 n 个字符
+func (l *answerLimiter) take(n int) string {
+	out := string(l.pending[:n])
+	l.pending = l.pending[n:]
+	l.emitted += n
+	return out
+}
+
+// lastSentenceEnd 返回 runes[:limit] 中最后一个句子结束位置（不含），没有时返回0。
+// 句末标点后紧跟的右引号、右括号计入该句
+func lastSentenceEnd(runes []rune, limit int) int {
+	if limit > len(runes) {
+		limit = len(runes)
+	}
+
+	for i := limit - 1; i >= 0; i-- {
+		if !isSentenceEnd(runes, i) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && end < limit && isClosingPunct(runes[end]) {
+			end++
+		}
+		return end
+	}
+	return 0
+}
+
+// isSentenceEnd 判断 runes[i] 是否为句子结束标点
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '。', '！', '？', '；', '!', '?', ';', '\n', '…':
+		return true
+	case '.':
+		// 英文句号后需跟空白才视为句末，避免截断小数和缩写；位于末尾时等待后续输出再判断
+		return i+1 < len(runes) && unicode.IsSpace(runes[i+1])
+	}
+	return false
+}
+
+// isClosingPunct 判断是否为右引号或右括号
+func isClosingPunct(r rune) bool {
+	switch r {
+	case '”', '’', '」', '』', '）', ')', '"', '\'':
+		return true
+	}
+	return false
+}
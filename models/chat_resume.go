@@ -0,0 +1,191 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hertz-contrib/sse"
+)
+
+// defaultChatResumeTTL 回答结束后缓冲区的默认保留时长
+const defaultChatResumeTTL = time.Minute
+
+// chatStreams 正在生成或刚结束的聊天回答缓冲区，按流ID索引
+var chatStreams = struct {
+	sync.Mutex
+	m map[string]*ChatStreamBuffer
+}{m: make(map[string]*ChatStreamBuffer)}
+
+// ChatStreamBuffer 缓存一次聊天回答已生成的事件，使断线重连的客户端可以按 Last-Event-ID 续传。
+// 作为 EventPublisher 传给 ChatMessage.Process 时写入不会失败。客户端断开后回答继续生成，
+// 保留时长内没有客户端重新连接时取消生成
+type ChatStreamBuffer struct {
+	id     string
+	mu     sync.Mutex
+	events []*sse.Event
+	done   bool
+	notify chan struct{} // 有新事件或回答结束时关闭并替换
+
+	cancel      context.CancelFunc // 取消回答生成
+	followers   int                // 正在接收事件的客户端数
+	cancelTimer *time.Timer        // 没有客户端时等待重连的计时器
+}
+
+// GetChatResumeTTL 获取回答结束后缓冲区的保留时长，未配置时为60秒
+func GetChatResumeTTL() time.Duration {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.Stream.ResumeTTLSeconds <= 0 {
+		return defaultChatResumeTTL
+	}
+	return time.Duration(cfg.Stream.ResumeTTLSeconds) * time.Second
+}
+
+// NewChatStreamBuffer 创建并登记一个回答缓冲区，回答结束后需调用 Finish。
+// 所有客户端断开且保留时长内没有客户端重连时调用 cancel 取消回答生成
+func NewChatStreamBuffer(cancel context.CancelFunc) *ChatStreamBuffer {
+	id := strconv.FormatInt(time.Now().UnixNano(), 36)
+	randomBytes := make([]byte, 8)
+	if _, err := rand.Read(randomBytes); err == nil {
+		id = hex.EncodeToString(randomBytes)
+	}
+	b := &ChatStreamBuffer{
+		id:     id,
+		notify: make(chan struct{}),
+		cancel: cancel,
+	}
+
+	chatStreams.Lock()
+	chatStreams.m[b.id] = b
+	chatStreams.Unlock()
+	return b
+}
+
+// FindChatStreamBuffer 按 Last-Event-ID（格式为 "流ID:序号"）查找回答缓冲区，返回客户端已收到的事件数。
+// 缓冲区不存在或已过期时返回 false
+func FindChatStreamBuffer(lastEventID string) (*ChatStreamBuffer, int, bool) {
+	id, seqStr, ok := strings.Cut(strings.TrimSpace(lastEventID), ":")
+	if !ok {
+		return nil, 0, false
+	}
+	seq, err := strconv.Atoi(seqStr)
+	if err != nil || seq < 0 {
+		return nil, 0, false
+	}
+
+	chatStreams.Lock()
+	b, ok := chatStreams.m[id]
+	chatStreams.Unlock()
+	return b, seq, ok
+}
+
+// Publish 为事件分配 "流ID:序号" 形式的ID并追加到缓冲区，序号从1开始
+func (b *ChatStreamBuffer) Publish(event *sse.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done {
+		return nil
+	}
+	buffered := *event
+	buffered.ID = b.id + ":" + strconv.Itoa(len(b.events)+1)
+	b.events = append(b.events, &buffered)
+	b.wakeLocked()
+	return nil
+}
+
+// Finish 标记回答结束，缓冲区在保留时长后删除
+func (b *ChatStreamBuffer) Finish() {
+	b.mu.Lock()
+	b.done = true
+	b.wakeLocked()
+	if b.cancelTimer != nil {
+		b.cancelTimer.Stop()
+		b.cancelTimer = nil
+	}
+	b.mu.Unlock()
+
+	time.AfterFunc(GetChatResumeTTL(), func() {
+		chatStreams.Lock()
+		delete(chatStreams.m, b.id)
+		chatStreams.Unlock()
+	})
+}
+
+// wakeLocked 唤醒等待新事件的 Follow，调用方需持有锁
+func (b *ChatStreamBuffer) wakeLocked() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// attach 登记一个接收事件的客户端，停止等待重连的计时器
+func (b *ChatStreamBuffer) attach() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.followers++
+	if b.cancelTimer != nil {
+		b.cancelTimer.Stop()
+		b.cancelTimer = nil
+	}
+}
+
+// detach 注销一个客户端。回答未结束且没有其他客户端时开始计时，
+// 保留时长内没有客户端重连则取消回答生成
+func (b *ChatStreamBuffer) detach() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.followers--
+	if b.followers > 0 || b.done || b.cancel == nil {
+		return
+	}
+	b.cancelTimer = time.AfterFunc(GetChatResumeTTL(), func() {
+		b.mu.Lock()
+		abandoned := b.followers == 0 && !b.done
+		b.mu.Unlock()
+		if abandoned {
+			fmt.Printf("聊天回答 %s 在保留时长内没有客户端重连，取消生成\n", b.id)
+			b.cancel()
+		}
+	})
+}
+
+// Follow 从第 after 个事件之后开始向 stream 发送缓冲的事件，并继续转发新生成的事件直到回答结束。
+// 发送失败（客户端断开）或 ctx 取消时返回错误，此时回答仍在缓冲区中继续生成，等待客户端重连
+func (b *ChatStreamBuffer) Follow(ctx context.Context, after int, stream EventPublisher) error {
+	b.attach()
+	defer b.detach()
+
+	for {
+		b.mu.Lock()
+		if after > len(b.events) {
+			after = len(b.events)
+		}
+		pending := b.events[after:]
+		done := b.done
+		notify := b.notify
+		b.mu.Unlock()
+
+		for _, event := range pending {
+			if err := stream.Publish(event); err != nil {
+				return err
+			}
+			after++
+		}
+		if len(pending) > 0 {
+			continue
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrContentTooLarge 会议内容过长，无法抽取
+var ErrContentTooLarge = errors.New("会议内容过长")
+
+// 会议内容长度的默认限制，按字符计算
+const (
+	defaultChunkChars      = 12000
+	defaultMaxContentChars = 200000
+)
+
+// sentenceEnds 单行过长时用于切分的句末标点
+const sentenceEnds = "。！？；.!?;"
+
+// ExtractionSettings 会议信息抽取的长度限制
+type ExtractionSettings struct {
+	ChunkChars      int // 超过该长度的会议内容分段抽取后合并，每段不超过该长度
+	MaxContentChars int // 会议内容的最大长度，超出时拒绝创建会议
+}
+
+// GetExtractionSettings 获取会议信息抽取的长度限制，未配置时使用默认值
+func GetExtractionSettings() ExtractionSettings {
+	settings := ExtractionSettings{
+		ChunkChars:      defaultChunkChars,
+		MaxContentChars: defaultMaxContentChars,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+	if cfg.Extraction.ChunkChars > 0 {
+		settings.ChunkChars = cfg.Extraction.ChunkChars
+	}
+	if cfg.Extraction.MaxContentChars > 0 {
+		settings.MaxContentChars = cfg.Extraction.MaxContentChars
+	}
+	return settings
+}
+
+// ValidateMeetingContent 检查会议内容能否被抽取：总长度不超过上限，且超过分段长度时能按行或句子切分
+func ValidateMeetingContent(text string) error {
+	settings := GetExtractionSettings()
+	if length := utf8.RuneCountInString(text); length > settings.MaxContentChars {
+		return fmt.Errorf("%w: 共 %d 个字符，最多 %d 个字符", ErrContentTooLarge, length, settings.MaxContentChars)
+	}
+	_, err := SplitContent(text, settings.ChunkChars)
+	return err
+}
+
+// SplitContent 将会议内容按行切分为不超过 chunkChars 个字符的分段，单行过长时再按句子切分。
+// 某一句仍超过 chunkChars 时无法在不截断语义的情况下切分，返回 ErrContentTooLarge
+func SplitContent(text string, chunkChars int) ([]string, error) {
+	if utf8.RuneCountInString(text) <= chunkChars {
+		return []string{text}, nil
+	}
+
+	var segments []string
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if utf8.RuneCountInString(line) <= chunkChars {
+			segments = append(segments, line)
+			continue
+		}
+		for _, sentence := range splitSentences(line) {
+			if utf8.RuneCountInString(sentence) > chunkChars {
+				return nil, fmt.Errorf("%w: 存在超过 %d 个字符且无法按行或句子切分的段落", ErrContentTooLarge, chunkChars)
+			}
+			segments = append(segments, sentence)
+		}
+	}
+
+	// 按顺序把分段装入不超过长度上限的块
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+	for _, segment := range segments {
+		segmentLen := utf8.RuneCountInString(segment)
+		if currentLen+segmentLen > chunkChars && currentLen > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+		current.WriteString(segment)
+		currentLen += segmentLen
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks, nil
+}
+
+// splitSentences 在句末标点之后切分文本，标点保留在前一句中
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		if strings.ContainsRune(sentenceEnds, r) {
+			end := i + utf8.RuneLen(r)
+			sentences = append(sentences, text[start:end])
+			start = end
+		}
+	}
+	if start < len(text) {
+		sentences = append(sentences, text[start:])
+	}
+	return sentences
+}
+
+// extractMeetingInfoChunked 分段抽取会议信息后合并：每段单独抽取，再合并参会人员、待办事项等字段，
+// 并让模型把各段摘要合并为整体摘要
+func extractMeetingInfoChunked(ctx context.Context, chunks []string, meetingType string) (MeetingMetadata, error) {
+	parts := make([]MeetingMetadata, 0, len(chunks))
+	for i, chunk := range chunks {
+		if err := ctx.Err(); err != nil {
+			return MeetingMetadata{}, err
+		}
+
+		text := fmt.Sprintf("以下是会议记录的第 %d/%d 部分：\n%s", i+1, len(chunks), chunk)
+		info, err := extractMeetingInfoOnce(ctx, text, meetingType)
+		if err != nil {
+			return MeetingMetadata{}, fmt.Errorf("抽取第 %d/%d 部分失败: %v", i+1, len(chunks), err)
+		}
+		parts = append(parts, info)
+	}
+
+	merged := mergeMeetingInfos(parts)
+
+	var summaries []string
+	for _, part := range parts {
+		if part.Summary != "" {
+			summaries = append(summaries, part.Summary)
+		}
+	}
+	if len(summaries) > 1 {
+		summary, err := mergeChunkSummaries(ctx, summaries, getMeetingTypeProfile(merged.MeetingType).SummaryChars)
+		if err != nil {
+			// 合并失败时保留各段摘要的拼接，不影响会议创建
+			Logf(ctx, "合并分段摘要失败: %v\n", err)
+			summary = strings.Join(summaries, "\n")
+		}
+		merged.Summary = summary
+	}
+
+	return merged, nil
+}
+
+// mergeMeetingInfos 合并各段的抽取结果：标题、描述、开始时间和摘要取第一个非空值，结束时间取最后一个，
+// 参会人员和待办事项按出现顺序去重合并，会议类型取各段分类中出现次数最多的一个
+func mergeMeetingInfos(parts []MeetingMetadata) MeetingMetadata {
+	merged := MeetingMetadata{
+		Participants: []string{},
+		TodoList:     []ExtractedTodo{},
+	}
+
+	seenParticipants := make(map[string]bool)
+	seenTodos := make(map[string]bool)
+	typeVotes := make(map[string]int)
+
+	for _, part := range parts {
+		if merged.Title == "" && part.Title != defaultMeetingTitle {
+			merged.Title = part.Title
+		}
+		if merged.Description == "" {
+			merged.Description = part.Description
+		}
+		if merged.StartTime == "" {
+			merged.StartTime = part.StartTime
+		}
+		if merged.Summary == "" {
+			merged.Summary = part.Summary
+		}
+		if part.EndTime != "" {
+			merged.EndTime = part.EndTime
+		}
+		// 次数相同时取先出现的类型
+		if part.MeetingType != "" {
+			typeVotes[part.MeetingType]++
+			if typeVotes[part.MeetingType] > typeVotes[merged.MeetingType] {
+				merged.MeetingType = part.MeetingType
+			}
+		}
+
+		for _, name := range part.Participants {
+			key := normalizeParticipantName(name)
+			if seenParticipants[key] {
+				continue
+			}
+			seenParticipants[key] = true
+			merged.Participants = append(merged.Participants, name)
+		}
+
+		// 待办按任务内容去重
+		for _, todo := range part.TodoList {
+			if seenTodos[todo.Task] {
+				continue
+			}
+			seenTodos[todo.Task] = true
+			merged.TodoList = append(merged.TodoList, todo)
+		}
+	}
+
+	return merged
+}
+
+// mergeChunkSummaries 使用LLM将各段摘要合并为不超过 maxChars 字的整体摘要
+func mergeChunkSummaries(ctx context.Context, summaries []string, maxChars int) (string, error) {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureExtract, 0.3)
+	if err != nil {
+		return "", fmt.Errorf("创建LLM客户端失败: %v", err)
+	}
+
+	var sb strings.Builder
+	for i, summary := range summaries {
+		sb.WriteString(fmt.Sprintf("第%d部分: %s\n", i+1, summary))
+	}
+
+	messages := []*schema.Message{
+		schema.SystemMessage(fmt.Sprintf("你是一个专业的会议分析助手。以下是同一场会议按顺序分段后的各部分摘要，请合并为一段不超过%d字的会议整体摘要，只输出摘要内容。", maxChars)),
+		schema.UserMessage(sb.String()),
+	}
+
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	response, err := chatModel.Generate(callCtx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return "", err
+	}
+
+	summary, exceeded := newOutputGuard().Push(strings.TrimSpace(response.Content))
+	if exceeded {
+		summary += truncatedMarker
+	}
+	return summary, nil
+}
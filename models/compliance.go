@@ -0,0 +1,339 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 合规告警级别
+const (
+	AlertLevelInfo     = "info"
+	AlertLevelWarning  = "warning"
+	AlertLevelCritical = "critical"
+)
+
+// defaultComplianceContextChars 命中位置前后截取的上下文字符数
+const defaultComplianceContextChars = 30
+
+// alertLevelOrder 告警级别的排序权重
+var alertLevelOrder = map[string]int{
+	AlertLevelCritical: 3,
+	AlertLevelWarning:  2,
+	AlertLevelInfo:     1,
+}
+
+// ComplianceRule 一条敏感内容匹配规则，Keywords 与 Pattern 任一命中即视为命中
+type ComplianceRule struct {
+	Name     string   `json:"name"`     // 规则名称，例如 "泄密"
+	Level    string   `json:"level"`    // 告警级别: info / warning / critical
+	Keywords []string `json:"keywords"` // 敏感词，大小写不敏感
+	Pattern  string   `json:"pattern"`  // 正则表达式，可选
+}
+
+// ComplianceHit 一次敏感内容命中
+type ComplianceHit struct {
+	Rule    string `json:"rule"`
+	Level   string `json:"level"`
+	Match   string `json:"match"`   // 命中的文本
+	Snippet string `json:"snippet"` // 命中位置的上下文片段
+}
+
+// ComplianceRecord 一次扫描的命中记录
+type ComplianceRecord struct {
+	MeetingID string          `json:"meeting_id"`
+	Source    string          `json:"source"` // 内容来源，例如 meeting / chat
+	Hits      []ComplianceHit `json:"hits"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ComplianceSettings 合规扫描的运行参数
+type ComplianceSettings struct {
+	Enabled       bool
+	Channel       string
+	MinAlertLevel string // 低于该级别的命中只记录不推送
+	ContextChars  int
+	Rules         []ComplianceRule
+	Allowlist     []string // 包含敏感词的正常说法，位于其中的命中会被忽略
+}
+
+// compiledRule 预编译后的匹配规则
+type compiledRule struct {
+	rule    ComplianceRule
+	pattern *regexp.Regexp
+}
+
+// complianceRecordsMutex 保护合规记录文件的读写
+var complianceRecordsMutex sync.Mutex
+
+// GetComplianceSettings 获取合规扫描配置，配置加载失败或未配置规则时视为未启用
+func GetComplianceSettings() ComplianceSettings {
+	settings := ComplianceSettings{
+		MinAlertLevel: AlertLevelWarning,
+		ContextChars:  defaultComplianceContextChars,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+
+	settings.Enabled = cfg.Compliance.Enabled && len(cfg.Compliance.Rules) > 0
+	settings.Channel = cfg.Compliance.Channel
+	settings.Rules = cfg.Compliance.Rules
+	settings.Allowlist = cfg.Compliance.Allowlist
+	if _, ok := alertLevelOrder[cfg.Compliance.MinAlertLevel]; ok {
+		settings.MinAlertLevel = cfg.Compliance.MinAlertLevel
+	}
+	if cfg.Compliance.ContextChars > 0 {
+		settings.ContextChars = cfg.Compliance.ContextChars
+	}
+
+	return settings
+}
+
+// ScanCompliance 按规则扫描文本，返回按告警级别从高到低排列的命中。
+// 同一规则的相同命中文本只保留第一次出现的位置
+func ScanCompliance(text string, settings ComplianceSettings) ([]ComplianceHit, error) {
+	rules, err := compileComplianceRules(settings.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := allowlistSpans(text, settings.Allowlist)
+
+	var hits []ComplianceHit
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		for _, loc := range r.pattern.FindAllStringIndex(text, -1) {
+			if insideSpans(loc, allowed) {
+				continue
+			}
+
+			match := text[loc[0]:loc[1]]
+			key := r.rule.Name + "|" + strings.ToLower(match)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			hits = append(hits, ComplianceHit{
+				Rule:    r.rule.Name,
+				Level:   r.rule.Level,
+				Match:   match,
+				Snippet: snippetAround(text, loc, settings.ContextChars),
+			})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return alertLevelOrder[hits[i].Level] > alertLevelOrder[hits[j].Level]
+	})
+
+	return hits, nil
+}
+
+// CheckCompliance 扫描会议相关内容，命中时记录并在达到告警级别时推送给合规负责人。
+// 扫描或告警失败只记录日志，不影响调用方的正常流程
+func CheckCompliance(meetingID, source, text string) {
+	settings := GetComplianceSettings()
+	if !settings.Enabled || strings.TrimSpace(text) == "" {
+		return
+	}
+
+	hits, err := ScanCompliance(text, settings)
+	if err != nil {
+		fmt.Printf("合规扫描失败: %v\n", err)
+		return
+	}
+	if len(hits) == 0 {
+		return
+	}
+
+	record := ComplianceRecord{
+		MeetingID: meetingID,
+		Source:    source,
+		Hits:      hits,
+		CreatedAt: time.Now(),
+	}
+	if err := saveComplianceRecord(record); err != nil {
+		fmt.Printf("保存合规记录失败: %v\n", err)
+	}
+
+	// 只推送达到告警级别的命中，避免低级别命中打扰合规负责人
+	var alertHits []ComplianceHit
+	for _, hit := range hits {
+		if alertLevelOrder[hit.Level] >= alertLevelOrder[settings.MinAlertLevel] {
+			alertHits = append(alertHits, hit)
+		}
+	}
+	if len(alertHits) == 0 {
+		return
+	}
+
+	notifier, err := NewNotifier(settings.Channel)
+	if err != nil {
+		fmt.Printf("创建合规告警推送渠道失败: %v\n", err)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("会议 %s（%s）命中 %d 条敏感内容：\n", meetingID, source, len(alertHits)))
+	for i, hit := range alertHits {
+		sb.WriteString(fmt.Sprintf("%d. [%s] %s：「%s」\n   上下文：%s\n", i+1, hit.Level, hit.Rule, hit.Match, hit.Snippet))
+	}
+
+	if err := notifier.SendText("会议合规告警", sb.String()); err != nil {
+		fmt.Printf("发送合规告警失败: %v\n", err)
+	}
+}
+
+// GetComplianceRecords 获取会议的合规命中记录
+func GetComplianceRecords(meetingID string) ([]ComplianceRecord, error) {
+	if !validMeetingID(meetingID) {
+		return nil, ErrMeetingNotFound
+	}
+
+	complianceRecordsMutex.Lock()
+	defer complianceRecordsMutex.Unlock()
+
+	return loadComplianceRecords(meetingID)
+}
+
+// complianceRecordPath 返回会议合规记录文件路径
+func complianceRecordPath(meetingID string) string {
+	return filepath.Join(dataDir(), "compliance", meetingID+".json")
+}
+
+// loadComplianceRecords 读取会议合规记录，调用方需持有锁
+func loadComplianceRecords(meetingID string) ([]ComplianceRecord, error) {
+	records := []ComplianceRecord{}
+
+	data, err := os.ReadFile(complianceRecordPath(meetingID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("读取合规记录失败: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("解析合规记录失败: %v", err)
+	}
+
+	return records, nil
+}
+
+// saveComplianceRecord 追加一条会议合规记录
+func saveComplianceRecord(record ComplianceRecord) error {
+	if !validMeetingID(record.MeetingID) {
+		return fmt.Errorf("无效的会议ID: %s", record.MeetingID)
+	}
+
+	complianceRecordsMutex.Lock()
+	defer complianceRecordsMutex.Unlock()
+
+	records, err := loadComplianceRecords(record.MeetingID)
+	if err != nil {
+		return err
+	}
+	records = append(records, record)
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("序列化合规记录失败: %v", err)
+	}
+
+	filePath := complianceRecordPath(record.MeetingID)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("创建合规记录目录失败: %v", err)
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// compileComplianceRules 将敏感词和正则合并编译为大小写不敏感的正则
+func compileComplianceRules(rules []ComplianceRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		var alternatives []string
+		for _, keyword := range rule.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				alternatives = append(alternatives, regexp.QuoteMeta(keyword))
+			}
+		}
+		if rule.Pattern != "" {
+			alternatives = append(alternatives, "(?:"+rule.Pattern+")")
+		}
+		if len(alternatives) == 0 {
+			continue
+		}
+
+		pattern, err := regexp.Compile("(?i)" + strings.Join(alternatives, "|"))
+		if err != nil {
+			return nil, fmt.Errorf("规则 %s 的正则表达式无效: %v", rule.Name, err)
+		}
+
+		if _, ok := alertLevelOrder[rule.Level]; !ok {
+			rule.Level = AlertLevelWarning
+		}
+		compiled = append(compiled, compiledRule{rule: rule, pattern: pattern})
+	}
+	return compiled, nil
+}
+
+// allowlistSpans 返回文本中白名单说法所在的字节区间
+func allowlistSpans(text string, allowlist []string) [][]int {
+	var spans [][]int
+	lower := strings.ToLower(text)
+	for _, phrase := range allowlist {
+		phrase = strings.ToLower(strings.TrimSpace(phrase))
+		if phrase == "" {
+			continue
+		}
+		for start := 0; ; {
+			idx := strings.Index(lower[start:], phrase)
+			if idx < 0 {
+				break
+			}
+			spans = append(spans, []int{start + idx, start + idx + len(phrase)})
+			start += idx + len(phrase)
+		}
+	}
+	return spans
+}
+
+// insideSpans 判断命中区间是否完全落在某个白名单区间内
+func insideSpans(loc []int, spans [][]int) bool {
+	for _, span := range spans {
+		if loc[0] >= span[0] && loc[1] <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// snippetAround 截取命中位置前后 contextChars 个字符作为上下文
+func snippetAround(text string, loc []int, contextChars int) string {
+	before := []rune(text[:loc[0]])
+	after := []rune(text[loc[1]:])
+
+	prefix, suffix := "", ""
+	if len(before) > contextChars {
+		before = before[len(before)-contextChars:]
+		prefix = "…"
+	}
+	if len(after) > contextChars {
+		after = after[:contextChars]
+		suffix = "…"
+	}
+
+	snippet := prefix + string(before) + text[loc[0]:loc[1]] + string(after) + suffix
+	return strings.Join(strings.Fields(snippet), " ")
+}
@@ -1,60 +1,283 @@
 package models
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Config 应用程序配置信息
 type Config struct {
-	ARK struct {
-		APIKey    string `json:"api_key"`
-		ModelName string `json:"model_name"`
+	Provider string `json:"provider"` // 模型提供方: ark（默认）、openai 或 mock（离线模拟），环境变量 MOCK_LLM=true 时固定为 mock
+	ARK      struct {
+		APIKey               string   `json:"api_key"`
+		ModelName            string   `json:"model_name"`
+		KnownModels          []string `json:"known_models"`            // 可用的模型名称，非空时启动时校验 model_name 是否在列表中
+		PromptPricePer1K     float64  `json:"prompt_price_per_1k"`     // 每千个输入token的单价，用于估算费用
+		CompletionPricePer1K float64  `json:"completion_price_per_1k"` // 每千个输出token的单价
 	} `json:"ark"`
+	OpenAI struct {
+		APIKey               string   `json:"api_key"`
+		BaseURL              string   `json:"base_url"` // OpenAI 兼容接口地址，默认 https://api.openai.com/v1
+		ModelName            string   `json:"model_name"`
+		KnownModels          []string `json:"known_models"`
+		PromptPricePer1K     float64  `json:"prompt_price_per_1k"`
+		CompletionPricePer1K float64  `json:"completion_price_per_1k"`
+	} `json:"openai"`
+	LLM struct {
+		TimeoutSeconds int `json:"timeout_seconds"`  // 单次模型调用的超时时间，默认60秒
+		MaxOutputChars int `json:"max_output_chars"` // 单次模型调用的最大输出字符数，超出后截断，默认20000
+		MaxConcurrent  int `json:"max_concurrent"`   // 全局同时进行的模型调用数上限，超出时排队等待，默认不限制
+	} `json:"llm"`
+	Storage struct {
+		MeetingsDir string `json:"meetings_dir"` // 会议文件目录，环境变量 MEETINGS_DIR 优先
+		TodoDB      string `json:"todo_db"`      // 待办事项数据库文件，环境变量 TODO_DB 优先
+		DataDir     string `json:"data_dir"`     // 缓存、模板等其他数据的目录，环境变量 DATA_DIR 优先
+		Compress    bool   `json:"compress"`     // 是否以 gzip 压缩保存会议文件（.json.gz），默认不压缩
+		Backend     string `json:"backend"`      // 会议文件的存储后端: filesystem（默认）、s3 或 sqlite，环境变量 STORAGE_BACKEND 优先
+		S3          struct {
+			Endpoint        string `json:"endpoint"`          // S3 兼容接口地址，例如 https://s3.us-east-1.amazonaws.com 或 http://minio:9000
+			Region          string `json:"region"`            // 签名使用的区域，默认 us-east-1
+			Bucket          string `json:"bucket"`            // 存储桶名称
+			Prefix          string `json:"prefix"`            // 会议文件的对象键前缀，默认 meetings/
+			AccessKeyID     string `json:"access_key_id"`     // 未配置时使用环境变量 AWS_ACCESS_KEY_ID
+			SecretAccessKey string `json:"secret_access_key"` // 未配置时使用环境变量 AWS_SECRET_ACCESS_KEY
+			PathStyle       bool   `json:"path_style"`        // 使用 endpoint/bucket/key 形式的地址，MinIO 等自建服务通常需要开启
+			TimeoutSeconds  int    `json:"timeout_seconds"`   // 单次请求的超时时间，默认30秒
+		} `json:"s3"`
+	} `json:"storage"`
 	FeiShu struct {
 		WebhookURL string `json:"webhook_url"`
 	} `json:"feishu"`
+	Report struct {
+		Sections []ReportSection `json:"sections"` // 推送报告的区块及顺序，未配置时使用默认模板
+	} `json:"report"`
+	WeChatWork struct {
+		WebhookURL string `json:"webhook_url"` // 形如 https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=...
+	} `json:"wechat_work"`
+	Slack struct {
+		WebhookURL string `json:"webhook_url"` // 未配置时读取 SLACK_WEBHOOK_URL 环境变量
+	} `json:"slack"`
+	Queue struct {
+		Workers    int      `json:"workers"`     // 消费会议抽取任务的worker数量
+		MaxPending int      `json:"max_pending"` // 队列最大积压任务数，超出后拒绝新请求
+		VIPTokens  []string `json:"vip_tokens"`  // 优先处理的调用方访问令牌，通过请求头 Authorization: Bearer <token> 识别
+	} `json:"queue"`
+	Todo struct {
+		DefaultPriority int    `json:"default_priority"` // 新建待办的默认优先级: 1高、2中（默认）、3低
+		DefaultStatus   string `json:"default_status"`   // 新建待办的默认状态代码，默认 NOT_STARTED，兼容中文状态
+		DefaultAssignee string `json:"default_assignee"` // 从会议中抽取的待办无法确定负责人时的默认负责人，first_participant 表示第一位参会人员
+		// 为 true 时允许在子任务未全部完成时将父待办标记为已完成，默认不允许
+		AllowIncompleteSubtasks bool `json:"allow_incomplete_subtasks"`
+		// 每个会议自动创建的待办数上限，超出的待办不写入数据库，记录在会议元数据的 untracked_todos 中，默认30
+		MaxPerMeeting int `json:"max_per_meeting"`
+	} `json:"todo"`
+	Reminder struct {
+		Enabled         bool   `json:"enabled"`          // 是否启用待办到期提醒
+		IntervalSeconds int    `json:"interval_seconds"` // 扫描间隔，默认300秒
+		WithinMinutes   int    `json:"within_minutes"`   // 提前提醒的时间窗口，0表示只提醒已逾期的待办
+		Channel         string `json:"channel"`          // 推送渠道，默认飞书
+	} `json:"reminder"`
+	Extraction struct {
+		ChunkChars      int `json:"chunk_chars"`       // 超过该字符数的会议内容分段抽取后合并，默认12000
+		MaxContentChars int `json:"max_content_chars"` // 会议内容的最大字符数，超出时拒绝创建，默认200000
+		// 为 true 时在抽取前为没有说话人标签的会议记录调用模型标注说话人，会增加一次模型调用，默认关闭
+		Diarization bool `json:"diarization"`
+	} `json:"extraction"`
+	Meeting struct {
+		NoTodoStatus        string `json:"no_todo_status"`        // 没有关联待办的会议状态: closed（默认）或 n/a
+		IdempotencyTTLHours int    `json:"idempotency_ttl_hours"` // 创建会议的幂等键保留时长，默认24小时
+	} `json:"meeting"`
+	Dedup struct {
+		Enabled             bool    `json:"enabled"`              // 创建会议时是否检查重复内容，默认关闭
+		LookbackDays        int     `json:"lookback_days"`        // 只与最近多少天内创建的会议比较，默认30天
+		SimilarityThreshold float64 `json:"similarity_threshold"` // 内容相似度达到该值（0-1）视为重复，默认0.9；设为1时只检查完全相同的内容
+	} `json:"dedup"`
+	Compliance struct {
+		Enabled       bool             `json:"enabled"`         // 是否启用敏感内容扫描
+		Channel       string           `json:"channel"`         // 告警推送渠道，默认飞书
+		MinAlertLevel string           `json:"min_alert_level"` // 推送告警的最低级别，默认 warning
+		ContextChars  int              `json:"context_chars"`   // 告警中附带的上下文字符数，默认30
+		Rules         []ComplianceRule `json:"rules"`
+		Allowlist     []string         `json:"allowlist"` // 包含敏感词的正常说法，用于减少误报
+	} `json:"compliance"`
+	Encryption struct {
+		Enabled bool              `json:"enabled"` // 是否加密存储会议文件中的敏感字段
+		Fields  []string          `json:"fields"`  // 加密的字段路径，默认 ["raw_content", "speaker_content"]
+		KeyID   string            `json:"key_id"`  // 加密新数据使用的密钥ID
+		Keys    map[string]string `json:"keys"`    // 密钥ID到base64编码的32字节密钥，轮换后保留旧密钥用于解密
+	} `json:"encryption"`
+	MultiRoleplay struct {
+		MaxRounds      int `json:"max_rounds"`      // 多角色扮演的最大讨论轮数，默认10
+		MaxSpecialists int `json:"max_specialists"` // 多角色扮演的最大专家人数，默认12
+		HistoryWindow  int `json:"history_window"`  // 后续轮次上下文中保留原文的最近发言条数，更早的发言合并为摘要，默认12
+		// 开启提前结束时，本轮发言与之前发言的平均相似度达到该值即结束讨论，默认0.6
+		EarlyStopSimilarity float64 `json:"early_stop_similarity"`
+		SummaryMinChars     int     `json:"summary_min_chars"` // 讨论总结（narrative 格式）的最少字数，默认300
+		SummaryMaxChars     int     `json:"summary_max_chars"` // 讨论总结的最多字数，默认500
+	} `json:"multi_roleplay"`
+	RateLimit struct {
+		Enabled           bool    `json:"enabled"`             // 是否对调用模型的接口按客户端限流
+		RequestsPerMinute float64 `json:"requests_per_minute"` // 每个客户端每分钟允许的请求数，默认10
+		Burst             int     `json:"burst"`               // 允许的突发请求数，默认5
+	} `json:"rate_limit"`
+	Log struct {
+		Format string `json:"format"` // 请求日志格式: text（默认）或 json
+	} `json:"log"`
+	Prompts struct {
+		Dir string `json:"dir"` // 提示模板目录，默认 ./prompts，环境变量 PROMPTS_DIR 优先
+	} `json:"prompts"`
+	Risk struct {
+		Dimensions []string `json:"dimensions"` // 风险识别维度，未配置时使用默认维度
+	} `json:"risk"`
+	Fetch struct {
+		TimeoutSeconds       int      `json:"timeout_seconds"`        // 从URL拉取会议记录的超时时间，默认15秒
+		MaxBytes             int64    `json:"max_bytes"`              // 拉取内容的最大字节数，默认2MB
+		AllowedHosts         []string `json:"allowed_hosts"`          // 非空时只允许从这些域名及其子域名拉取
+		DeniedHosts          []string `json:"denied_hosts"`           // 禁止拉取的域名及其子域名
+		AllowPrivateNetworks bool     `json:"allow_private_networks"` // 是否允许访问回环、内网地址，默认 false
+	} `json:"fetch"`
+	Callback struct {
+		Secret               string   `json:"secret"`                 // 签名回调请求的密钥，未配置时不接受 callback_url
+		TimeoutSeconds       int      `json:"timeout_seconds"`        // 单次回调请求的超时时间，默认10秒
+		MaxRetries           int      `json:"max_retries"`            // 回调失败后的最大重试次数，默认3次
+		AllowedHosts         []string `json:"allowed_hosts"`          // 非空时只允许回调这些域名及其子域名
+		DeniedHosts          []string `json:"denied_hosts"`           // 禁止回调的域名及其子域名
+		AllowPrivateNetworks bool     `json:"allow_private_networks"` // 是否允许回调回环、内网地址，默认 false
+	} `json:"callback"`
+	Admin struct {
+		Token string `json:"token"` // 管理接口的访问令牌，未配置时管理接口不可用
+	} `json:"admin"`
+	Static struct {
+		Disabled           bool   `json:"disabled"`             // 为 true 时不提供静态文件服务，只提供API
+		Root               string `json:"root"`                 // 静态文件目录，默认 ./static，环境变量 STATIC_DIR 优先
+		GenerateIndexPages bool   `json:"generate_index_pages"` // 目录下没有 index.html 时是否生成目录列表，默认 false
+	} `json:"static"`
+	Score struct {
+		GradeThresholds        map[string]float64 `json:"grade_thresholds"`         // 等级到最低得分百分比，默认 A:85、B:70、C:50、D:0
+		DisableIncompleteRetry bool               `json:"disable_incomplete_retry"` // 评估结果缺少指标得分时不要求模型补全重试，直接返回部分评分
+	} `json:"score"`
+	Models struct {
+		Extract  ModelOverride `json:"extract"`  // 会议信息抽取
+		Score    ModelOverride `json:"score"`    // 会议评分
+		Chat     ModelOverride `json:"chat"`     // 实时聊天
+		Roleplay ModelOverride `json:"roleplay"` // 角色扮演、多角色扮演和集体提问
+	} `json:"models"`
+	Embedding struct {
+		BaseURL        string `json:"base_url"`        // OpenAI 兼容的 embeddings 接口地址，默认使用 openai.base_url
+		APIKey         string `json:"api_key"`         // 默认使用 openai.api_key
+		ModelName      string `json:"model_name"`      // 向量模型名称，为空时不启用语义搜索
+		TimeoutSeconds int    `json:"timeout_seconds"` // 单次请求的超时时间，默认30秒
+	} `json:"embedding"`
+	Stream struct {
+		HeartbeatSeconds int  `json:"heartbeat_seconds"`  // 流式接口生成期间发送心跳事件的间隔，默认15秒，负数表示不发送
+		ResumeTTLSeconds int  `json:"resume_ttl_seconds"` // 聊天回答结束后保留已生成事件供断线续传的时长，默认60秒
+		EventNames       bool `json:"event_names"`        // 是否为事件设置 message、summary、done 等名称，默认关闭以兼容只使用 onmessage 的客户端
+	} `json:"stream"`
+}
+
+// ModelOverride 单个功能使用的模型配置，未配置的字段使用全局配置
+type ModelOverride struct {
+	ModelName   string   `json:"model_name"`  // 模型名称，为空时使用模型提供方配置中的 model_name
+	Temperature *float32 `json:"temperature"` // 温度，为空时使用该功能的默认温度
 }
 
 var (
-	config     *Config
-	configOnce sync.Once
-	configErr  error
+	configMu     sync.RWMutex
+	config       *Config
+	configErr    error
+	configLoaded bool
 )
 
-// LoadConfig 从配置文件加载配置
+// LoadConfig 从配置文件加载配置，首次调用后缓存结果，调用 ReloadConfig 后返回新的配置
 func LoadConfig() (*Config, error) {
-	configOnce.Do(func() {
-		// 优先从环境变量获取配置文件路径
-		configPath := os.Getenv("CONFIG_PATH")
-		if configPath == "" {
-			configPath = "config/config.json" // 默认配置文件路径
-		}
+	configMu.RLock()
+	if configLoaded {
+		defer configMu.RUnlock()
+		return config, configErr
+	}
+	configMu.RUnlock()
 
-		data, err := os.ReadFile(configPath)
-		if err != nil {
-			configErr = fmt.Errorf("读取配置文件失败: %v", err)
-			return
-		}
+	configMu.Lock()
+	defer configMu.Unlock()
+	if !configLoaded {
+		config, configErr = readConfigFile()
+		configLoaded = true
+	}
+	return config, configErr
+}
+
+// ReloadConfig 重新读取并校验配置文件，校验通过后替换缓存的配置并清空按旧配置创建的模型缓存；
+// 读取或校验失败时保留当前配置并返回错误。已启动的任务队列、存储目录等在启动时读取的配置仍需重启后生效
+func ReloadConfig() (*Config, error) {
+	cfg, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	configMu.Lock()
+	config, configErr, configLoaded = cfg, nil, true
+	configMu.Unlock()
+
+	// 模型缓存中的客户端持有旧的 API 密钥和模型名称
+	ResetChatModels()
+	fmt.Printf("配置已重新加载: %s\n", configFilePath())
+	return cfg, nil
+}
+
+// configFilePath 配置文件路径，优先使用环境变量 CONFIG_PATH
+func configFilePath() string {
+	if configPath := os.Getenv("CONFIG_PATH"); configPath != "" {
+		return configPath
+	}
+	return "config/config.json" // 默认配置文件路径
+}
 
-		var cfg Config
+// readConfigFile 读取配置文件并校验必要配置。启用 MOCK_LLM 且配置文件不存在时使用默认配置，
+// 没有配置文件的本地开发和 CI 环境同样可以启动
+func readConfigFile() (*Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(configFilePath())
+	switch {
+	case err == nil:
 		if err := json.Unmarshal(data, &cfg); err != nil {
-			configErr = fmt.Errorf("解析配置文件失败: %v", err)
-			return
+			return nil, fmt.Errorf("解析配置文件失败: %v", err)
 		}
+	case os.IsNotExist(err) && IsMockLLMEnabled():
+		fmt.Printf("配置文件 %s 不存在，模拟模式使用默认配置\n", configFilePath())
+		cfg.Provider = LLMProviderMock
+	default:
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
 
-		// 检查必要配置
-		if cfg.ARK.APIKey == "" {
-			configErr = fmt.Errorf("ARK API密钥未配置")
-			return
+	// 检查必要配置，API 密钥按当前模型提供方校验，模拟模式不需要 API 密钥
+	if err := validateLLMConfig(&cfg); err != nil {
+		return nil, fmt.Errorf("模型配置无效: %v", err)
+	}
+	if err := validateStorageConfig(&cfg); err != nil {
+		return nil, fmt.Errorf("存储配置无效: %v", err)
+	}
+	if len(cfg.Report.Sections) > 0 {
+		if err := ValidateReportSections(cfg.Report.Sections); err != nil {
+			return nil, fmt.Errorf("报告模板配置无效: %v", err)
 		}
+	}
 
-		config = &cfg
-	})
+	return &cfg, nil
+}
 
-	return config, configErr
+// GetAdminToken 获取管理接口的访问令牌，未配置时返回空字符串
+func GetAdminToken() string {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.Admin.Token
 }
 
 // GetARKAPIKey 获取ARK API密钥
@@ -74,3 +297,318 @@ func GetARKModelName() (string, error) {
 	}
 	return cfg.ARK.ModelName, nil
 }
+
+// GetLLMPricing 获取每千个输入、输出token的单价，未配置时返回0
+func GetLLMPricing() (float64, float64) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return 0, 0
+	}
+	switch GetLLMProvider() {
+	case LLMProviderOpenAI:
+		return cfg.OpenAI.PromptPricePer1K, cfg.OpenAI.CompletionPricePer1K
+	case LLMProviderMock:
+		return 0, 0
+	}
+	return cfg.ARK.PromptPricePer1K, cfg.ARK.CompletionPricePer1K
+}
+
+// GetLLMProvider 获取配置的模型提供方，未配置时使用 ark
+func GetLLMProvider() string {
+	cfg, err := LoadConfig()
+	if err != nil {
+		if IsMockLLMEnabled() {
+			return LLMProviderMock
+		}
+		return LLMProviderARK
+	}
+	return configProvider(cfg)
+}
+
+// IsMockLLMEnabled 是否通过环境变量 MOCK_LLM=true 开启模拟模型，开启后忽略配置中的 provider
+func IsMockLLMEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("MOCK_LLM"))
+	return enabled
+}
+
+// configProvider 返回配置生效的模型提供方：MOCK_LLM 开启时为 mock，provider 未配置时为 ark
+func configProvider(cfg *Config) string {
+	if IsMockLLMEnabled() {
+		return LLMProviderMock
+	}
+	if provider := strings.ToLower(strings.TrimSpace(cfg.Provider)); provider != "" {
+		return provider
+	}
+	return LLMProviderARK
+}
+
+// GetLLMModelName 获取当前模型提供方配置的模型名称
+func GetLLMModelName() (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	switch GetLLMProvider() {
+	case LLMProviderOpenAI:
+		return cfg.OpenAI.ModelName, nil
+	case LLMProviderMock:
+		return mockModelName, nil
+	}
+	return cfg.ARK.ModelName, nil
+}
+
+// GetQueueSettings 获取会议处理队列的worker数量和最大积压数，未配置时返回0由队列使用默认值
+func GetQueueSettings() (int, int) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Queue.Workers, cfg.Queue.MaxPending
+}
+
+// IsVIPToken 判断访问令牌是否在VIP令牌列表中，按常量时间比较
+func IsVIPToken(token string) bool {
+	cfg, err := LoadConfig()
+	if err != nil || token == "" {
+		return false
+	}
+	for _, vip := range cfg.Queue.VIPTokens {
+		if vip != "" && subtle.ConstantTimeCompare([]byte(token), []byte(vip)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// 请求日志格式
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// GetLogFormat 获取请求日志格式，未配置或配置无效时使用 text
+func GetLogFormat() string {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return LogFormatText
+	}
+	if strings.EqualFold(strings.TrimSpace(cfg.Log.Format), LogFormatJSON) {
+		return LogFormatJSON
+	}
+	return LogFormatText
+}
+
+// GetMultiRoleplayLimits 获取多角色扮演的最大轮数和最大专家人数，未配置时使用默认值
+func GetMultiRoleplayLimits() (int, int) {
+	maxRounds, maxSpecialists := 10, 12
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return maxRounds, maxSpecialists
+	}
+	if cfg.MultiRoleplay.MaxRounds > 0 {
+		maxRounds = cfg.MultiRoleplay.MaxRounds
+	}
+	if cfg.MultiRoleplay.MaxSpecialists > 0 {
+		maxSpecialists = cfg.MultiRoleplay.MaxSpecialists
+	}
+	return maxRounds, maxSpecialists
+}
+
+// GetMultiRoleplayHistoryWindow 获取多角色扮演上下文中保留原文的最近发言条数，未配置时使用默认值
+func GetMultiRoleplayHistoryWindow() int {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.MultiRoleplay.HistoryWindow <= 0 {
+		return defaultMultiRoleplayHistoryWindow
+	}
+	return cfg.MultiRoleplay.HistoryWindow
+}
+
+// TodoAssigneeFirstParticipant 默认负责人取会议的第一位参会人员，通常为会议主持人
+const TodoAssigneeFirstParticipant = "first_participant"
+
+// TodoSettings 新建待办的默认值，未配置的字段为零值，由调用方使用内置默认值
+type TodoSettings struct {
+	DefaultPriority         int
+	DefaultStatus           string
+	DefaultAssignee         string
+	AllowIncompleteSubtasks bool
+}
+
+// GetTodoSettings 获取新建待办的默认值配置
+func GetTodoSettings() TodoSettings {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return TodoSettings{}
+	}
+	return TodoSettings{
+		DefaultPriority:         cfg.Todo.DefaultPriority,
+		DefaultStatus:           strings.TrimSpace(cfg.Todo.DefaultStatus),
+		DefaultAssignee:         strings.TrimSpace(cfg.Todo.DefaultAssignee),
+		AllowIncompleteSubtasks: cfg.Todo.AllowIncompleteSubtasks,
+	}
+}
+
+// defaultMaxMeetingTodos 每个会议自动创建的待办数默认上限
+const defaultMaxMeetingTodos = 30
+
+// GetMaxMeetingTodos 获取每个会议自动创建的待办数上限，未配置时默认30
+func GetMaxMeetingTodos() int {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.Todo.MaxPerMeeting <= 0 {
+		return defaultMaxMeetingTodos
+	}
+	return cfg.Todo.MaxPerMeeting
+}
+
+// DefaultTodoAssignee 返回会议中抽取的待办无法确定负责人时使用的负责人，未配置时返回空字符串
+func DefaultTodoAssignee(participants []string) string {
+	assignee := GetTodoSettings().DefaultAssignee
+	if assignee != TodoAssigneeFirstParticipant {
+		return assignee
+	}
+	if len(participants) == 0 {
+		return ""
+	}
+	return participants[0]
+}
+
+// ReminderSettings 待办到期提醒的运行参数
+type ReminderSettings struct {
+	Enabled  bool
+	Interval time.Duration
+	Within   time.Duration
+	Channel  string
+}
+
+// GetReminderSettings 获取待办到期提醒配置，配置加载失败时视为未启用
+func GetReminderSettings() ReminderSettings {
+	settings := ReminderSettings{
+		Interval: 5 * time.Minute,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+
+	settings.Enabled = cfg.Reminder.Enabled
+	settings.Channel = cfg.Reminder.Channel
+	if cfg.Reminder.IntervalSeconds > 0 {
+		settings.Interval = time.Duration(cfg.Reminder.IntervalSeconds) * time.Second
+	}
+	if cfg.Reminder.WithinMinutes > 0 {
+		settings.Within = time.Duration(cfg.Reminder.WithinMinutes) * time.Minute
+	}
+
+	return settings
+}
+
+// RateLimitSettings 模型接口限流的运行参数
+type RateLimitSettings struct {
+	Enabled           bool
+	RequestsPerMinute float64
+	Burst             int
+}
+
+// GetRateLimitSettings 获取模型接口的限流配置，配置加载失败时视为未启用
+func GetRateLimitSettings() RateLimitSettings {
+	settings := RateLimitSettings{
+		RequestsPerMinute: 10,
+		Burst:             5,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+
+	settings.Enabled = cfg.RateLimit.Enabled
+	if cfg.RateLimit.RequestsPerMinute > 0 {
+		settings.RequestsPerMinute = cfg.RateLimit.RequestsPerMinute
+	}
+	if cfg.RateLimit.Burst > 0 {
+		settings.Burst = cfg.RateLimit.Burst
+	}
+
+	return settings
+}
+
+// StorageSettings 数据存储路径
+type StorageSettings struct {
+	MeetingsDir string
+	TodoDB      string
+	DataDir     string
+	Compress    bool
+	Backend     string // 会议文件的存储后端，取值为 StorageBackendFilesystem、StorageBackendS3 或 StorageBackendSQLite
+}
+
+// GetStorageSettings 获取数据存储路径，优先级为环境变量 > 配置文件 > 默认值
+func GetStorageSettings() StorageSettings {
+	settings := StorageSettings{
+		MeetingsDir: "./storage/meetings",
+		TodoDB:      "./storage/todo.db",
+		DataDir:     "./storage",
+		Backend:     StorageBackendFilesystem,
+	}
+
+	if cfg, err := LoadConfig(); err == nil {
+		if cfg.Storage.MeetingsDir != "" {
+			settings.MeetingsDir = cfg.Storage.MeetingsDir
+		}
+		if cfg.Storage.TodoDB != "" {
+			settings.TodoDB = cfg.Storage.TodoDB
+		}
+		if cfg.Storage.DataDir != "" {
+			settings.DataDir = cfg.Storage.DataDir
+		}
+		settings.Compress = cfg.Storage.Compress
+		if backend := strings.ToLower(strings.TrimSpace(cfg.Storage.Backend)); backend != "" {
+			settings.Backend = backend
+		}
+	}
+
+	if dir := os.Getenv("MEETINGS_DIR"); dir != "" {
+		settings.MeetingsDir = dir
+	}
+	if db := os.Getenv("TODO_DB"); db != "" {
+		settings.TodoDB = db
+	}
+	if dir := os.Getenv("DATA_DIR"); dir != "" {
+		settings.DataDir = dir
+	}
+	if backend := os.Getenv("STORAGE_BACKEND"); backend != "" {
+		settings.Backend = strings.ToLower(strings.TrimSpace(backend))
+	}
+
+	return settings
+}
+
+// StaticSettings 静态文件服务的运行参数
+type StaticSettings struct {
+	Enabled            bool
+	Root               string
+	GenerateIndexPages bool
+}
+
+// GetStaticSettings 获取静态文件服务配置，默认提供 ./static 目录且不生成目录列表
+func GetStaticSettings() StaticSettings {
+	settings := StaticSettings{
+		Enabled: true,
+		Root:    "./static",
+	}
+
+	if cfg, err := LoadConfig(); err == nil {
+		settings.Enabled = !cfg.Static.Disabled
+		settings.GenerateIndexPages = cfg.Static.GenerateIndexPages
+		if cfg.Static.Root != "" {
+			settings.Root = cfg.Static.Root
+		}
+	}
+
+	if dir := os.Getenv("STATIC_DIR"); dir != "" {
+		settings.Root = dir
+	}
+
+	return settings
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// 模型提供方 API 密钥的基本格式要求
+const (
+	minARKAPIKeyLength    = 16
+	minOpenAIAPIKeyLength = 20
+	openAIAPIKeyPrefix    = "sk-"
+)
+
+// validateLLMConfig 在加载配置时校验当前模型提供方的模型名称和 API 密钥，避免配置错误到第一次调用模型时才暴露。
+// 提供方配置了 known_models 时，model_name 和 models 中各功能的模型名称都必须在列表中
+func validateLLMConfig(cfg *Config) error {
+	provider := configProvider(cfg)
+	if provider == LLMProviderMock {
+		return nil
+	}
+
+	var modelName, apiKey string
+	var knownModels []string
+	switch provider {
+	case LLMProviderARK:
+		modelName, apiKey, knownModels = cfg.ARK.ModelName, cfg.ARK.APIKey, cfg.ARK.KnownModels
+	case LLMProviderOpenAI:
+		modelName, apiKey, knownModels = cfg.OpenAI.ModelName, cfg.OpenAI.APIKey, cfg.OpenAI.KnownModels
+	default:
+		return fmt.Errorf("不支持的模型提供方: %s，可选值: %s, %s, %s", cfg.Provider, LLMProviderARK, LLMProviderOpenAI, LLMProviderMock)
+	}
+
+	modelName = strings.TrimSpace(modelName)
+	if modelName == "" {
+		return fmt.Errorf("%s.model_name 未配置", provider)
+	}
+	if isPlaceholderValue(modelName) {
+		return fmt.Errorf("%s.model_name 仍是模板中的占位值 %q，请填写实际的模型名称", provider, modelName)
+	}
+
+	if len(knownModels) > 0 {
+		fields := []string{provider + ".model_name"}
+		names := []string{modelName}
+		for _, feature := range []struct {
+			name     string
+			override ModelOverride
+		}{
+			{ModelFeatureExtract, cfg.Models.Extract},
+			{ModelFeatureScore, cfg.Models.Score},
+			{ModelFeatureChat, cfg.Models.Chat},
+			{ModelFeatureRoleplay, cfg.Models.Roleplay},
+		} {
+			if name := strings.TrimSpace(feature.override.ModelName); name != "" {
+				fields = append(fields, "models."+feature.name+".model_name")
+				names = append(names, name)
+			}
+		}
+		for i, name := range names {
+			if !containsModel(knownModels, name) {
+				return fmt.Errorf("%s %q 不在 %s.known_models 中，可选值: %s", fields[i], name, provider, strings.Join(knownModels, ", "))
+			}
+		}
+	}
+
+	return validateAPIKey(provider, apiKey, cfg.OpenAI.BaseURL)
+}
+
+// validateAPIKey 检查 API 密钥是否像一个有效的密钥：不是模板占位值、不含空白字符、长度足够。
+// OpenAI 官方接口的密钥必须以 sk- 开头；使用自定义 base_url 的兼容服务允许不配置密钥
+func validateAPIKey(provider, apiKey, openAIBaseURL string) error {
+	field := provider + ".api_key"
+	if apiKey == "" {
+		if provider == LLMProviderOpenAI && !isOfficialOpenAIBaseURL(openAIBaseURL) {
+			return nil
+		}
+		return fmt.Errorf("%s 未配置", field)
+	}
+	if isPlaceholderValue(apiKey) {
+		return fmt.Errorf("%s 仍是模板中的占位值，请填写实际的 API 密钥", field)
+	}
+	if strings.IndexFunc(apiKey, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%s 包含空白字符，请检查是否复制了多余的空格或换行", field)
+	}
+
+	switch provider {
+	case LLMProviderARK:
+		if len(apiKey) < minARKAPIKeyLength {
+			return fmt.Errorf("%s 长度过短（%d 个字符，至少 %d 个字符）", field, len(apiKey), minARKAPIKeyLength)
+		}
+	case LLMProviderOpenAI:
+		if !isOfficialOpenAIBaseURL(openAIBaseURL) {
+			return nil
+		}
+		if !strings.HasPrefix(apiKey, openAIAPIKeyPrefix) {
+			return fmt.Errorf("%s 格式无效，OpenAI 的 API 密钥以 %s 开头", field, openAIAPIKeyPrefix)
+		}
+		if len(apiKey) < minOpenAIAPIKeyLength {
+			return fmt.Errorf("%s 长度过短（%d 个字符，至少 %d 个字符）", field, len(apiKey), minOpenAIAPIKeyLength)
+		}
+	}
+	return nil
+}
+
+// validateStorageConfig 校验会议存储后端，使用 s3 时必须配置接口地址、存储桶和访问密钥，
+// 访问密钥也可以通过 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY 环境变量提供
+func validateStorageConfig(cfg *Config) error {
+	backend := strings.ToLower(strings.TrimSpace(cfg.Storage.Backend))
+	if env := os.Getenv("STORAGE_BACKEND"); env != "" {
+		backend = strings.ToLower(strings.TrimSpace(env))
+	}
+	switch backend {
+	case "", StorageBackendFilesystem, StorageBackendSQLite:
+		return nil
+	case StorageBackendS3:
+	default:
+		return fmt.Errorf("不支持的存储后端: %s，可选值: %s, %s, %s", backend, StorageBackendFilesystem, StorageBackendS3, StorageBackendSQLite)
+	}
+
+	s3 := cfg.Storage.S3
+	endpoint, err := url.Parse(strings.TrimSpace(s3.Endpoint))
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("storage.s3.endpoint 必须是 http:// 或 https:// 开头的地址")
+	}
+	if strings.TrimSpace(s3.Bucket) == "" {
+		return fmt.Errorf("storage.s3.bucket 未配置")
+	}
+	if s3.AccessKeyID == "" && os.Getenv("AWS_ACCESS_KEY_ID") == "" {
+		return fmt.Errorf("storage.s3.access_key_id 未配置")
+	}
+	if s3.SecretAccessKey == "" && os.Getenv("AWS_SECRET_ACCESS_KEY") == "" {
+		return fmt.Errorf("storage.s3.secret_access_key 未配置")
+	}
+	return nil
+}
+
+// isOfficialOpenAIBaseURL 判断是否使用 OpenAI 官方接口，未配置 base_url 时视为官方接口
+func isOfficialOpenAIBaseURL(baseURL string) bool {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	return baseURL == "" || baseURL == defaultOpenAIBaseURL
+}
+
+// isPlaceholderValue 判断配置值是否为模板中的占位值，例如 your_ark_api_key_here
+func isPlaceholderValue(value string) bool {
+	value = strings.ToLower(value)
+	return strings.HasPrefix(value, "your_") || strings.HasSuffix(value, "_here")
+}
+
+// containsModel 判断模型名称是否在已知模型列表中
+func containsModel(knownModels []string, name string) bool {
+	for _, known := range knownModels {
+		if strings.TrimSpace(known) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 重复会议检测的默认参数
+const (
+	defaultDedupLookback   = 30 * 24 * time.Hour
+	defaultDedupSimilarity = 0.9
+	dedupShingleSize       = 5 // 计算相似度时使用的连续字符数
+)
+
+// 重复会议的匹配方式
+const (
+	DuplicateMatchExact   = "exact"
+	DuplicateMatchSimilar = "similar"
+)
+
+// DedupSettings 重复会议检测的运行参数
+type DedupSettings struct {
+	Enabled             bool
+	Lookback            time.Duration
+	SimilarityThreshold float64
+}
+
+// GetDedupSettings 获取重复会议检测配置，配置加载失败时视为未启用
+func GetDedupSettings() DedupSettings {
+	settings := DedupSettings{
+		Lookback:            defaultDedupLookback,
+		SimilarityThreshold: defaultDedupSimilarity,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+
+	settings.Enabled = cfg.Dedup.Enabled
+	if cfg.Dedup.LookbackDays > 0 {
+		settings.Lookback = time.Duration(cfg.Dedup.LookbackDays) * 24 * time.Hour
+	}
+	if cfg.Dedup.SimilarityThreshold > 0 && cfg.Dedup.SimilarityThreshold <= 1 {
+		settings.SimilarityThreshold = cfg.Dedup.SimilarityThreshold
+	}
+	return settings
+}
+
+// DuplicateMeeting 与新会议内容重复的已有会议
+type DuplicateMeeting struct {
+	MeetingID  string  `json:"existing_meeting_id"`
+	Title      string  `json:"title"`
+	Match      string  `json:"match"`      // exact 表示内容完全相同（忽略空白和大小写），similar 表示内容相似
+	Similarity float64 `json:"similarity"` // 内容相似度，完全相同时为1
+}
+
+// FindDuplicateMeeting 在回溯时间内创建的会议中查找与 documentText 重复的会议：
+// 忽略空白和大小写后内容相同视为完全重复，否则按连续字符片段的 Jaccard 相似度判断是否相似，
+// 返回相似度最高的一个，没有重复时返回 nil
+func FindDuplicateMeeting(documentText string, settings DedupSettings) (*DuplicateMeeting, error) {
+	normalized := normalizeForDedup(documentText)
+	if normalized == "" {
+		return nil, nil
+	}
+	hash := HashContent(normalized)
+
+	meetingIDs, err := ListMeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-settings.Lookback)
+	var shingles map[uint64]struct{}
+	var best *DuplicateMeeting
+	for _, meetingID := range meetingIDs {
+		if createdAt, ok := MeetingCreatedAt(meetingID); ok && createdAt.Before(cutoff) {
+			continue
+		}
+
+		meetingData, err := LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("读取会议 %s 失败: %v\n", meetingID, err)
+			continue
+		}
+		rawContent, _ := meetingData["raw_content"].(string)
+		existing := normalizeForDedup(rawContent)
+		if existing == "" {
+			continue
+		}
+		metadata, _ := GetMeetingMetadata(meetingData)
+
+		if HashContent(existing) == hash {
+			return &DuplicateMeeting{MeetingID: meetingID, Title: metadata.Title, Match: DuplicateMatchExact, Similarity: 1}, nil
+		}
+		if settings.SimilarityThreshold >= 1 || !similarLength(normalized, existing, settings.SimilarityThreshold) {
+			continue
+		}
+
+		if shingles == nil {
+			shingles = dedupShingles(normalized)
+		}
+		similarity := jaccardSimilarity(shingles, dedupShingles(existing))
+		if similarity >= settings.SimilarityThreshold && (best == nil || similarity > best.Similarity) {
+			best = &DuplicateMeeting{MeetingID: meetingID, Title: metadata.Title, Match: DuplicateMatchSimilar, Similarity: math.Round(similarity*10000) / 10000}
+		}
+	}
+	return best, nil
+}
+
+// normalizeForDedup 去除所有空白并转为小写，避免换行、缩进等格式差异影响比较
+func normalizeForDedup(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// similarLength 两段内容的 Jaccard 相似度不会超过长度之比，长度相差过大时无需计算
+func similarLength(a, b string, threshold float64) bool {
+	shorter, longer := len(a), len(b)
+	if shorter > longer {
+		shorter, longer = longer, shorter
+	}
+	return float64(shorter)/float64(longer) >= threshold
+}
+
+// dedupShingles 计算内容中所有连续 dedupShingleSize 个字符片段的哈希集合，内容过短时整体作为一个片段
+func dedupShingles(text string) map[uint64]struct{} {
+	runes := []rune(text)
+	shingles := make(map[uint64]struct{})
+	if len(runes) <= dedupShingleSize {
+		shingles[hashShingle(string(runes))] = struct{}{}
+		return shingles
+	}
+	for i := 0; i+dedupShingleSize <= len(runes); i++ {
+		shingles[hashShingle(string(runes[i:i+dedupShingleSize]))] = struct{}{}
+	}
+	return shingles
+}
+
+// hashShingle 计算字符片段的64位哈希
+func hashShingle(shingle string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(shingle))
+	return h.Sum64()
+}
+
+// jaccardSimilarity 计算两个片段集合的 Jaccard 相似度
+func jaccardSimilarity(a, b map[uint64]struct{}) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	intersection := 0
+	for shingle := range a {
+		if _, ok := b[shingle]; ok {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
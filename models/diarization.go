@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 判断会议记录是否已有说话人标签的阈值
+const (
+	speakerLabeledLineRatio = 0.5 // 带说话人标签的行占非空行的比例
+	minSpeakerLabels        = 2   // 至少出现的不同说话人数
+	minDiarizedLengthRatio  = 0.8 // 标注结果的字符数不能少于原文的该比例，避免模型改写或删减内容
+)
+
+// speakerLabelPattern 行首的说话人标签，例如 "张三: "、"[10:02] 李四：" 或 "(00:01:05) Alice:"，
+// 第一个分组为姓名，第二个分组为发言内容；姓名不能以数字开头，避免把时间戳当作说话人
+var speakerLabelPattern = regexp.MustCompile(`^\s*(?:[\[(（【]?\d{1,2}:\d{2}(?::\d{2})?[\])）】]?\s*)?([^\s\d:：\[\]()（）【】][^:：\n]{0,19}?)\s*[:：]\s*(\S.*)`)
+
+// IsDiarizationEnabled 是否在抽取前为没有说话人标签的会议记录标注说话人，默认关闭
+func IsDiarizationEnabled() bool {
+	cfg, err := LoadConfig()
+	return err == nil && cfg.Extraction.Diarization
+}
+
+// speakerLabels 返回会议记录中按出现顺序排列的说话人，以及带说话人标签的行数和非空行数
+func speakerLabels(text string) ([]string, int, int) {
+	var speakers []string
+	seen := make(map[string]bool)
+	labeled, total := 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		total++
+		match := speakerLabelPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		labeled++
+		if speaker := strings.TrimSpace(match[1]); !seen[speaker] {
+			seen[speaker] = true
+			speakers = append(speakers, speaker)
+		}
+	}
+	return speakers, labeled, total
+}
+
+// HasSpeakerLabels 判断会议记录是否已经标注了说话人：至少一半的非空行以说话人标签开头，且出现了两位以上的说话人
+func HasSpeakerLabels(text string) bool {
+	speakers, labeled, total := speakerLabels(text)
+	if total == 0 || len(speakers) < minSpeakerLabels {
+		return false
+	}
+	return float64(labeled)/float64(total) >= speakerLabeledLineRatio
+}
+
+// DiarizeMeetingContent 为没有说话人标签的会议记录标注说话人，结果保存在会议文件的 speaker_content 中，
+// 供参会人员抽取和角色扮演使用。未开启 extraction.diarization、会议记录已有说话人标签或标注失败时返回空字符串，
+// 调用方继续使用原始内容
+func DiarizeMeetingContent(ctx context.Context, text string) string {
+	if !IsDiarizationEnabled() || HasSpeakerLabels(text) {
+		return ""
+	}
+
+	tagged, err := DiarizeTranscript(ctx, text)
+	if err != nil {
+		Logf(ctx, "标注说话人失败，使用原始会议内容: %v\n", err)
+		return ""
+	}
+	return tagged
+}
+
+// DiarizeTranscript 调用模型为会议记录的每一行标注说话人。超过 extraction.chunk_chars 的内容分段标注，
+// 前面分段识别出的说话人会提供给后续分段，使同一个人在各段中使用相同的名字
+func DiarizeTranscript(ctx context.Context, text string) (string, error) {
+	chunks, err := SplitContent(text, GetExtractionSettings().ChunkChars)
+	if err != nil {
+		return "", err
+	}
+
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureExtract, 0) // 标注结果需要稳定
+	if err != nil {
+		return "", fmt.Errorf("创建聊天模型失败: %v", err)
+	}
+
+	var result strings.Builder
+	var speakers []string
+	for i, chunk := range chunks {
+		tagged, err := diarizeChunk(ctx, chatModel, chunk, speakers)
+		if err != nil {
+			return "", fmt.Errorf("标注第 %d/%d 段失败: %v", i+1, len(chunks), err)
+		}
+		if result.Len() > 0 && !strings.HasSuffix(result.String(), "\n") {
+			result.WriteString("\n")
+		}
+		result.WriteString(tagged)
+
+		known := make(map[string]bool, len(speakers))
+		for _, speaker := range speakers {
+			known[speaker] = true
+		}
+		chunkSpeakers, _, _ := speakerLabels(tagged)
+		for _, speaker := range chunkSpeakers {
+			if !known[speaker] {
+				speakers = append(speakers, speaker)
+			}
+		}
+	}
+
+	tagged := result.String()
+	if !HasSpeakerLabels(tagged) {
+		return "", fmt.Errorf("模型未能识别出多位说话人")
+	}
+	return tagged, nil
+}
+
+// diarizeChunk 标注一段会议记录的说话人，knownSpeakers 为前面分段已识别出的说话人
+func diarizeChunk(ctx context.Context, chatModel LLM, chunk string, knownSpeakers []string) (string, error) {
+	systemPrompt := `你负责为没有说话人标签的会议记录标注说话人。请根据称呼、自我介绍、点名、问答关系和发言内容判断每句话是谁说的，并在每一行前加上"姓名: "。
+
+要求：
+1. 保持原文的内容和顺序，不要改写、删减、合并或补充任何内容，只在行首添加说话人
+2. 一行中包含多位说话人的发言时，按说话人拆分为多行
+3. 能从上下文确定姓名时使用真实姓名；无法确定时使用"发言人1"、"发言人2"等编号，同一个人始终使用同一个编号
+4. 只输出标注后的会议记录，不要输出说明或其他内容`
+	if len(knownSpeakers) > 0 {
+		systemPrompt += "\n\n前面的会议记录中已识别出以下说话人，同一个人请使用相同的名字：" + strings.Join(knownSpeakers, "、")
+	}
+
+	messages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(chunk),
+	}
+
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	response, err := chatModel.Generate(callCtx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return "", err
+	}
+
+	tagged := strings.TrimSpace(response.Content)
+	if strings.HasSuffix(tagged, truncatedMarker) {
+		return "", fmt.Errorf("模型输出超出长度上限")
+	}
+	// 标注只会增加内容，明显变短说明模型改写或删减了原文
+	if float64(utf8.RuneCountInString(tagged)) < float64(utf8.RuneCountInString(strings.TrimSpace(chunk)))*minDiarizedLengthRatio {
+		return "", fmt.Errorf("标注结果与原文差异过大")
+	}
+	return tagged, nil
+}
+
+// MeetingSpeakerContent 返回角色扮演使用的会议内容：有说话人标注版本时使用标注版本，否则使用原始内容
+func MeetingSpeakerContent(meetingData map[string]interface{}) string {
+	if speakerContent, ok := meetingData["speaker_content"].(string); ok && speakerContent != "" {
+		return speakerContent
+	}
+	return MeetingDataContent(meetingData)
+}
+
+// getMeetingSpeakerContent 与 getMeetingContent 相同，但有说话人标注版本时返回标注版本，用于多角色扮演等需要区分发言人的场景
+func getMeetingSpeakerContent(meetingID string) (string, string, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return "", "", err
+	}
+	metadata, _ := GetMeetingMetadata(meetingData)
+	return MeetingSpeakerContent(meetingData), metadata.Describe(), nil
+}
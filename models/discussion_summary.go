@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 多角色扮演讨论总结的格式
+const (
+	SummaryStyleNarrative   = "narrative"    // 按话题、观点、共识、待讨论问题和行动项组织的段落总结（默认）
+	SummaryStyleActionItems = "action_items" // 只列出行动项
+	SummaryStyleDecisionLog = "decision_log" // 按条目记录讨论中做出的决定
+)
+
+// 讨论总结的默认长度
+const (
+	defaultDiscussionSummaryMinChars = 300
+	defaultDiscussionSummaryMaxChars = 500
+)
+
+// discussionSummaryStyles 各总结格式对应的提示，渲染到 discussion_summary 提示模板的 {{.SummaryStyle}}
+var discussionSummaryStyles = map[string]string{
+	SummaryStyleNarrative: `总结应包括：
+1. 讨论的主要话题和议题
+2. 各方观点的概述
+3. 达成的共识或结论
+4. 需要进一步讨论的问题
+5. 确定的下一步行动项目`,
+
+	SummaryStyleActionItems: `只列出讨论中提出的行动项，不要写背景和观点概述。每项一行，格式为：
+- 行动内容（负责人：姓名，无法确定时写"待定"；期限：讨论中提到的时间，没有时省略）
+讨论中没有形成任何行动项时，只输出"无行动项"。`,
+
+	SummaryStyleDecisionLog: `以决策日志的形式记录讨论中做出的决定，不要写讨论过程。每项一行，格式为：
+- 决定：决定内容；理由：支持该决定的主要理由；提出人：姓名
+最后单独列出"未决事项"，每项一行，说明仍有分歧的问题和各方立场。讨论中没有做出任何决定时，说明"未形成决定"并只列出未决事项。`,
+}
+
+// SummaryStyles 返回可选的讨论总结格式，按名称排序
+func SummaryStyles() []string {
+	styles := make([]string, 0, len(discussionSummaryStyles))
+	for style := range discussionSummaryStyles {
+		styles = append(styles, style)
+	}
+	sort.Strings(styles)
+	return styles
+}
+
+// normalizeSummaryStyle 校验讨论总结格式，空值使用默认的 narrative
+func normalizeSummaryStyle(style string) (string, error) {
+	style = strings.ToLower(strings.TrimSpace(style))
+	if style == "" {
+		return SummaryStyleNarrative, nil
+	}
+	if _, ok := discussionSummaryStyles[style]; !ok {
+		return "", fmt.Errorf("不支持的 summary_style: %s，可选值: %s", style, strings.Join(SummaryStyles(), ", "))
+	}
+	return style, nil
+}
+
+// GetDiscussionSummaryLength 获取讨论总结的目标字数范围，最少字数为0表示只限制最多字数
+func GetDiscussionSummaryLength() (int, int) {
+	minChars, maxChars := defaultDiscussionSummaryMinChars, defaultDiscussionSummaryMaxChars
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return minChars, maxChars
+	}
+	if cfg.MultiRoleplay.SummaryMaxChars > 0 {
+		maxChars = cfg.MultiRoleplay.SummaryMaxChars
+		// 只调整最多字数时，默认的最少字数可能超过它
+		if minChars >= maxChars {
+			minChars = 0
+		}
+	}
+	if cfg.MultiRoleplay.SummaryMinChars > 0 && cfg.MultiRoleplay.SummaryMinChars < maxChars {
+		minChars = cfg.MultiRoleplay.SummaryMinChars
+	}
+	return minChars, maxChars
+}
+
+// renderDiscussionSummaryPrompt 渲染讨论总结的系统提示。只有段落总结使用最少字数，
+// 行动项和决策日志的条目数取决于讨论内容，只限制最多字数
+func renderDiscussionSummaryPrompt(style string) (string, error) {
+	style, err := normalizeSummaryStyle(style)
+	if err != nil {
+		return "", err
+	}
+
+	minChars, maxChars := GetDiscussionSummaryLength()
+	limit := fmt.Sprintf("总结应该清晰、简洁、客观，总长度不超过%d字。", maxChars)
+	if style == SummaryStyleNarrative && minChars > 0 {
+		limit = fmt.Sprintf("总结应该清晰、简洁、客观，长度控制在%d-%d字之间。", minChars, maxChars)
+	}
+
+	return RenderPrompt(PromptDiscussionSummary, PromptData{
+		SummaryStyle: discussionSummaryStyles[style],
+		SummaryLimit: limit,
+	})
+}
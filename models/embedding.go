@@ -0,0 +1,206 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 向量接口的默认限制
+const (
+	defaultEmbeddingTimeout = 30 * time.Second
+	embeddingBatchSize      = 64 // 单次请求最多计算的文本数
+)
+
+// embeddingArtifactKind 会议向量在缓存中的类型
+const embeddingArtifactKind = "embedding"
+
+// ErrEmbeddingUnavailable 未配置向量模型
+var ErrEmbeddingUnavailable = errors.New("未配置向量模型")
+
+// Embedder 将文本转换为向量，返回的向量与输入文本一一对应
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// EmbeddingSettings 向量接口的运行参数
+type EmbeddingSettings struct {
+	BaseURL   string
+	APIKey    string
+	ModelName string
+	Timeout   time.Duration
+}
+
+// GetEmbeddingSettings 获取向量接口配置，接口地址和密钥未配置时使用 openai 中的配置
+func GetEmbeddingSettings() EmbeddingSettings {
+	settings := EmbeddingSettings{
+		BaseURL: defaultOpenAIBaseURL,
+		Timeout: defaultEmbeddingTimeout,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+
+	settings.ModelName = strings.TrimSpace(cfg.Embedding.ModelName)
+	if baseURL := strings.TrimRight(cfg.Embedding.BaseURL, "/"); baseURL != "" {
+		settings.BaseURL = baseURL
+	} else if baseURL := strings.TrimRight(cfg.OpenAI.BaseURL, "/"); baseURL != "" {
+		settings.BaseURL = baseURL
+	}
+	settings.APIKey = cfg.Embedding.APIKey
+	if settings.APIKey == "" {
+		settings.APIKey = cfg.OpenAI.APIKey
+	}
+	if cfg.Embedding.TimeoutSeconds > 0 {
+		settings.Timeout = time.Duration(cfg.Embedding.TimeoutSeconds) * time.Second
+	}
+	return settings
+}
+
+// GetEmbedder 根据配置创建向量接口客户端，未配置向量模型时返回 ErrEmbeddingUnavailable
+func GetEmbedder() (Embedder, error) {
+	settings := GetEmbeddingSettings()
+	if settings.ModelName == "" {
+		return nil, ErrEmbeddingUnavailable
+	}
+	return &openAIEmbedder{
+		client:   &http.Client{Timeout: settings.Timeout},
+		settings: settings,
+	}, nil
+}
+
+// openAIEmbedder 调用 OpenAI 兼容的 /embeddings 接口
+type openAIEmbedder struct {
+	client   *http.Client
+	settings EmbeddingSettings
+}
+
+// openAIEmbeddingRequest /embeddings 请求体
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// openAIEmbeddingResponse /embeddings 响应体，Index 对应输入文本的下标
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed 分批计算文本向量
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := start + embeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batch, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// embedBatch 计算一批文本的向量，按响应中的 index 还原输入顺序
+func (e *openAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	data, err := json.Marshal(openAIEmbeddingRequest{Model: e.settings.ModelName, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("序列化向量请求失败: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.settings.BaseURL+"/embeddings", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("创建向量请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.settings.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.settings.APIKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求向量接口失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("向量接口返回错误状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析向量响应失败: %v", err)
+	}
+
+	vectors := make([][]float64, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) || len(item.Embedding) == 0 {
+			return nil, fmt.Errorf("向量响应中的下标无效: %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, fmt.Errorf("向量响应缺少第 %d 条文本的结果", i)
+		}
+	}
+	return vectors, nil
+}
+
+// meetingEmbeddingText 用于计算会议向量的文本：标题和摘要
+func meetingEmbeddingText(metadata MeetingMetadata) string {
+	return strings.TrimSpace(metadata.Title + "\n" + metadata.Summary)
+}
+
+// meetingEmbeddingHash 会议向量的缓存键，标题、摘要或向量模型变化后缓存自动失效
+func meetingEmbeddingHash(metadata MeetingMetadata, modelName string) string {
+	return HashContent(meetingEmbeddingText(metadata), modelName)
+}
+
+// IndexMeetingEmbedding 计算会议标题和摘要的向量并写入缓存，未配置向量模型时直接返回 nil
+func IndexMeetingEmbedding(ctx context.Context, meetingID string, metadata MeetingMetadata) error {
+	embedder, err := GetEmbedder()
+	if errors.Is(err, ErrEmbeddingUnavailable) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	vectors, err := embedder.Embed(ctx, []string{meetingEmbeddingText(metadata)})
+	if err != nil {
+		return err
+	}
+	return SaveCachedArtifact(meetingID, embeddingArtifactKind, meetingEmbeddingHash(metadata, GetEmbeddingSettings().ModelName), vectors[0])
+}
+
+// cosineSimilarity 计算两个向量的余弦相似度，维度不一致或存在零向量时返回0
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
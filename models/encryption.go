@@ -0,0 +1,311 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// encryptedValuePrefix 加密字段值的前缀，完整格式为 enc:v1:<密钥ID>:<base64(nonce+密文)>
+const encryptedValuePrefix = "enc:v1:"
+
+// defaultEncryptedFields 默认加密的会议字段
+var defaultEncryptedFields = []string{"raw_content", "speaker_content"}
+
+// EncryptionKeys 字段加密使用的密钥，CurrentID 用于加密新数据，Keys 中的其他密钥仅用于解密轮换前的数据
+type EncryptionKeys struct {
+	CurrentID string
+	Keys      map[string][]byte
+}
+
+// EncryptionKeyProvider 获取字段加密密钥的函数
+type EncryptionKeyProvider func() (*EncryptionKeys, error)
+
+// encryptionKeyProvider 当前使用的密钥来源，默认读取配置文件和环境变量
+var encryptionKeyProvider EncryptionKeyProvider = configEncryptionKeys
+
+// SetEncryptionKeyProvider 替换密钥来源，例如从KMS获取密钥
+func SetEncryptionKeyProvider(provider EncryptionKeyProvider) {
+	encryptionKeyProvider = provider
+}
+
+// GetEncryptedFields 获取需要加密的字段路径，未启用加密时返回 nil。
+// 路径用 . 分隔，例如 raw_content、metadata.summary
+func GetEncryptedFields() []string {
+	cfg, err := LoadConfig()
+	if err != nil || !cfg.Encryption.Enabled {
+		return nil
+	}
+	if len(cfg.Encryption.Fields) == 0 {
+		return defaultEncryptedFields
+	}
+	return cfg.Encryption.Fields
+}
+
+// configEncryptionKeys 从配置文件读取密钥，配置文件未设置时读取
+// MEETING_ENCRYPTION_KEYS（格式 id1:base64,id2:base64）和 MEETING_ENCRYPTION_KEY_ID 环境变量
+func configEncryptionKeys() (*EncryptionKeys, error) {
+	currentID := os.Getenv("MEETING_ENCRYPTION_KEY_ID")
+	encodedKeys := make(map[string]string)
+
+	if cfg, err := LoadConfig(); err == nil && len(cfg.Encryption.Keys) > 0 {
+		currentID = cfg.Encryption.KeyID
+		encodedKeys = cfg.Encryption.Keys
+	} else if envKeys := os.Getenv("MEETING_ENCRYPTION_KEYS"); envKeys != "" {
+		for _, item := range strings.Split(envKeys, ",") {
+			id, key, ok := strings.Cut(strings.TrimSpace(item), ":")
+			if !ok {
+				return nil, fmt.Errorf("MEETING_ENCRYPTION_KEYS 格式错误")
+			}
+			encodedKeys[id] = key
+		}
+	}
+
+	if len(encodedKeys) == 0 {
+		return nil, fmt.Errorf("加密密钥未配置")
+	}
+
+	keys := &EncryptionKeys{
+		CurrentID: currentID,
+		Keys:      make(map[string][]byte, len(encodedKeys)),
+	}
+	for id, encoded := range encodedKeys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("密钥ID %s 不能包含冒号", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("解码密钥 %s 失败: %v", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("密钥 %s 长度必须为32字节（AES-256）", id)
+		}
+		keys.Keys[id] = key
+	}
+
+	// 只配置了一个密钥时可省略密钥ID
+	if keys.CurrentID == "" && len(keys.Keys) == 1 {
+		for id := range keys.Keys {
+			keys.CurrentID = id
+		}
+	}
+	if _, ok := keys.Keys[keys.CurrentID]; !ok {
+		return nil, fmt.Errorf("当前密钥 %s 不存在", keys.CurrentID)
+	}
+
+	return keys, nil
+}
+
+// encryptMeetingFields 返回敏感字段已加密的会议数据副本，不修改传入的数据。
+// 字段值先序列化为JSON再加密，解密后可还原原始类型；未列出的字段保持明文以便按 metadata 查询
+func encryptMeetingFields(meetingID string, meetingData map[string]interface{}, fields []string) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return meetingData, nil
+	}
+
+	keys, err := encryptionKeyProvider()
+	if err != nil {
+		return nil, fmt.Errorf("获取加密密钥失败: %v", err)
+	}
+
+	result := copyMap(meetingData)
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+
+		// 复制路径上的每一层，避免修改调用方持有的数据
+		parent := result
+		for _, key := range path[:len(path)-1] {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				parent = nil
+				break
+			}
+			child = copyMap(child)
+			parent[key] = child
+			parent = child
+		}
+		if parent == nil {
+			continue
+		}
+
+		last := path[len(path)-1]
+		value, ok := parent[last]
+		if !ok || value == nil {
+			continue
+		}
+		if str, ok := value.(string); ok && strings.HasPrefix(str, encryptedValuePrefix) {
+			continue
+		}
+
+		encrypted, err := encryptValue(keys, meetingID+"/"+field, value)
+		if err != nil {
+			return nil, fmt.Errorf("加密字段 %s 失败: %v", field, err)
+		}
+		parent[last] = encrypted
+	}
+
+	return result, nil
+}
+
+// decryptMeetingFields 就地解密会议数据中所有加密的字段，与当前加密字段配置无关，
+// 因此缩小加密范围或关闭加密后仍可读取历史数据
+func decryptMeetingFields(meetingID string, meetingData map[string]interface{}) error {
+	var keys *EncryptionKeys
+	return walkEncryptedValues(meetingData, "", func(path, value string) (interface{}, error) {
+		if keys == nil {
+			var err error
+			if keys, err = encryptionKeyProvider(); err != nil {
+				return nil, fmt.Errorf("获取加密密钥失败: %v", err)
+			}
+		}
+		decrypted, err := decryptValue(keys, meetingID+"/"+path, value)
+		if err != nil {
+			return nil, fmt.Errorf("解密字段 %s 失败: %v", path, err)
+		}
+		return decrypted, nil
+	})
+}
+
+// walkEncryptedValues 遍历嵌套map中的加密字符串并用 replace 的结果替换
+func walkEncryptedValues(data map[string]interface{}, prefix string, replace func(path, value string) (interface{}, error)) error {
+	for key, value := range data {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case string:
+			if !strings.HasPrefix(v, encryptedValuePrefix) {
+				continue
+			}
+			decrypted, err := replace(path, v)
+			if err != nil {
+				return err
+			}
+			data[key] = decrypted
+		case map[string]interface{}:
+			if err := walkEncryptedValues(v, path, replace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// encryptValue 使用当前密钥以AES-GCM加密字段值，字段位置作为附加数据防止密文被挪用到其他字段
+func encryptValue(keys *EncryptionKeys, aad string, value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(keys.Keys[keys.CurrentID])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, []byte(aad))
+	return encryptedValuePrefix + keys.CurrentID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue 按密文中记录的密钥ID解密字段值
+func decryptValue(keys *EncryptionKeys, aad string, value string) (interface{}, error) {
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, encryptedValuePrefix), ":")
+	if !ok {
+		return nil, fmt.Errorf("密文格式错误")
+	}
+
+	key, ok := keys.Keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("密钥 %s 不存在", keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("密文格式错误: %v", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("密文长度错误")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(aad))
+	if err != nil {
+		return nil, fmt.Errorf("密文校验失败: %v", err)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(plaintext, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// newGCM 创建AES-GCM实例
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// copyMap 浅拷贝map
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// MigrateMeetingEncryption 按当前配置重写所有会议文件：未加密的敏感字段会被加密，
+// 使用旧密钥加密的字段会用当前密钥重新加密。返回重写的会议数
+func MigrateMeetingEncryption() (int, error) {
+	fields := GetEncryptedFields()
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("未启用字段加密")
+	}
+
+	meetingIDs, err := ListMeetingIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, meetingID := range meetingIDs {
+		if err := rewriteMeeting(meetingID); err != nil {
+			return migrated, fmt.Errorf("迁移会议 %s 失败: %v", meetingID, err)
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// rewriteMeeting 在会议锁内解密并按当前配置重新写入会议文件
+func rewriteMeeting(meetingID string) error {
+	unlock := lockMeeting(meetingID)
+	defer unlock()
+
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return err
+	}
+	return SaveMeeting(meetingID, meetingData)
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hertz-contrib/sse"
+)
+
+// defaultStreamHeartbeat 流式接口默认的心跳间隔
+const defaultStreamHeartbeat = 15 * time.Second
+
+// GetStreamHeartbeatInterval 获取流式接口的心跳间隔，未配置时使用默认值，配置为负数时返回0表示不发送心跳
+func GetStreamHeartbeatInterval() time.Duration {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.Stream.HeartbeatSeconds == 0 {
+		return defaultStreamHeartbeat
+	}
+	if cfg.Stream.HeartbeatSeconds < 0 {
+		return 0
+	}
+	return time.Duration(cfg.Stream.HeartbeatSeconds) * time.Second
+}
+
+// heartbeatPublisher 与心跳共用同一把锁写入事件，避免心跳和正常事件交错写入连接
+type heartbeatPublisher struct {
+	mu      sync.Mutex
+	stream  EventPublisher
+	stopped bool // 停止后不再发送心跳，保证结束事件之后没有多余的心跳
+}
+
+// Publish 发送正常事件
+func (p *heartbeatPublisher) Publish(event *sse.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stream.Publish(event)
+}
+
+// beat 发送一次心跳，已停止时直接返回
+func (p *heartbeatPublisher) beat() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return nil
+	}
+	return p.stream.Publish(&sse.Event{Event: "heartbeat", Data: []byte(`{}`)})
+}
+
+// StartHeartbeat 在生成期间每隔 interval 向 stream 发送一次 heartbeat 事件，避免代理或负载均衡因连接空闲将其断开。
+// 返回的 EventPublisher 用于发送正常事件；调用返回的 stop 后心跳停止，stop 返回时心跳协程已退出。
+// ctx 取消或发送失败（客户端已断开）时心跳也会停止，interval 不大于0时不发送心跳
+func StartHeartbeat(ctx context.Context, stream EventPublisher, interval time.Duration) (EventPublisher, func()) {
+	publisher := &heartbeatPublisher{stream: stream}
+	if interval <= 0 {
+		return publisher, func() {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := publisher.beat(); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			publisher.mu.Lock()
+			publisher.stopped = true
+			publisher.mu.Unlock()
+			close(done)
+			<-exited
+		})
+	}
+	return publisher, stop
+}
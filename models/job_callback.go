@@ -0,0 +1,160 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 任务完成回调的默认限制
+const (
+	defaultCallbackTimeout    = 10 * time.Second
+	defaultCallbackMaxRetries = 3
+	callbackRetryBaseDelay    = time.Second
+)
+
+// 回调请求的签名请求头，签名为 HMAC-SHA256(secret, 时间戳 + "." + 请求体) 的十六进制
+const (
+	CallbackSignatureHeader = "X-Callback-Signature"
+	CallbackTimestampHeader = "X-Callback-Timestamp"
+)
+
+// ErrCallbackNotConfigured 未配置回调签名密钥
+var ErrCallbackNotConfigured = errors.New("未配置 callback.secret，不支持 callback_url")
+
+// CallbackSettings 任务完成回调的配置
+type CallbackSettings struct {
+	Secret     string
+	Timeout    time.Duration
+	MaxRetries int
+	Fetch      FetchSettings // 回调地址的域名和内网限制，与拉取远程会议记录使用相同的校验
+}
+
+// JobCallbackPayload 任务完成回调的请求体
+type JobCallbackPayload struct {
+	JobID      string    `json:"job_id"`
+	MeetingID  string    `json:"meeting_id"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// GetCallbackSettings 获取任务完成回调的配置，未配置时使用默认值
+func GetCallbackSettings() CallbackSettings {
+	settings := CallbackSettings{
+		Timeout:    defaultCallbackTimeout,
+		MaxRetries: defaultCallbackMaxRetries,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+	settings.Secret = cfg.Callback.Secret
+	if cfg.Callback.TimeoutSeconds > 0 {
+		settings.Timeout = time.Duration(cfg.Callback.TimeoutSeconds) * time.Second
+	}
+	if cfg.Callback.MaxRetries > 0 {
+		settings.MaxRetries = cfg.Callback.MaxRetries
+	}
+	settings.Fetch = FetchSettings{
+		Timeout:              settings.Timeout,
+		AllowedHosts:         cfg.Callback.AllowedHosts,
+		DeniedHosts:          cfg.Callback.DeniedHosts,
+		AllowPrivateNetworks: cfg.Callback.AllowPrivateNetworks,
+	}
+	return settings
+}
+
+// ValidateCallbackURL 校验创建会议请求中的回调地址：必须已配置签名密钥，
+// 地址的协议、域名和内网限制与从URL拉取会议记录相同
+func ValidateCallbackURL(rawURL string) error {
+	settings := GetCallbackSettings()
+	if settings.Secret == "" {
+		return ErrCallbackNotConfigured
+	}
+	_, err := validateFetchURL(rawURL, settings.Fetch)
+	return err
+}
+
+// SignCallback 计算回调请求的签名
+func SignCallback(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverJobCallback 向回调地址 POST 任务结果。网络错误、5xx、408 和 429 按 1s、2s、4s... 退避重试，
+// 最多重试 MaxRetries 次；其他 4xx 或地址不允许访问时不再重试
+func deliverJobCallback(callbackURL string, payload JobCallbackPayload) {
+	settings := GetCallbackSettings()
+	if settings.Secret == "" {
+		fmt.Printf("任务 %s 的回调未发送: %v\n", payload.JobID, ErrCallbackNotConfigured)
+		return
+	}
+	target, err := validateFetchURL(callbackURL, settings.Fetch)
+	if err != nil {
+		fmt.Printf("任务 %s 的回调未发送: %v\n", payload.JobID, err)
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Printf("序列化任务 %s 的回调失败: %v\n", payload.JobID, err)
+		return
+	}
+
+	client := newRestrictedHTTPClient(settings.Fetch)
+	for attempt := 0; ; attempt++ {
+		retryable, err := postJobCallback(client, target.String(), settings.Secret, body)
+		if err == nil {
+			fmt.Printf("任务 %s 的回调已发送到 %s\n", payload.JobID, target.Host)
+			return
+		}
+		if !retryable || attempt >= settings.MaxRetries {
+			fmt.Printf("任务 %s 的回调发送失败（共尝试 %d 次）: %v\n", payload.JobID, attempt+1, err)
+			return
+		}
+		delay := callbackRetryBaseDelay << attempt
+		fmt.Printf("任务 %s 的回调发送失败，%v 后重试: %v\n", payload.JobID, delay, err)
+		time.Sleep(delay)
+	}
+}
+
+// postJobCallback 发送一次签名的回调请求，返回失败时是否值得重试
+func postJobCallback(client *http.Client, callbackURL, secret string, body []byte) (bool, error) {
+	// 每次请求重新签名，时间戳反映实际发送时间
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CallbackTimestampHeader, timestamp)
+	req.Header.Set(CallbackSignatureHeader, SignCallback(secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return !errors.Is(err, ErrFetchURLNotAllowed), err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("返回状态码 %d", resp.StatusCode)
+}
@@ -0,0 +1,356 @@
+package models
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 会议处理任务的优先级，数值越大越先被处理
+const (
+	JobPriorityNormal = 0 // 普通
+	JobPriorityUrgent = 1 // 紧急标记
+	JobPriorityVIP    = 2 // VIP用户
+)
+
+// 会议处理任务状态
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+const (
+	defaultQueueWorkers    = 2
+	defaultQueueMaxPending = 100
+	finishedJobRetention   = time.Hour // 已结束任务的状态保留时长
+)
+
+// ErrQueueFull 任务队列积压已达上限
+var ErrQueueFull = errors.New("会议处理队列已满，请稍后重试")
+
+// ErrJobNotFound 任务不存在
+var ErrJobNotFound = errors.New("任务不存在")
+
+// ErrQueueClosed 服务正在关闭，队列不再接收新任务
+var ErrQueueClosed = errors.New("服务正在关闭，请稍后重试")
+
+// MeetingJob 一个会议抽取处理任务
+type MeetingJob struct {
+	ID           string     `json:"job_id"`
+	MeetingID    string     `json:"meeting_id"`
+	Priority     int        `json:"priority"`
+	Status       string     `json:"status"`
+	Error        string     `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`  // 任务开始执行的时间，未开始时为空
+	FinishedAt   *time.Time `json:"finished_at,omitempty"` // 任务结束的时间，未结束时为空
+	DocumentText string     `json:"-"`                     // 待抽取的会议文本
+	Tags         []string   `json:"-"`                     // 创建会议时指定的标签
+	Reextract    bool       `json:"reextract,omitempty"`   // 为 true 时重新抽取已有会议的信息，而不是创建新会议
+	CallbackURL  string     `json:"-"`                     // 任务结束后接收结果的回调地址
+	MeetingType  string     `json:"-"`                     // 用户指定的会议类型，为空时由模型分类
+
+	seq  int64
+	done chan struct{}
+}
+
+// MeetingJobHandler 执行会议处理任务的函数
+type MeetingJobHandler func(ctx context.Context, job *MeetingJob) error
+
+// JobQueueStats 任务队列的监控指标
+type JobQueueStats struct {
+	Workers    int   `json:"workers"`
+	MaxPending int   `json:"max_pending"`
+	Pending    int   `json:"pending"`
+	Running    int   `json:"running"`
+	Succeeded  int64 `json:"succeeded"`
+	Failed     int64 `json:"failed"`
+	Rejected   int64 `json:"rejected"` // 因积压超限被拒绝的任务数
+}
+
+// MeetingJobQueue 按优先级调度的会议处理队列，由固定数量的worker消费以平滑模型调用速率
+type MeetingJobQueue struct {
+	mu         sync.Mutex
+	pending    jobHeap
+	jobs       map[string]*MeetingJob
+	notify     chan struct{}
+	workers    int
+	maxPending int
+	handler    MeetingJobHandler
+	seq        int64
+	running    int
+	succeeded  int64
+	failed     int64
+	rejected   int64
+	closed     bool
+	inFlight   sync.WaitGroup // 正在执行的任务
+	startOnce  sync.Once
+}
+
+// NewMeetingJobQueue 创建会议处理队列，workers 和 maxPending 不大于0时使用默认值
+func NewMeetingJobQueue(workers, maxPending int, handler MeetingJobHandler) *MeetingJobQueue {
+	if workers <= 0 {
+		workers = defaultQueueWorkers
+	}
+	if maxPending <= 0 {
+		maxPending = defaultQueueMaxPending
+	}
+
+	return &MeetingJobQueue{
+		jobs:       make(map[string]*MeetingJob),
+		notify:     make(chan struct{}, 1),
+		workers:    workers,
+		maxPending: maxPending,
+		handler:    handler,
+	}
+}
+
+// Start 启动worker，ctx 取消后worker在完成当前任务后退出
+func (q *MeetingJobQueue) Start(ctx context.Context) {
+	q.startOnce.Do(func() {
+		for i := 0; i < q.workers; i++ {
+			go q.work(ctx)
+		}
+	})
+}
+
+// Enqueue 提交任务，队列积压达到上限时返回 ErrQueueFull
+func (q *MeetingJobQueue) Enqueue(job *MeetingJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	if q.pending.Len() >= q.maxPending {
+		q.rejected++
+		return ErrQueueFull
+	}
+
+	q.seq++
+	job.seq = q.seq
+	job.Status = JobStatusQueued
+	job.CreatedAt = time.Now()
+	job.done = make(chan struct{})
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("job_%d_%d", job.CreatedAt.UnixNano(), q.seq)
+	}
+
+	heap.Push(&q.pending, job)
+	q.jobs[job.ID] = job
+	q.pruneLocked()
+
+	// 唤醒一个空闲worker
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Wait 等待任务执行结束，返回任务的最终状态
+func (q *MeetingJobQueue) Wait(ctx context.Context, jobID string) (MeetingJob, error) {
+	q.mu.Lock()
+	job, ok := q.jobs[jobID]
+	q.mu.Unlock()
+	if !ok {
+		return MeetingJob{}, ErrJobNotFound
+	}
+
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return MeetingJob{}, ctx.Err()
+	}
+
+	return q.Get(jobID)
+}
+
+// Get 获取任务当前状态的快照
+func (q *MeetingJobQueue) Get(jobID string) (MeetingJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[jobID]
+	if !ok {
+		return MeetingJob{}, ErrJobNotFound
+	}
+	return *job, nil
+}
+
+// Stats 返回队列的监控指标
+func (q *MeetingJobQueue) Stats() JobQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return JobQueueStats{
+		Workers:    q.workers,
+		MaxPending: q.maxPending,
+		Pending:    q.pending.Len(),
+		Running:    q.running,
+		Succeeded:  q.succeeded,
+		Failed:     q.failed,
+		Rejected:   q.rejected,
+	}
+}
+
+// work worker主循环，每次取出优先级最高的任务执行
+func (q *MeetingJobQueue) work(ctx context.Context) {
+	for {
+		job := q.next()
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.notify:
+				continue
+			}
+		}
+
+		q.run(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// next 取出下一个待执行任务，队列为空时返回 nil
+func (q *MeetingJobQueue) next() *MeetingJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pending.Len() == 0 {
+		return nil
+	}
+
+	job := heap.Pop(&q.pending).(*MeetingJob)
+	job.Status = JobStatusRunning
+	job.StartedAt = Of(time.Now())
+	q.running++
+	q.inFlight.Add(1)
+
+	// 队列中仍有任务时继续唤醒其他worker
+	if q.pending.Len() > 0 {
+		select {
+		case q.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	return job
+}
+
+// run 执行任务并记录结果
+func (q *MeetingJobQueue) run(ctx context.Context, job *MeetingJob) {
+	err := q.handler(ctx, job)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.inFlight.Done()
+
+	q.running--
+	job.FinishedAt = Of(time.Now())
+	job.DocumentText = ""
+	if err != nil {
+		job.Status = JobStatusFailed
+		job.Error = err.Error()
+		q.failed++
+	} else {
+		job.Status = JobStatusSucceeded
+		q.succeeded++
+	}
+	close(job.done)
+	notifyJobCallback(job)
+}
+
+// notifyJobCallback 任务结束后在后台发送回调，调用方需持有队列锁
+func notifyJobCallback(job *MeetingJob) {
+	if job.CallbackURL == "" {
+		return
+	}
+	go deliverJobCallback(job.CallbackURL, JobCallbackPayload{
+		JobID:      job.ID,
+		MeetingID:  job.MeetingID,
+		Status:     job.Status,
+		Error:      job.Error,
+		FinishedAt: *job.FinishedAt,
+	})
+}
+
+// Shutdown 停止接收新任务，未开始的任务标记为失败，并等待正在执行的任务完成或 ctx 超时
+func (q *MeetingJobQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	now := time.Now()
+	for q.pending.Len() > 0 {
+		job := heap.Pop(&q.pending).(*MeetingJob)
+		job.Status = JobStatusFailed
+		job.Error = ErrQueueClosed.Error()
+		job.FinishedAt = &now
+		job.DocumentText = ""
+		q.failed++
+		close(job.done)
+		notifyJobCallback(job)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pruneLocked 清理过期的已结束任务，调用方需持有锁
+func (q *MeetingJobQueue) pruneLocked() {
+	cutoff := time.Now().Add(-finishedJobRetention)
+	for id, job := range q.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+// jobHeap 按优先级（高优先）和提交顺序（先提交优先）排序的任务堆
+type jobHeap []*MeetingJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].Priority != h[j].Priority {
+		return h[i].Priority > h[j].Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x interface{}) {
+	*h = append(*h, x.(*MeetingJob))
+}
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return job
+}
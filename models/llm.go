@@ -0,0 +1,36 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// 支持的模型提供方，对应配置文件中的 provider
+const (
+	LLMProviderARK    = "ark"
+	LLMProviderOpenAI = "openai"
+	LLMProviderMock   = "mock" // 离线模拟，返回固定的示例结果，不需要 API 密钥和网络
+)
+
+// LLM 聊天模型，方法签名与 eino 的 ChatModel 一致，ark.ChatModel 可直接满足该接口
+type LLM interface {
+	Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error)
+	Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error)
+}
+
+// newChatModel 按配置的模型提供方创建聊天模型
+func newChatModel(ctx context.Context, spec ModelSpec) (LLM, error) {
+	switch provider := GetLLMProvider(); provider {
+	case LLMProviderARK:
+		return newARKChatModel(ctx, spec)
+	case LLMProviderOpenAI:
+		return newOpenAIChatModel(spec)
+	case LLMProviderMock:
+		return newMockChatModel(spec), nil
+	default:
+		return nil, fmt.Errorf("不支持的模型提供方: %s", provider)
+	}
+}
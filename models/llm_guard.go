@@ -0,0 +1,65 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// 模型调用的默认保护限制
+const (
+	defaultLLMTimeout     = 60 * time.Second
+	defaultMaxOutputChars = 20000
+)
+
+// truncatedMarker 模型输出超出长度上限被截断时追加的标记
+const truncatedMarker = "[truncated]"
+
+// GetLLMTimeout 获取单次模型调用的超时时间，未配置时默认60秒
+func GetLLMTimeout() time.Duration {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.LLM.TimeoutSeconds <= 0 {
+		return defaultLLMTimeout
+	}
+	return time.Duration(cfg.LLM.TimeoutSeconds) * time.Second
+}
+
+// GetMaxOutputChars 获取单次模型调用允许的最大输出字符数，未配置时默认20000
+func GetMaxOutputChars() int {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.LLM.MaxOutputChars <= 0 {
+		return defaultMaxOutputChars
+	}
+	return cfg.LLM.MaxOutputChars
+}
+
+// withLLMTimeout 为单次模型调用设置截止时间，防止上游流停滞时请求一直不结束
+func withLLMTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, GetLLMTimeout())
+}
+
+// outputGuard 累计模型输出的字符数，超出上限后丢弃之后的输出
+type outputGuard struct {
+	maxRunes int
+	written  int
+}
+
+// newOutputGuard 创建使用配置上限的输出保护
+func newOutputGuard() *outputGuard {
+	return &outputGuard{maxRunes: GetMaxOutputChars()}
+}
+
+// Push 追加一段模型输出，返回上限内的部分；超出上限时 exceeded 为 true，调用方应停止读取流
+func (g *outputGuard) Push(content string) (out string, exceeded bool) {
+	runes := []rune(content)
+	if g.written+len(runes) <= g.maxRunes {
+		g.written += len(runes)
+		return content, false
+	}
+
+	remaining := g.maxRunes - g.written
+	if remaining < 0 {
+		remaining = 0
+	}
+	g.written = g.maxRunes
+	return string(runes[:remaining]), true
+}
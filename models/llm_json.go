@@ -0,0 +1,169 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// errNoJSONObject 模型输出中找不到可解析的JSON对象
+var errNoJSONObject = errors.New("输出中没有JSON对象")
+
+// parseJSONObject 从模型输出中解析第一个JSON对象到 v。
+// 依次容忍 markdown 代码块包裹、对象前的说明文字、对象后的附加说明或第二个对象，以及对象内多余的尾随逗号。
+// 找不到JSON对象时返回 errNoJSONObject
+func parseJSONObject(content string, v interface{}) error {
+	candidates := []string{content}
+	if fenced, ok := fencedBlock(content); ok {
+		// 优先解析代码块内的内容，代码块内没有对象时再回退到全文
+		candidates = []string{fenced, content}
+	}
+
+	var firstErr error
+	for _, candidate := range candidates {
+		for _, text := range []string{candidate, removeTrailingCommas(candidate)} {
+			raw, err := firstJSONObject(text)
+			if err != nil {
+				if firstErr == nil && !errors.Is(err, errNoJSONObject) {
+					firstErr = err
+				}
+				continue
+			}
+			return json.Unmarshal(raw, v)
+		}
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return errNoJSONObject
+}
+
+// firstJSONObject 返回第一个可解析的顶层JSON对象，对象之后的内容被忽略。
+// 只尝试从顶层（不在另一对花括号内）的 { 开始的片段：片段解析失败时跳过整个片段继续向后查找，
+// 片段直到文本末尾都没有闭合（通常是输出被截断）时直接返回错误，不会退而解析其中嵌套的对象
+func firstJSONObject(text string) (json.RawMessage, error) {
+	var firstErr error
+	for offset := 0; ; {
+		start := strings.Index(text[offset:], "{")
+		if start < 0 {
+			break
+		}
+		start += offset
+
+		end, ok := matchingBrace(text, start)
+		if !ok {
+			var raw json.RawMessage
+			err := json.NewDecoder(strings.NewReader(text[start:])).Decode(&raw)
+			if err == nil {
+				err = errors.New("JSON对象不完整")
+			}
+			return nil, err
+		}
+
+		var raw json.RawMessage
+		err := json.Unmarshal([]byte(text[start:end+1]), &raw)
+		if err == nil {
+			return raw, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		offset = end + 1
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, errNoJSONObject
+}
+
+// matchingBrace 返回与 text[start] 处的 { 配对的 } 的位置，字符串内的花括号不计入；
+// 到文本末尾仍未闭合时返回 false
+func matchingBrace(text string, start int) (int, bool) {
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		ch := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// fencedBlock 提取第一个 markdown 代码块（```json ... ```）的内容
+func fencedBlock(content string) (string, bool) {
+	start := strings.Index(content, "```")
+	if start < 0 {
+		return "", false
+	}
+
+	body := content[start+3:]
+	// 跳过代码块的语言标记，例如 json
+	if newline := strings.Index(body, "\n"); newline >= 0 && !strings.Contains(body[:newline], "{") {
+		body = body[newline+1:]
+	}
+
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return body, true
+}
+
+// removeTrailingCommas 去掉 } 和 ] 前多余的逗号，字符串内的内容保持不变
+func removeTrailingCommas(text string) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		if inString {
+			sb.WriteByte(ch)
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+
+		if ch == '"' {
+			inString = true
+		} else if ch == ',' {
+			j := i + 1
+			for j < len(text) && strings.IndexByte(" \t\r\n", text[j]) >= 0 {
+				j++
+			}
+			if j < len(text) && (text[j] == '}' || text[j] == ']') {
+				continue
+			}
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
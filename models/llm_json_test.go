@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONObject(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		want      map[string]interface{}
+		wantErr   bool
+		wantNoObj bool // 期望返回 errNoJSONObject
+	}{
+		{
+			name:    "plain object",
+			content: `{"title": "周会", "score": 3}`,
+			want:    map[string]interface{}{"title": "周会", "score": float64(3)},
+		},
+		{
+			name:    "fenced with language tag",
+			content: "```json\n{\"title\": \"周会\"}\n```",
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "fenced without language tag",
+			content: "```\n{\"title\": \"周会\"}\n```",
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "fenced with prose around",
+			content: "以下是结果：\n```json\n{\"title\": \"周会\"}\n```\n如有问题请告诉我。",
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "prose before object",
+			content: "好的，结果如下：{\"title\": \"周会\"}",
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "trailing commentary",
+			content: "{\"title\": \"周会\"}\n\n说明：标题根据会议内容推断，{仅供参考}。",
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "second object after the first",
+			content: `{"title": "周会"} {"title": "另一个"}`,
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "trailing commas",
+			content: "{\"tags\": [\"a\", \"b\",], \"title\": \"周会\",\n}",
+			want:    map[string]interface{}{"tags": []interface{}{"a", "b"}, "title": "周会"},
+		},
+		{
+			name:    "comma inside string is kept",
+			content: `{"title": "a,}", "note": "b,]",}`,
+			want:    map[string]interface{}{"title": "a,}", "note": "b,]"},
+		},
+		{
+			name:    "braces inside string",
+			content: `{"summary": "讨论了 {预算} 问题"} 附注`,
+			want:    map[string]interface{}{"summary": "讨论了 {预算} 问题"},
+		},
+		{
+			name:    "truncated object",
+			content: `{"title": "周会", "summary": "讨论了`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated object with complete nested object",
+			content: `{"meeting": {"title": "周会"}, "summary": "讨论了`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated fenced object with complete nested object",
+			content: "```json\n{\"todo_list\": [{\"content\": \"提交预算\"}, {\"content\": \"整理",
+			wantErr: true,
+		},
+		{
+			name:    "invalid object before valid one",
+			content: `{title: 周会} {"title": "周会"}`,
+			want:    map[string]interface{}{"title": "周会"},
+		},
+		{
+			name:    "unquoted keys",
+			content: `{title: 周会}`,
+			wantErr: true,
+		},
+		{
+			name:      "no object",
+			content:   "抱歉，我无法完成这个请求。",
+			wantErr:   true,
+			wantNoObj: true,
+		},
+		{
+			name:      "empty input",
+			content:   "",
+			wantErr:   true,
+			wantNoObj: true,
+		},
+		{
+			name:      "array instead of object",
+			content:   `["周会"]`,
+			wantErr:   true,
+			wantNoObj: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			err := parseJSONObject(tt.content, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望返回错误，得到 %v", got)
+				}
+				if tt.wantNoObj != errors.Is(err, errNoJSONObject) {
+					t.Errorf("错误 = %v，是否为 errNoJSONObject 期望 %v", err, tt.wantNoObj)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSONObject() = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
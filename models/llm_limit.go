@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GetLLMMaxConcurrent 获取全局同时进行的模型调用数上限，未配置或不大于0时不限制
+func GetLLMMaxConcurrent() int {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.LLM.MaxConcurrent <= 0 {
+		return 0
+	}
+	return cfg.LLM.MaxConcurrent
+}
+
+// llmLimiter 限制全局同时进行的模型调用数，达到上限的调用按到达顺序排队等待
+type llmLimiter struct {
+	mu       sync.Mutex
+	inFlight int
+	waiters  []chan struct{}
+}
+
+// defaultLLMLimiter 所有通过模型工厂获取的模型共享的并发限制
+var defaultLLMLimiter = &llmLimiter{}
+
+// LLMConcurrencyStats 模型调用并发情况
+type LLMConcurrencyStats struct {
+	InFlight int // 正在进行的模型调用数
+	Waiting  int // 排队等待的模型调用数
+	Limit    int // 并发上限，0 表示不限制
+}
+
+// GetLLMConcurrencyStats 获取当前模型调用的并发情况
+func GetLLMConcurrencyStats() LLMConcurrencyStats {
+	defaultLLMLimiter.mu.Lock()
+	defer defaultLLMLimiter.mu.Unlock()
+	return LLMConcurrencyStats{
+		InFlight: defaultLLMLimiter.inFlight,
+		Waiting:  len(defaultLLMLimiter.waiters),
+		Limit:    GetLLMMaxConcurrent(),
+	}
+}
+
+// acquire 获取一个调用名额，达到上限时排队等待，ctx 取消时放弃等待并返回 ctx.Err()
+func (l *llmLimiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if limit := GetLLMMaxConcurrent(); limit <= 0 || (l.inFlight < limit && len(l.waiters) == 0) {
+		l.inFlight++
+		l.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		for i, waiter := range l.waiters {
+			if waiter == ready {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				l.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		l.mu.Unlock()
+		// 取消的同时已经分到名额，交给下一个等待者
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// release 归还调用名额，有等待者且未超出上限时直接转交给最早的等待者
+func (l *llmLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := GetLLMMaxConcurrent()
+	l.inFlight--
+	for len(l.waiters) > 0 && (limit <= 0 || l.inFlight < limit) {
+		l.inFlight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+// limitedLLM 调用前先获取全局并发名额的聊天模型；流式调用的名额在读完或关闭流后归还
+type limitedLLM struct {
+	LLM
+	limiter *llmLimiter
+}
+
+// Generate 获取名额后调用模型
+func (m *limitedLLM) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := m.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer m.limiter.release()
+	return m.LLM.Generate(ctx, input, opts...)
+}
+
+// Stream 获取名额后调用模型，通过管道转发上游流，以便在流结束时归还名额
+func (m *limitedLLM) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := m.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	upstream, err := m.LLM.Stream(ctx, input, opts...)
+	if err != nil {
+		m.limiter.release()
+		return nil, err
+	}
+
+	reader, writer := schema.Pipe[*schema.Message](0)
+	go func() {
+		defer m.limiter.release()
+		defer upstream.Close()
+		defer writer.Close()
+		for {
+			chunk, err := upstream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			// 调用方已关闭流时停止读取上游
+			if closed := writer.Send(chunk, err); closed || err != nil {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 支持的回答语言
+const (
+	LocaleZH = "zh" // 中文（默认）
+	LocaleEN = "en" // 英文
+	LocaleJA = "ja" // 日文
+)
+
+// DefaultLocale 默认回答语言，未指定或不支持时使用
+const DefaultLocale = LocaleZH
+
+// localeInstructions 各语言追加到系统提示末尾的回答语言要求，中文提示本身即为中文，无需追加
+var localeInstructions = map[string]string{
+	LocaleZH: "",
+	LocaleEN: "Always respond in English, regardless of the language of the meeting content or these instructions. Keep JSON field names unchanged if a JSON format is required.",
+	LocaleJA: "会議内容や指示の言語に関係なく、必ず日本語で回答してください。JSON形式が求められている場合、フィールド名は変更しないでください。",
+}
+
+// localeKey 回答语言在 context 中的键
+type localeKey struct{}
+
+// WithLocale 返回携带回答语言的 context，之后使用该 context 的聊天、评分、摘要等模型调用按该语言回答
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, NormalizeLocale(locale))
+}
+
+// LocaleFromContext 获取 context 中的回答语言，没有时返回默认语言
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// NormalizeLocale 将语言标签（例如 en-US、zh_CN）归一化为支持的语言，不支持时返回默认语言
+func NormalizeLocale(tag string) string {
+	if locale, ok := matchLocale(tag); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// ResolveLocale 确定请求的回答语言：lang 参数优先，其次按 Accept-Language 请求头的权重选择第一个支持的语言，
+// 都没有匹配时返回默认语言
+func ResolveLocale(lang, acceptLanguage string) string {
+	if locale, ok := matchLocale(lang); ok {
+		return locale
+	}
+
+	type weightedTag struct {
+		tag    string
+		weight float64
+	}
+	var tags []weightedTag
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		tags = append(tags, weightedTag{tag: tag, weight: weight})
+	}
+
+	// 权重相同时保持请求头中的顺序
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].weight > tags[j].weight
+	})
+	for _, t := range tags {
+		if t.weight <= 0 {
+			continue
+		}
+		if locale, ok := matchLocale(t.tag); ok {
+			return locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+// matchLocale 按语言标签的主语言匹配支持的语言
+func matchLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := localeInstructions[tag]; ok {
+		return tag, true
+	}
+	return "", false
+}
+
+// withLocaleInstruction 在系统提示末尾追加 context 中回答语言的要求，默认语言时原样返回
+func withLocaleInstruction(ctx context.Context, prompt string) string {
+	instruction := localeInstructions[LocaleFromContext(ctx)]
+	if instruction == "" {
+		return prompt
+	}
+	return prompt + "\n\n" + instruction
+}
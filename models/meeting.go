@@ -4,15 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/schema"
 	"github.com/hertz-contrib/sse"
 )
@@ -25,7 +26,12 @@ type Meeting struct {
 
 // PostMeetingResponse represents the response for creating a meeting
 type PostMeetingResponse struct {
-	ID string `json:"id"`
+	ID    string `json:"id"`
+	JobID string `json:"job_id,omitempty"` // 异步模式下返回的任务ID
+	// 超出每个会议待办数上限、没有自动创建的待办，仅同步模式返回
+	UntrackedTodos []ExtractedTodo `json:"untracked_todos,omitempty"`
+	// 开始、结束时间无法解析或不合理时的说明，仅同步模式返回，异步模式可在元数据的 time_warnings 中查看
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // GetMeetingsResponse represents the response for listing meetings
@@ -35,13 +41,18 @@ type GetMeetingsResponse struct {
 
 // ChatMessage represents a chat message in the SSE stream
 type ChatMessage struct {
-	Data string `json:"data"`
+	Data            string `json:"data"`
+	MaxAnswerLength int    `json:"max_answer_length"` // 回答的最大字符数，不大于0时使用默认值
+	MaxCitations    int    `json:"max_citations"`     // 回答中最多引用的会议原文处数
+	Cite            bool   `json:"cite"`              // 要求模型逐字引用会议原文，并在结束事件中返回 citations
+	Transcript      string `json:"-"`                 // 会议原文，引用模式下用于定位引用
 }
 
 // ChatHistoryItem 表示一条聊天历史记录
 type ChatHistoryItem struct {
-	Role    string `json:"role"`    // 角色: user 或 assistant
-	Content string `json:"content"` // 消息内容
+	Role      string `json:"role"`      // 角色: user 或 assistant
+	Content   string `json:"content"`   // 消息内容
+	Timestamp string `json:"timestamp"` // 消息时间，RFC3339 格式
 }
 
 // ChatHistory 表示一个会话的聊天历史
@@ -84,27 +95,57 @@ func addToChatHistory(meetingID, sessionID, role, content string) {
 
 	chatHistoriesMutex.Lock()
 	history.Items = append(history.Items, ChatHistoryItem{
-		Role:    role,
-		Content: content,
+		Role:      role,
+		Content:   content,
+		Timestamp: time.Now().Format(time.RFC3339),
 	})
 	chatHistoriesMutex.Unlock()
 }
 
+// GetChatHistoryPage 按时间顺序返回会话从 offset 开始的至多 limit 条聊天记录及记录总数，
+// 会话尚无消息时返回空列表
+func GetChatHistoryPage(meetingID, sessionID string, offset, limit int) ([]ChatHistoryItem, int) {
+	chatHistoriesMutex.RLock()
+	defer chatHistoriesMutex.RUnlock()
+
+	items := []ChatHistoryItem{}
+	history, exists := chatHistories[getChatHistoryKey(meetingID, sessionID)]
+	if !exists {
+		return items, 0
+	}
+
+	total := len(history.Items)
+	if offset >= total {
+		return items, total
+	}
+	end := total
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return append(items, history.Items[offset:end]...), total
+}
+
 // RolePlayMessage 表示角色扮演聊天消息
 type RolePlayMessage struct {
 	Data            string `json:"data"`             // 会议内容数据
 	ParticipantName string `json:"participant_name"` // 参会人姓名
+	// 扮演参会者发言的温度，0-1，为 nil 时使用默认温度
+	Temperature *float32 `json:"temperature,omitempty"`
 }
 
 // MeetingScore 表示会议评分结果
 type MeetingScore struct {
-	GoalAchievement       int     `json:"goal_achievement"`       // 会议目标达成度
-	TopicFocus            int     `json:"topic_focus"`            // 主题聚焦度
-	ParticipantEngagement int     `json:"participant_engagement"` // 参与者互动与参与度
-	TotalScore            int     `json:"total_score"`            // 总分
-	MaxPossibleScore      int     `json:"max_possible_score"`     // 最大可能得分
-	ScorePercentage       float64 `json:"score_percentage"`       // 得分百分比
-	Feedback              string  `json:"feedback"`               // 评价反馈
+	GoalAchievement       int      `json:"goal_achievement"`           // 会议目标达成度
+	TopicFocus            int      `json:"topic_focus"`                // 主题聚焦度
+	ParticipantEngagement int      `json:"participant_engagement"`     // 参与者互动与参与度
+	TotalScore            int      `json:"total_score"`                // 总分
+	MaxPossibleScore      int      `json:"max_possible_score"`         // 最大可能得分
+	ScorePercentage       float64  `json:"score_percentage"`           // 得分百分比
+	Grade                 string   `json:"grade"`                      // 按得分百分比划分的等级，阈值见 GetGradeThresholds
+	ShortVerdict          string   `json:"short_verdict"`              // 一句话结论
+	Feedback              string   `json:"feedback"`                   // 评价反馈
+	Partial               bool     `json:"partial"`                    // 是否有指标未评估，未评估的指标得分为0且不计入满分
+	MissingCriteria       []string `json:"missing_criteria,omitempty"` // 未评估的指标
 }
 
 // FeiShuWebhookConfig 飞书机器人配置
@@ -119,6 +160,47 @@ type MeetingReport struct {
 	Summary      string   `json:"summary"`      // 会议摘要
 	Participants []string `json:"participants"` // 参会人员
 	TodoList     []string `json:"todo_list"`    // 待办事项
+
+	StartTime       string `json:"start_time,omitempty"`       // 会议开始时间，使用抽取的原文
+	EndTime         string `json:"end_time,omitempty"`         // 会议结束时间，使用抽取的原文
+	DurationMinutes int    `json:"duration_minutes,omitempty"` // 会议时长（分钟），无法计算时省略
+	Duration        string `json:"duration,omitempty"`         // 会议时长的展示文本，例如"1小时30分钟"
+
+	Score     *MeetingScore `json:"score,omitempty"`      // 会议评分，为空时报告中不包含评分
+	CardColor string        `json:"card_color,omitempty"` // 飞书卡片标题颜色，为空时使用默认颜色
+}
+
+// DefaultFeiShuCardColor 飞书卡片默认的标题颜色
+const DefaultFeiShuCardColor = "blue"
+
+// feiShuCardColors 飞书卡片标题支持的颜色模板
+var feiShuCardColors = []string{
+	"blue", "wathet", "turquoise", "green", "yellow", "orange",
+	"red", "carmine", "violet", "purple", "indigo", "grey", "default",
+}
+
+// NormalizeFeiShuCardColor 校验飞书卡片颜色，为空时返回默认颜色
+func NormalizeFeiShuCardColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" {
+		return DefaultFeiShuCardColor, nil
+	}
+	for _, allowed := range feiShuCardColors {
+		if color == allowed {
+			return color, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的卡片颜色: %s，可选值: %s", color, strings.Join(feiShuCardColors, "、"))
+}
+
+// reportScoreLines 返回报告中展示会议评分的文本行，详细评价篇幅较长不放入报告
+func reportScoreLines(score *MeetingScore) []string {
+	return []string{
+		fmt.Sprintf("总分: %d/%d（%.0f%%，等级 %s）", score.TotalScore, score.MaxPossibleScore, score.ScorePercentage, score.Grade),
+		fmt.Sprintf("目标达成度: %d/4", score.GoalAchievement),
+		fmt.Sprintf("主题聚焦度: %d/4", score.TopicFocus),
+		fmt.Sprintf("参与度: %d/4", score.ParticipantEngagement),
+	}
 }
 
 // FeiShuMessage 表示飞书消息的结构
@@ -174,42 +256,22 @@ type Action struct {
 	Value string `json:"value,omitempty"`
 }
 
+// EventPublisher 流式回答的事件输出，SSE 连接（*sse.Stream）和 WebSocket 连接都实现该接口
+type EventPublisher interface {
+	Publish(event *sse.Event) error
+}
+
 func Of[T any](v T) *T {
 	return &v
 }
 
-// Process handles the chat message and returns streaming response to the SSE stream
-func (c ChatMessage) Process(query string, stream *sse.Stream, meetingID, sessionID string) error {
-	// 从配置文件中获取API密钥和模型名称
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		fmt.Printf("获取API密钥失败: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 获取API密钥失败")),
-		}
-		return stream.Publish(event)
-	}
-
-	arkModelName, err := GetARKModelName()
+// Process handles the chat message and returns streaming response to the SSE stream or WebSocket connection.
+// ctx 取消时停止生成并返回 ctx.Err()
+func (c ChatMessage) Process(ctx context.Context, query string, stream EventPublisher, meetingID, sessionID string) error {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureChat, 0.6)
 	if err != nil {
-		fmt.Printf("获取模型名称失败: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 获取模型名称失败")),
-		}
-		return stream.Publish(event)
-	}
-
-	ctx := context.Background()
-	arkModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.6)),
-	})
-	if err != nil {
-		fmt.Printf("failed to create chat model: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 创建聊天模型失败")),
-		}
+		Logf(ctx, "failed to create chat model: %v", err)
+		event := NewStreamEvent(StreamEventError, []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 创建聊天模型失败")))
 		return stream.Publish(event)
 	}
 
@@ -219,15 +281,31 @@ func (c ChatMessage) Process(query string, stream *sse.Stream, meetingID, sessio
 	// 将用户当前问题添加到聊天历史
 	addToChatHistory(meetingID, sessionID, "user", query)
 
+	maxAnswerLength, maxCitations := NormalizeChatLimits(c.MaxAnswerLength, c.MaxCitations)
+	// 引用模式必须引用原文，max_citations 为0时使用默认数量
+	if c.Cite && maxCitations == 0 {
+		maxCitations = DefaultMaxCitations
+	}
+
+	systemPrompt, err := RenderPrompt(PromptChat, PromptData{
+		MeetingContent: c.Data,
+		Query:          wrapUserInput(query),
+		AnswerLimits:   chatLimitPrompt(maxAnswerLength, maxCitations, c.Cite),
+	})
+	if err != nil {
+		Logf(ctx, "渲染聊天提示失败: %v\n", err)
+		return publishStreamError(stream, "生成回答失败: "+err.Error())
+	}
+
 	// 准备消息
 	messages := []*schema.Message{
-		schema.SystemMessage("你是一个会议助手，负责回答用户关于会议内容的问题。"),
+		schema.SystemMessage(withLocaleInstruction(ctx, systemPrompt+untrustedInputInstruction)),
 	}
 
 	// 添加会议内容作为背景信息
 	messages = append(messages, schema.UserMessage("以下是会议内容，你需要根据这些内容回答用户问题：\n"+c.Data))
 
-	// 添加历史对话记录
+	// 添加历史对话记录，用户消息清理后放入分隔标签，与会议内容分开
 	chatHistoriesMutex.RLock()
 	historyItems := history.Items
 	chatHistoriesMutex.RUnlock()
@@ -241,94 +319,132 @@ func (c ChatMessage) Process(query string, stream *sse.Stream, meetingID, sessio
 	for i := startIdx; i < len(historyItems); i++ {
 		item := historyItems[i]
 		if item.Role == "user" {
-			messages = append(messages, schema.UserMessage(item.Content))
+			messages = append(messages, schema.UserMessage(wrapUserInput(item.Content)))
 		} else {
 			messages = append(messages, schema.AssistantMessage(item.Content, nil))
 		}
 	}
 
-	// 使用流式生成回答
-	reader, err := arkModel.Stream(ctx, messages)
+	// 使用流式生成回答，超时后停止读取上游流
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	reader, err := chatModel.Stream(callCtx, messages)
+	recordLLMCall(ctx, nil, err)
 	if err != nil {
-		fmt.Printf("failed to generate streaming response: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 生成流式回答失败")),
-		}
+		Logf(ctx, "failed to generate streaming response: %v", err)
+		event := NewStreamEvent(StreamEventError, []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 生成流式回答失败")))
 		return stream.Publish(event)
 	}
 	defer reader.Close()
 
-	// 处理流式响应
+	// 处理流式响应，超出长度上限时在句子边界处收尾
 	var fullResponse strings.Builder
+	limiter := newAnswerLimiter(maxAnswerLength)
+	guard := newOutputGuard()
+	truncated := false
 	for {
 		chunk, err := reader.Recv()
-		if err != nil {
-			// 流结束或发生错误
+		recordLLMUsage(ctx, chunk)
+		if errors.Is(err, io.EOF) {
+			// 流正常结束
 			break
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// 请求已取消，客户端不再等待回答，无需发送错误事件
+			return ctxErr
+		}
+		if err != nil {
+			// 模型调用失败，通知客户端而不是直接结束流，失败的回答不计入聊天历史
+			Logf(ctx, "接收流式回答失败: %v\n", err)
+			return publishStreamError(stream, streamErrorMessage(callCtx, err))
+		}
 
-		fullResponse.WriteString(chunk.Content)
+		// 模型输出超出最大字符数时丢弃之后的输出，并追加截断标记
+		output, exceeded := guard.Push(chunk.Content)
+		content, reachedLimit := limiter.Push(output)
+		if exceeded && !reachedLimit {
+			content += limiter.Flush() + truncatedMarker
+		}
+		if err := publishChatChunk(stream, &fullResponse, content); err != nil {
+			return err
+		}
 
-		// 将每个块作为SSE事件发送
-		jsonResponse := fmt.Sprintf(`{"data":%q}`, chunk.Content)
-		event := &sse.Event{
-			Data: []byte(jsonResponse),
+		if reachedLimit || exceeded {
+			truncated = true
+			break
 		}
+	}
 
-		if err := stream.Publish(event); err != nil {
-			fmt.Printf("发送SSE事件失败: %v", err)
+	if !truncated {
+		if err := publishChatChunk(stream, &fullResponse, limiter.Flush()); err != nil {
 			return err
 		}
 	}
 
+	// 通知客户端回答结束，truncated 表示回答因长度上限被截断，引用模式下附带定位到会议原文的引用
+	done := map[string]interface{}{"truncated": truncated}
+	if c.Cite {
+		done["citations"] = extractCitations(fullResponse.String(), c.Transcript, maxCitations)
+	}
+	if err := publishStreamDone(stream, done); err != nil {
+		return err
+	}
+
 	// 将AI回答添加到聊天历史
 	addToChatHistory(meetingID, sessionID, "assistant", fullResponse.String())
 
 	return nil
 }
 
-// 原始非流式Process方法，保留作为参考或备用
-func (c ChatMessage) ProcessNonStream(query string) string {
-	// 从配置文件中获取API密钥和模型名称
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		fmt.Printf("获取API密钥失败: %v", err)
-		return "错误: 获取API密钥失败"
+// streamErrorMessage 返回接收流式回答失败时发给客户端的错误信息，区分模型调用超时
+func streamErrorMessage(callCtx context.Context, err error) string {
+	if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return "生成回答超时"
 	}
+	return "生成回答失败: " + err.Error()
+}
 
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		fmt.Printf("获取模型名称失败: %v", err)
-		return "错误: 获取模型名称失败"
+// publishChatChunk 将一段回答作为SSE事件发送并记录到完整回答中，空内容不发送
+func publishChatChunk(stream EventPublisher, fullResponse *strings.Builder, content string) error {
+	if content == "" {
+		return nil
 	}
 
-	ctx := context.Background()
-	arkModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.6)),
-	})
-	if err != nil {
-		fmt.Printf("failed to create chat model: %v", err)
-		return "错误: 创建聊天模型失败"
+	fullResponse.WriteString(content)
+
+	// 将每个块作为SSE事件发送
+	jsonResponse := fmt.Sprintf(`{"data":%q}`, content)
+	event := NewStreamEvent(StreamEventMessage, []byte(jsonResponse))
+
+	if err := stream.Publish(event); err != nil {
+		fmt.Printf("发送SSE事件失败: %v", err)
+		return err
 	}
+	return nil
+}
 
-	// 拼接c.Data和query作为prompt
-	prompt := c.Data
-	if query != "" {
-		prompt = prompt + "\n用户问题: " + query
+// 原始非流式Process方法，保留作为参考或备用
+func (c ChatMessage) ProcessNonStream(ctx context.Context, query string) string {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureChat, 0.6)
+	if err != nil {
+		Logf(ctx, "failed to create chat model: %v", err)
+		return "错误: 创建聊天模型失败"
 	}
 
-	// 准备消息
+	// 会议内容和用户问题分别作为消息，用户问题清理后放入分隔标签
 	messages := []*schema.Message{
-		schema.SystemMessage("你是一个会议助手，负责回答用户关于会议内容的问题。"),
-		schema.UserMessage(prompt),
+		schema.SystemMessage("你是一个会议助手，负责回答用户关于会议内容的问题。" + untrustedInputInstruction),
+		schema.UserMessage(c.Data),
+	}
+	if query != "" {
+		messages = append(messages, schema.UserMessage(wrapUserInput(query)))
 	}
 
 	// 生成回答
-	response, err := arkModel.Generate(ctx, messages)
+	response, err := chatModel.Generate(ctx, messages)
+	recordLLMCall(ctx, response, err)
 	if err != nil {
-		fmt.Printf("failed to generate response: %v", err)
+		Logf(ctx, "failed to generate response: %v", err)
 		return "错误: 生成回答失败"
 	}
 
@@ -337,96 +453,131 @@ func (c ChatMessage) ProcessNonStream(query string) string {
 	return jsonResponse
 }
 
-// ExtractMeetingInfo 使用LLM从会议文本中提取结构化信息
-func ExtractMeetingInfo(ctx context.Context, documentText string) (map[string]interface{}, error) {
-	// 从配置文件中获取API密钥和模型名称
-	arkAPIKey, err := GetARKAPIKey()
+// ExtractMeetingInfo 使用LLM从会议文本中提取结构化信息，内容超过分段长度时分段抽取后合并。
+// meetingType 为用户指定的会议类型，为空时由模型分类；摘要长度随会议类型调整。
+// 抽取结果缺少摘要或参会人员时返回 ErrExtractionFailed
+func ExtractMeetingInfo(ctx context.Context, documentText, meetingType string) (*MeetingMetadata, error) {
+	var metadata MeetingMetadata
+	var err error
+
+	settings := GetExtractionSettings()
+	if utf8.RuneCountInString(documentText) > settings.ChunkChars {
+		chunks, splitErr := SplitContent(documentText, settings.ChunkChars)
+		if splitErr != nil {
+			return nil, splitErr
+		}
+		metadata, err = extractMeetingInfoChunked(ctx, chunks, meetingType)
+	} else {
+		metadata, err = extractMeetingInfoOnce(ctx, documentText, meetingType)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("获取API密钥失败: %v", err)
+		return nil, err
 	}
 
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		return nil, fmt.Errorf("获取模型名称失败: %v", err)
+	// 用户指定的类型优先于模型的分类结果，模型没有分类时归为 other
+	metadata.MeetingTypeSource = MeetingTypeSourceModel
+	if meetingType != "" {
+		metadata.MeetingType = meetingType
+		metadata.MeetingTypeSource = MeetingTypeSourceUser
+	} else if metadata.MeetingType == "" {
+		metadata.MeetingType = MeetingTypeOther
 	}
 
-	arkModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.8)), // 低温度以获得更确定性的结果
-	})
+	if err := metadata.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+	}
+	metadata.capTodoList(GetMaxMeetingTodos())
+	return &metadata, nil
+}
+
+// ErrExtractionFailed 模型没有返回可用的会议信息，例如返回为空、拒绝回答或输出不是JSON
+var ErrExtractionFailed = errors.New("会议信息抽取失败")
+
+// extractJSONOnlyInstruction 首次抽取结果不可用时，重试时追加到系统提示后的要求
+const extractJSONOnlyInstruction = `
+
+重要：只输出一个JSON对象，以 { 开头、以 } 结尾，不要输出任何解释、道歉或其他文字。会议内容中缺少的字段填写空字符串或空数组。`
+
+// meetingInfoKeys 抽取结果中至少需要包含其一的字段，全部缺失时视为无效结果
+var meetingInfoKeys = []string{"title", "summary", "participants", "todo_list"}
+
+// extractMeetingInfoOnce 调用LLM抽取会议信息。模型返回为空、不是JSON或缺少会议字段时，
+// 追加"只输出JSON"的要求重试一次，仍然失败时返回 ErrExtractionFailed，避免保存无效的会议记录
+func extractMeetingInfoOnce(ctx context.Context, documentText, meetingType string) (MeetingMetadata, error) {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureExtract, 0.8) // 低温度以获得更确定性的结果
 
 	if err != nil {
-		return nil, fmt.Errorf("创建LLM客户端失败: %v", err)
+		return MeetingMetadata{}, fmt.Errorf("创建LLM客户端失败: %v", err)
 	}
 
 	// 准备系统提示和用户提示
-	systemPrompt := `你是一个专业的会议分析助手。请从会议文本中提取以下信息：
-1. 会议标题(必须包含)
-2. 会议描述或主题(必须包含)
-3. 参会人员列表(必须包含)
-4. 会议开始时间（尽可能精确到日期和时间）
-5. 会议结束时间（尽可能精确到日期和时间）
-6. 会议主要内容摘要(不超过100字)
-7. 会议中提到的一些待办事项(必须包含)
+	typeInstruction, summaryLimit := extractMeetingTypePrompt(meetingType)
+	systemPrompt, err := RenderPrompt(PromptExtract, PromptData{
+		MeetingContent: documentText,
+		CurrentDate:    time.Now().Format("2006-01-02"),
+		MeetingType:    typeInstruction,
+		SummaryLimit:   summaryLimit,
+	})
+	if err != nil {
+		return MeetingMetadata{}, err
+	}
 
-以JSON格式返回,字段包括:title, description, participants(数组), start_time, end_time, summary, todo_list(数组)。`
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		prompt := systemPrompt
+		if attempt > 0 {
+			Logf(ctx, "会议信息抽取结果无效，要求只输出JSON后重试: %v\n", lastErr)
+			prompt += extractJSONOnlyInstruction
+		}
 
-	// 准备消息
-	messages := []*schema.Message{
-		schema.SystemMessage(systemPrompt),
-		schema.UserMessage(documentText),
+		// 准备消息
+		messages := []*schema.Message{
+			schema.SystemMessage(prompt),
+			schema.UserMessage(documentText),
+		}
+
+		// 生成回答
+		response, err := chatModel.Generate(ctx, messages)
+		recordLLMCall(ctx, response, err)
+		if err != nil {
+			return MeetingMetadata{}, fmt.Errorf("生成分析失败: %v", err)
+		}
+
+		meetingInfo, err := parseMeetingInfo(response.Content)
+		if err == nil {
+			return meetingInfo, nil
+		}
+		lastErr = err
 	}
 
-	// 生成回答
-	response, err := arkModel.Generate(ctx, messages)
-	if err != nil {
-		return nil, fmt.Errorf("生成分析失败: %v", err)
+	return MeetingMetadata{}, fmt.Errorf("%w: %v", ErrExtractionFailed, lastErr)
+}
+
+// parseMeetingInfo 解析模型返回的会议信息，返回为空、不是JSON对象或不包含任何会议字段时返回错误
+func parseMeetingInfo(content string) (MeetingMetadata, error) {
+	if strings.TrimSpace(content) == "" {
+		return MeetingMetadata{}, fmt.Errorf("模型返回为空")
 	}
 
-	// 解析JSON响应
 	var meetingInfo map[string]interface{}
-	if err := json.Unmarshal([]byte(response.Content), &meetingInfo); err != nil {
-		// 如果解析失败，尝试从文本中提取JSON部分
-		jsonStartIdx := strings.Index(response.Content, "{")
-		jsonEndIdx := strings.LastIndex(response.Content, "}")
-
-		if jsonStartIdx >= 0 && jsonEndIdx > jsonStartIdx {
-			jsonText := response.Content[jsonStartIdx : jsonEndIdx+1]
-			if err := json.Unmarshal([]byte(jsonText), &meetingInfo); err != nil {
-				return nil, fmt.Errorf("解析会议信息失败: %v", err)
-			}
-		} else {
-			// 如果无法提取JSON，则创建一个基本结构
-			meetingInfo = map[string]interface{}{
-				"title":       "未知会议",
-				"description": "无法从文本中提取会议信息",
-				"summary":     response.Content,
-			}
+	if err := parseJSONObject(content, &meetingInfo); err != nil {
+		if errors.Is(err, errNoJSONObject) {
+			return MeetingMetadata{}, fmt.Errorf("模型未返回JSON: %s", truncateRunes(strings.TrimSpace(content), 100))
 		}
+		return MeetingMetadata{}, fmt.Errorf("解析会议信息失败: %v", err)
 	}
 
-	return meetingInfo, nil
+	for _, key := range meetingInfoKeys {
+		if value, ok := meetingInfo[key]; ok && value != nil {
+			return DecodeMeetingMetadata(meetingInfo), nil
+		}
+	}
+	return MeetingMetadata{}, fmt.Errorf("模型返回的JSON中没有会议信息字段")
 }
 
 // ExtractMermaid 使用LLM从会议文本中总结出会议流程并输出对应的mermaid代码
 func ExtractMermaid(ctx context.Context, documentText string) (string, error) {
-	// 从配置文件中获取API密钥和模型名称
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		return "", fmt.Errorf("获取API密钥失败: %v", err)
-	}
-
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		return "", fmt.Errorf("获取模型名称失败: %v", err)
-	}
-
-	arkModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.7)), // 稍微提高创造性
-	})
+	chatModel, err := GetChatModel(ctx, 0.7) // 稍微提高创造性
 
 	if err != nil {
 		return "", fmt.Errorf("创建LLM客户端失败: %v", err)
@@ -459,7 +610,8 @@ flowchart TD
 	}
 
 	// 生成回答
-	response, err := arkModel.Generate(ctx, messages)
+	response, err := chatModel.Generate(ctx, messages)
+	recordLLMCall(ctx, response, err)
 	if err != nil {
 		return "", fmt.Errorf("生成流程图失败: %v", err)
 	}
@@ -479,222 +631,262 @@ flowchart TD
 	return content, nil
 }
 
-// ProcessRolePlay 处理角色扮演聊天并返回流式响应
-func (r RolePlayMessage) ProcessRolePlay(query string, stream *sse.Stream) error {
-	// 从配置文件中获取API密钥和模型名称
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		fmt.Printf("获取API密钥失败: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 获取API密钥失败")),
-		}
-		return stream.Publish(event)
-	}
-
-	arkModelName, err := GetARKModelName()
+// ProcessRolePlay 处理角色扮演聊天并返回流式响应，ctx 取消时停止生成并返回 ctx.Err()
+func (r RolePlayMessage) ProcessRolePlay(ctx context.Context, query string, stream EventPublisher) error {
+	chatModel, err := GetPersonaChatModel(ctx, r.Temperature) // 默认0.7，增加一点创造性，使角色扮演更生动
 	if err != nil {
-		fmt.Printf("获取模型名称失败: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 获取模型名称失败")),
-		}
+		Logf(ctx, "failed to create chat model: %v", err)
+		event := NewStreamEvent(StreamEventError, []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 创建聊天模型失败")))
 		return stream.Publish(event)
 	}
 
-	ctx := context.Background()
-	arkModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.7)), // 增加一点创造性，使角色扮演更生动
+	// 拼接角色扮演提示
+	prompt, err := RenderPrompt(PromptRolePlay, PromptData{
+		MeetingContent:  r.Data,
+		ParticipantName: r.ParticipantName,
+		Query:           wrapUserInput(query),
 	})
 	if err != nil {
-		fmt.Printf("failed to create chat model: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 创建聊天模型失败")),
-		}
-		return stream.Publish(event)
+		Logf(ctx, "渲染角色扮演提示失败: %v\n", err)
+		return publishStreamError(stream, "生成回答失败: "+err.Error())
 	}
 
-	// 拼接角色扮演提示
-	prompt := fmt.Sprintf(`
-会议内容: 
-%s
-
-你现在扮演的角色是会议中的参会者"%s"。你需要以这个人的身份、语气、知识背景、态度和说话方式来回答问题。
-根据会议记录中这个人的言论和表现来模仿他/她的回答风格。如果会议内容中没有足够的信息来确定这个人的某些观点，
-你可以基于这个人在会议中表现出的性格特点来合理推测，但要保持一致性。
-
-用户将以对话形式向你提问，你要始终保持角色扮演，不要暴露你是AI的事实。回答要简洁自然，符合真实对话的风格。
-
-用户问题: %s
-`, r.Data, r.ParticipantName, query)
-
-	// 准备消息
+	// 准备消息，角色设定和会议内容之后单独发送清理并放入分隔标签的用户问题
 	messages := []*schema.Message{
-		schema.SystemMessage("你正在进行角色扮演，扮演会议参会者。请完全沉浸在角色中，使用第一人称回答问题，仿佛你就是那个人。"),
+		schema.SystemMessage(withLocaleInstruction(ctx, "你正在进行角色扮演，扮演会议参会者。请完全沉浸在角色中，使用第一人称回答问题，仿佛你就是那个人。"+untrustedInputInstruction)),
 		schema.UserMessage(prompt),
+		schema.UserMessage(wrapUserInput(query)),
 	}
 
-	// 使用流式生成回答
-	reader, err := arkModel.Stream(ctx, messages)
+	// 使用流式生成回答，超时后停止读取上游流
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	reader, err := chatModel.Stream(callCtx, messages)
+	recordLLMCall(ctx, nil, err)
 	if err != nil {
-		fmt.Printf("failed to generate streaming response: %v", err)
-		event := &sse.Event{
-			Data: []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 生成流式回答失败")),
-		}
+		Logf(ctx, "failed to generate streaming response: %v", err)
+		event := NewStreamEvent(StreamEventError, []byte(fmt.Sprintf(`{"data":"%s"}`, "错误: 生成流式回答失败")))
 		return stream.Publish(event)
 	}
 	defer reader.Close()
 
 	// 处理流式响应
 	var fullResponse strings.Builder
+	guard := newOutputGuard()
+	truncated := false
 	for {
 		chunk, err := reader.Recv()
-		if err != nil {
-			// 流结束或发生错误
+		recordLLMUsage(ctx, chunk)
+		if errors.Is(err, io.EOF) {
+			// 流正常结束
 			break
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			Logf(ctx, "接收流式回答失败: %v\n", err)
+			return publishStreamError(stream, streamErrorMessage(callCtx, err))
+		}
 
-		fullResponse.WriteString(chunk.Content)
+		content, exceeded := guard.Push(chunk.Content)
+		if exceeded {
+			content += truncatedMarker
+		}
+		fullResponse.WriteString(content)
 
 		// 将每个块作为SSE事件发送
-		jsonResponse := fmt.Sprintf(`{"data":%q, "role":"%s"}`, chunk.Content, r.ParticipantName)
-		event := &sse.Event{
-			Data: []byte(jsonResponse),
-		}
+		jsonResponse := fmt.Sprintf(`{"data":%q, "role":"%s"}`, content, r.ParticipantName)
+		event := NewStreamEvent(StreamEventMessage, []byte(jsonResponse))
 
 		if err := stream.Publish(event); err != nil {
-			fmt.Printf("发送SSE事件失败: %v", err)
+			Logf(ctx, "发送SSE事件失败: %v", err)
 			return err
 		}
+
+		if exceeded {
+			truncated = true
+			break
+		}
 	}
 
+	return publishStreamDone(stream, map[string]interface{}{"role": r.ParticipantName, "truncated": truncated})
+}
+
+// publishStreamError 发送终止流的错误事件，客户端收到后应停止等待后续内容
+func publishStreamError(stream EventPublisher, message string) error {
+	data, _ := json.Marshal(map[string]interface{}{"error": message})
+	if err := stream.Publish(NewStreamEvent(StreamEventError, data)); err != nil {
+		fmt.Printf("发送SSE事件失败: %v", err)
+		return err
+	}
 	return nil
 }
 
-// EvaluateMeeting 使用LLM评估会议质量
-func EvaluateMeeting(ctx context.Context, documentText string) (*MeetingScore, error) {
-	// 从配置文件中获取API密钥和模型名称
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		return nil, fmt.Errorf("获取API密钥失败: %v", err)
+// publishStreamDone 发送流正常结束事件，extra 中的字段会一并返回
+func publishStreamDone(stream EventPublisher, extra map[string]interface{}) error {
+	payload := map[string]interface{}{"done": true}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	if err := stream.Publish(NewStreamEvent(StreamEventDone, data)); err != nil {
+		fmt.Printf("发送SSE事件失败: %v", err)
+		return err
 	}
+	return nil
+}
 
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		return nil, fmt.Errorf("获取模型名称失败: %v", err)
+// scoreFeedbackTemplates 各语言的评分反馈模板，占位符依次为三个指标的得分（或未评估标记）和理由、总体评价、总分、满分和得分百分比
+var scoreFeedbackTemplates = map[string]string{
+	LocaleZH: `## 会议评分详情
+
+### 会议目标达成度: %s
+%s
+
+### 主题聚焦度: %s
+%s
+
+### 参与者互动与参与度: %s
+%s
+
+### 总体评价
+%s
+
+**总分: %d/%d (%.1f%%)**
+`,
+	LocaleEN: `## Meeting Score Details
+
+### Meeting Goal Achievement: %s
+%s
+
+### Topic Focus: %s
+%s
+
+### Participant Engagement & Interaction: %s
+%s
+
+### Overall Feedback
+%s
+
+**Total: %d/%d (%.1f%%)**
+`,
+	LocaleJA: `## 会議評価の詳細
+
+### 会議目標の達成度: %s
+%s
+
+### テーマへの集中度: %s
+%s
+
+### 参加者の関与と交流: %s
+%s
+
+### 総合評価
+%s
+
+**合計: %d/%d (%.1f%%)**
+`,
+}
+
+// scoreNotEvaluatedLabels 各语言中模型没有给出得分的指标在反馈中的标记
+var scoreNotEvaluatedLabels = map[string]string{
+	LocaleZH: "未评估",
+	LocaleEN: "Not evaluated",
+	LocaleJA: "未評価",
+}
+
+// 会议评分的指标，顺序与反馈模板一致
+const (
+	CriterionGoalAchievement       = "goal_achievement"
+	CriterionTopicFocus            = "topic_focus"
+	CriterionParticipantEngagement = "participant_engagement"
+)
+
+// scoreCriteria 所有评分指标
+var scoreCriteria = []string{CriterionGoalAchievement, CriterionTopicFocus, CriterionParticipantEngagement}
+
+// maxCriterionScore 单个指标的满分
+const maxCriterionScore = 4
+
+// scoreCompleteInstruction 评估结果缺少指标得分时，重试时追加到系统提示后的要求，占位符为缺少的字段
+const scoreCompleteInstruction = `
+
+重要：上一次的评估结果缺少以下指标的得分：%s。请返回包含全部字段的完整JSON对象，每个指标的得分都必须是1到4之间的整数。`
+
+// scoreFeedbackTemplate 返回指定语言的评分反馈模板，不支持的语言使用中文模板
+func scoreFeedbackTemplate(locale string) string {
+	if tmpl, ok := scoreFeedbackTemplates[locale]; ok {
+		return tmpl
 	}
+	return scoreFeedbackTemplates[DefaultLocale]
+}
 
-	arkModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.2)), // 低温度以获得一致的评估结果
-	})
+// EvaluateMeeting 使用LLM评估会议质量，评价内容使用 ctx 中的回答语言。
+// 模型没有给出某个指标的得分（缺少字段或不是1到4之间的数字）时视为未评估而不是0分：
+// 先要求模型补全重试一次（配置 score.disable_incomplete_retry 后不重试），仍有缺少时返回
+// Partial 为 true 的部分评分，满分只计入已评估的指标；所有指标都未评估时返回错误。
+// meetingType 为会议类型，评分规则会追加该类型的评分侧重说明
+func EvaluateMeeting(ctx context.Context, documentText, meetingType string) (*MeetingScore, error) {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureScore, 0.2) // 低温度以获得一致的评估结果
 
 	if err != nil {
 		return nil, fmt.Errorf("创建LLM客户端失败: %v", err)
 	}
 
 	// 准备系统提示和用户提示
-	systemPrompt := `你是一个专业的会议评估专家。你需要根据以下评分规则对提供的会议文本进行全面客观的评估：
-
-核心指标一：会议目标达成度 (Meeting Goal Achievement) - 总分 /4
-4 分 (优秀): 会议完全实现了预定的目标，目标非常明确且可衡量，产出了清晰、可执行的成果和行动项，问题（如果会议目的是解决问题）得到了高效解决。
-3 分 (良好): 会议基本实现了预定的目标，目标比较明确，产出了较为具体的成果和行动项，问题（如果会议目的是解决问题）得到了较好解决。
-2 分 (一般): 会议部分实现了预定的目标，目标相对模糊，成果和行动项较为笼统，问题（如果会议目的是解决问题）得到了初步讨论，但解决程度有限。
-1 分 (较差): 会议未能有效实现预定的目标，目标不明确，缺乏有效成果和行动项，问题（如果会议目的是解决问题）未得到有效解决。
-
-核心指标二：主题聚焦度 (Topic Focus) - 总分 /4
-4 分 (优秀): 讨论完全聚焦于会议主题和议程，严格遵循议程，所有内容高度相关，时间利用非常高效，无跑题。
-3 分 (良好): 讨论基本聚焦于会议主题和议程，大部分遵循议程，内容基本相关，时间利用效率较高，偶有少量跑题但能及时拉回。
-2 分 (一般): 讨论部分偏离会议主题和议程，议程遵循度一般，部分内容关联性较弱，时间利用效率一般，跑题现象较为明显。
-1 分 (较差): 讨论严重偏离会议主题和议程，议程形同虚设，大量内容无关，时间利用效率极低，严重跑题。
-
-核心指标三：参与者互动与参与度 (Participant Engagement & Interaction) - 总分 /4
-4 分 (优秀): 绝大多数参与者都积极参与，互动频繁且深入，认真倾听并尊重他人，讨论氛围非常积极合作，充分体现集体智慧。
-3 分 (良好): 多数参与者都积极参与，互动较好，基本认真倾听并尊重他人，讨论氛围较为友好，参与度良好。
-2 分 (一般): 部分参与者参与，互动较少，倾听和尊重程度一般，讨论氛围有待改善，参与度一般，部分人沉默。
-1 分 (较差): 少数人主导，参与度极低，几乎没有互动，缺乏倾听和尊重，讨论氛围紧张或冷淡，如同单向汇报。
-
-必须严格按照以上评分标准，根据会议文本的内容和质量，为每个核心指标打分，并给出总体评价。你的评估必须客观、公正、详细，基于事实而非主观假设。
-你的回答必须包含每个指标的得分（1-4分）和详细理由，以及一个总体评价。
-
-以下是你必须返回的JSON格式（不要输出其他内容）：
-{
-  "goal_achievement": 分数,
-  "goal_achievement_feedback": "理由...",
-  "topic_focus": 分数,
-  "topic_focus_feedback": "理由...",
-  "participant_engagement": 分数,
-  "participant_engagement_feedback": "理由...",
-  "overall_feedback": "总体评价..."
-}`
-
-	// 准备消息
-	messages := []*schema.Message{
-		schema.SystemMessage(systemPrompt),
-		schema.UserMessage(documentText),
+	systemPrompt, err := RenderPrompt(PromptScore, PromptData{MeetingContent: documentText})
+	if err != nil {
+		return nil, err
 	}
+	systemPrompt = withMeetingTypeRubric(systemPrompt, meetingType)
 
-	// 生成回答
-	response, err := arkModel.Generate(ctx, messages)
+	evaluation, err := generateEvaluation(ctx, chatModel, systemPrompt, documentText)
 	if err != nil {
-		return nil, fmt.Errorf("评估会议失败: %v", err)
+		return nil, err
 	}
 
-	// 解析评估结果
-	var evaluation map[string]interface{}
-	if err := json.Unmarshal([]byte(response.Content), &evaluation); err != nil {
-		// 如果解析失败，尝试从文本中提取JSON部分
-		jsonStartIdx := strings.Index(response.Content, "{")
-		jsonEndIdx := strings.LastIndex(response.Content, "}")
-
-		if jsonStartIdx >= 0 && jsonEndIdx > jsonStartIdx {
-			jsonText := response.Content[jsonStartIdx : jsonEndIdx+1]
-			if err := json.Unmarshal([]byte(jsonText), &evaluation); err != nil {
-				return nil, fmt.Errorf("解析评估结果失败: %v", err)
-			}
-		} else {
-			return nil, fmt.Errorf("评估结果格式错误: %v", err)
+	return buildMeetingScore(ctx, chatModel, systemPrompt, documentText, evaluation)
+}
+
+// buildMeetingScore 根据模型的评估结果计算评分，有指标缺少得分时按配置要求模型补全重试一次
+func buildMeetingScore(ctx context.Context, chatModel LLM, systemPrompt, documentText string, evaluation map[string]interface{}) (*MeetingScore, error) {
+	scores, missing := parseCriterionScores(evaluation)
+	if len(missing) > 0 && !scoreRetryDisabled() {
+		Logf(ctx, "评估结果缺少指标 %v，要求补全后重试\n", missing)
+		retried, err := generateEvaluation(ctx, chatModel, systemPrompt+fmt.Sprintf(scoreCompleteInstruction, strings.Join(missing, ", ")), documentText)
+		if err != nil {
+			Logf(ctx, "补全评估结果失败，使用首次结果: %v\n", err)
+		} else if retriedScores, retriedMissing := parseCriterionScores(retried); len(retriedMissing) < len(missing) {
+			evaluation, scores, missing = retried, retriedScores, retriedMissing
 		}
 	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("评估结果中没有任何有效的指标得分")
+	}
 
-	// 提取评分
-	goalAchievement, _ := evaluation["goal_achievement"].(float64)
-	topicFocus, _ := evaluation["topic_focus"].(float64)
-	participantEngagement, _ := evaluation["participant_engagement"].(float64)
-
-	// 计算总分和百分比
-	totalScore := int(goalAchievement + topicFocus + participantEngagement)
-	maxPossibleScore := 12 // 3个指标，每个最高4分
+	// 计算总分和百分比，满分只计入已评估的指标
+	totalScore := 0
+	for _, score := range scores {
+		totalScore += score
+	}
+	maxPossibleScore := maxCriterionScore * len(scores)
 	scorePercentage := float64(totalScore) / float64(maxPossibleScore) * 100
 
 	// 构建反馈
+	locale := LocaleFromContext(ctx)
 	goalAchievementFeedback, _ := evaluation["goal_achievement_feedback"].(string)
 	topicFocusFeedback, _ := evaluation["topic_focus_feedback"].(string)
 	participantEngagementFeedback, _ := evaluation["participant_engagement_feedback"].(string)
 	overallFeedback, _ := evaluation["overall_feedback"].(string)
+	shortVerdict, _ := evaluation["short_verdict"].(string)
 
-	feedback := fmt.Sprintf(`## 会议评分详情
-
-### 会议目标达成度: %d/4
-%s
-
-### 主题聚焦度: %d/4
-%s
-
-### 参与者互动与参与度: %d/4
-%s
-
-### 总体评价
-%s
-
-**总分: %d/%d (%.1f%%)**
-`,
-		int(goalAchievement),
+	feedback := fmt.Sprintf(scoreFeedbackTemplate(locale),
+		criterionScoreText(scores, CriterionGoalAchievement, locale),
 		goalAchievementFeedback,
-		int(topicFocus),
+		criterionScoreText(scores, CriterionTopicFocus, locale),
 		topicFocusFeedback,
-		int(participantEngagement),
+		criterionScoreText(scores, CriterionParticipantEngagement, locale),
 		participantEngagementFeedback,
 		overallFeedback,
 		totalScore,
@@ -703,18 +895,78 @@ func EvaluateMeeting(ctx context.Context, documentText string) (*MeetingScore, e
 
 	// 构建评分结果
 	meetingScore := &MeetingScore{
-		GoalAchievement:       int(goalAchievement),
-		TopicFocus:            int(topicFocus),
-		ParticipantEngagement: int(participantEngagement),
+		GoalAchievement:       scores[CriterionGoalAchievement],
+		TopicFocus:            scores[CriterionTopicFocus],
+		ParticipantEngagement: scores[CriterionParticipantEngagement],
 		TotalScore:            totalScore,
 		MaxPossibleScore:      maxPossibleScore,
 		ScorePercentage:       scorePercentage,
+		Grade:                 ScoreGrade(scorePercentage),
+		ShortVerdict:          strings.TrimSpace(shortVerdict),
 		Feedback:              feedback,
+		Partial:               len(missing) > 0,
+		MissingCriteria:       missing,
 	}
 
 	return meetingScore, nil
 }
 
+// generateEvaluation 调用LLM生成一次评估结果并解析为JSON对象
+func generateEvaluation(ctx context.Context, chatModel LLM, systemPrompt, documentText string) (map[string]interface{}, error) {
+	// 准备消息
+	messages := []*schema.Message{
+		schema.SystemMessage(withLocaleInstruction(ctx, systemPrompt)),
+		schema.UserMessage(documentText),
+	}
+
+	// 生成回答
+	response, err := chatModel.Generate(ctx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("评估会议失败: %v", err)
+	}
+
+	// 解析评估结果
+	var evaluation map[string]interface{}
+	if err := parseJSONObject(response.Content, &evaluation); err != nil {
+		return nil, fmt.Errorf("解析评估结果失败: %v", err)
+	}
+	return evaluation, nil
+}
+
+// parseCriterionScores 读取各指标的得分，缺少字段或不是1到4之间数字的指标列入 missing
+func parseCriterionScores(evaluation map[string]interface{}) (map[string]int, []string) {
+	scores := make(map[string]int, len(scoreCriteria))
+	var missing []string
+	for _, criterion := range scoreCriteria {
+		value, ok := evaluation[criterion].(float64)
+		if !ok || value < 1 || value > maxCriterionScore {
+			missing = append(missing, criterion)
+			continue
+		}
+		scores[criterion] = int(math.Round(value))
+	}
+	return scores, missing
+}
+
+// criterionScoreText 返回反馈中指标的得分，未评估的指标显示对应语言的未评估标记
+func criterionScoreText(scores map[string]int, criterion, locale string) string {
+	score, ok := scores[criterion]
+	if !ok {
+		if label, ok := scoreNotEvaluatedLabels[locale]; ok {
+			return label
+		}
+		return scoreNotEvaluatedLabels[DefaultLocale]
+	}
+	return fmt.Sprintf("%d/%d", score, maxCriterionScore)
+}
+
+// scoreRetryDisabled 是否关闭评估结果缺少指标时的补全重试
+func scoreRetryDisabled() bool {
+	cfg, err := LoadConfig()
+	return err == nil && cfg.Score.DisableIncompleteRetry
+}
+
 // GetFeiShuWebhookURL 从配置中获取飞书Webhook URL
 func GetFeiShuWebhookURL() (string, error) {
 	cfg, err := LoadConfig()
@@ -734,25 +986,10 @@ func GetFeiShuWebhookURL() (string, error) {
 
 // CreateMeetingReport 从会议ID创建会议报告
 func CreateMeetingReport(meetingID string) (*MeetingReport, error) {
-	// 读取对应会议文件内容
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
-
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("会议不存在")
-	}
-
 	// 读取会议文件
-	data, err := os.ReadFile(filePath)
+	meetingData, err := LoadMeeting(meetingID)
 	if err != nil {
-		return nil, fmt.Errorf("无法读取会议信息: %v", err)
-	}
-
-	// 解析JSON内容
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		return nil, fmt.Errorf("无法解析会议数据: %v", err)
+		return nil, err
 	}
 
 	// 创建会议报告
@@ -765,52 +1002,25 @@ func CreateMeetingReport(meetingID string) (*MeetingReport, error) {
 	}
 
 	// 从metadata中提取信息
-	if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-		// 提取标题
-		if title, ok := metadata["title"].(string); ok && title != "" {
-			report.Title = title
-		}
-
-		// 提取描述
-		if description, ok := metadata["description"].(string); ok && description != "" {
-			report.Description = description
-		}
-
-		// 提取摘要
-		if summary, ok := metadata["summary"].(string); ok && summary != "" {
-			report.Summary = summary
-		}
-
-		// 提取参会人员
-		if participants, ok := metadata["participants"].([]interface{}); ok && len(participants) > 0 {
-			for _, p := range participants {
-				if pStr, ok := p.(string); ok && pStr != "" {
-					report.Participants = append(report.Participants, pStr)
-				}
-			}
-		}
-
-		// 提取待办事项
-		if todoList, ok := metadata["todo_list"].([]interface{}); ok && len(todoList) > 0 {
-			for _, todo := range todoList {
-				if todoStr, ok := todo.(string); ok && todoStr != "" {
-					report.TodoList = append(report.TodoList, todoStr)
-				}
-			}
-		}
+	metadata, _ := GetMeetingMetadata(meetingData)
+	if metadata.Title != "" {
+		report.Title = metadata.Title
 	}
+	report.Description = metadata.Description
+	report.Summary = metadata.Summary
+	report.Participants = append(report.Participants, metadata.Participants...)
+	report.TodoList = append(report.TodoList, metadata.TodoItems()...)
+	// 按开始和结束时间实时计算时长，早于时长功能创建的会议同样适用
+	report.StartTime = metadata.StartTime
+	report.EndTime = metadata.EndTime
+	report.DurationMinutes = metadata.Timing().DurationMinutes
+	report.Duration = FormatDuration(report.DurationMinutes)
 
 	return report, nil
 }
 
-// SendMeetingReportToFeiShu 发送会议报告到飞书
-func SendMeetingReportToFeiShu(report *MeetingReport) error {
-	// 获取飞书Webhook URL
-	webhookURL, err := GetFeiShuWebhookURL()
-	if err != nil {
-		return fmt.Errorf("获取飞书Webhook URL失败: %v", err)
-	}
-
+// BuildFeiShuReportMessage 将会议报告构建为飞书交互式卡片消息，推送和预览共用
+func BuildFeiShuReportMessage(report *MeetingReport) FeiShuMessage {
 	// 构建飞书消息
 	message := FeiShuMessage{
 		MsgType: "interactive",
@@ -820,66 +1030,79 @@ func SendMeetingReportToFeiShu(report *MeetingReport) error {
 					Content: report.Title,
 					Tag:     "plain_text",
 				},
-				Template: "blue", // 可以根据需要更改颜色
+				Template: DefaultFeiShuCardColor,
 			},
 			Elements: []Element{},
 		},
 	}
 
-	// 添加会议描述
-	if report.Description != "" {
-		message.Card.Elements = append(message.Card.Elements, Element{
-			Tag: "div",
-			Text: &Text{
-				Content: "**会议描述：**\n" + report.Description,
-				Tag:     "lark_md",
-			},
-		})
+	if report.CardColor != "" {
+		message.Card.Header.Template = report.CardColor
 	}
 
-	// 添加会议摘要
-	if report.Summary != "" {
-		message.Card.Elements = append(message.Card.Elements, Element{
-			Tag: "div",
-			Text: &Text{
-				Content: "**会议摘要：**\n" + report.Summary,
-				Tag:     "lark_md",
-			},
-		})
+	// 按报告模板依次添加区块，内容为空的区块跳过
+	for _, section := range GetReportSections() {
+		if element, ok := feiShuSectionElement(report, section); ok {
+			message.Card.Elements = append(message.Card.Elements, element)
+		}
 	}
 
-	// 添加分割线
-	message.Card.Elements = append(message.Card.Elements, Element{
-		Tag: "hr",
-	})
+	return message
+}
 
-	// 添加参会人员
-	if len(report.Participants) > 0 {
-		participantsText := "**参会人员：**\n" + strings.Join(report.Participants, "、")
-		message.Card.Elements = append(message.Card.Elements, Element{
-			Tag: "div",
-			Text: &Text{
-				Content: participantsText,
-				Tag:     "lark_md",
-			},
-		})
+// feiShuSectionElement 将报告模板中的区块转换为飞书卡片元素，区块内容为空时返回 false。
+// 会议评分的各项得分以并排字段展示
+func feiShuSectionElement(report *MeetingReport, section ReportSection) (Element, bool) {
+	if section.Type == ReportSectionDivider {
+		return Element{Tag: "hr"}, true
+	}
+
+	text := reportSectionText(report, section.Type)
+	if text == "" {
+		return Element{}, false
 	}
 
-	// 添加待办事项
-	if len(report.TodoList) > 0 {
-		todoListText := "**待办事项：**\n"
-		for i, todo := range report.TodoList {
-			todoListText += fmt.Sprintf("%d. %s\n", i+1, todo)
+	if section.Type == ReportSectionScore {
+		lines := reportScoreLines(report.Score)
+		fields := make([]Field, 0, len(lines))
+		for i, line := range lines {
+			fields = append(fields, Field{
+				IsShort: i > 0,
+				Text: Text{
+					Content: line,
+					Tag:     "lark_md",
+				},
+			})
 		}
-		message.Card.Elements = append(message.Card.Elements, Element{
+		return Element{
 			Tag: "div",
 			Text: &Text{
-				Content: todoListText,
+				Content: fmt.Sprintf("**%s：**", section.Title),
 				Tag:     "lark_md",
 			},
-		})
+			Fields: fields,
+		}, true
 	}
 
+	return Element{
+		Tag: "div",
+		Text: &Text{
+			Content: fmt.Sprintf("**%s：**\n%s", section.Title, text),
+			Tag:     "lark_md",
+		},
+	}, true
+}
+
+// SendMeetingReportToFeiShu 发送会议报告到飞书
+func SendMeetingReportToFeiShu(report *MeetingReport) error {
+	// 获取飞书Webhook URL
+	webhookURL, err := GetFeiShuWebhookURL()
+	if err != nil {
+		return fmt.Errorf("获取飞书Webhook URL失败: %v", err)
+	}
+
+	message := BuildFeiShuReportMessage(report)
+
 	// 将消息转换为JSON
 	messageJSON, err := json.Marshal(message)
 	if err != nil {
@@ -917,3 +1140,14 @@ func PushMeetingReportToFeiShu(meetingID string) error {
 
 	return nil
 }
+
+// GetMeetingRawContent 返回会议的原始内容（启用字段加密时已解密），会议没有原始内容时返回空字符串，
+// 会议不存在时返回 ErrMeetingNotFound
+func GetMeetingRawContent(meetingID string) (string, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return "", err
+	}
+	rawContent, _ := meetingData["raw_content"].(string)
+	return rawContent, nil
+}
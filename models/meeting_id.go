@@ -0,0 +1,48 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultIdempotencyTTL 创建会议的幂等键默认保留时长
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// NewMeetingID 生成会议ID，格式为 meeting_<时间戳>_<随机后缀>，同一秒内创建的会议也不会冲突
+func NewMeetingID() string {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		// 随机数不可用时退化为纳秒，仍能避免同一秒内的冲突
+		now := time.Now()
+		return fmt.Sprintf("meeting_%s_%09d", now.Format(meetingIDTimeLayout), now.Nanosecond())
+	}
+	return "meeting_" + time.Now().Format(meetingIDTimeLayout) + "_" + hex.EncodeToString(suffix)
+}
+
+// GetIdempotencyTTL 获取创建会议的幂等键保留时长，未配置时默认24小时
+func GetIdempotencyTTL() time.Duration {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.Meeting.IdempotencyTTLHours <= 0 {
+		return defaultIdempotencyTTL
+	}
+	return time.Duration(cfg.Meeting.IdempotencyTTLHours) * time.Hour
+}
+
+// meetingIDTimeLayout 会议ID中时间戳的格式
+const meetingIDTimeLayout = "20060102150405"
+
+// MeetingCreatedAt 从会议ID中解析创建时间（服务器本地时区），ID格式不符时返回 false
+func MeetingCreatedAt(meetingID string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(meetingID, "meeting_")
+	if !ok || len(rest) < len(meetingIDTimeLayout) {
+		return time.Time{}, false
+	}
+	createdAt, err := time.ParseInLocation(meetingIDTimeLayout, rest[:len(meetingIDTimeLayout)], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
@@ -0,0 +1,39 @@
+package models
+
+// 会议闭环状态，记录在会议 metadata 的 status 字段中
+const (
+	MeetingStatusOpen   = "open"   // 仍有未完成的待办
+	MeetingStatusClosed = "closed" // 关联的待办全部完成
+	MeetingStatusNA     = "n/a"    // 会议没有关联待办
+)
+
+// GetNoTodoMeetingStatus 获取没有关联待办的会议状态，可配置为 closed 或 n/a，默认 closed
+func GetNoTodoMeetingStatus() string {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.Meeting.NoTodoStatus != MeetingStatusNA {
+		return MeetingStatusClosed
+	}
+	return MeetingStatusNA
+}
+
+// ComputeMeetingStatus 根据会议关联待办的总数和已完成数计算会议闭环状态
+func ComputeMeetingStatus(total, completed int) string {
+	if total == 0 {
+		return GetNoTodoMeetingStatus()
+	}
+	if completed >= total {
+		return MeetingStatusClosed
+	}
+	return MeetingStatusOpen
+}
+
+// SetMeetingStatus 更新会议 metadata 中的闭环状态，状态未变化时不写文件
+func SetMeetingStatus(meetingID, status string) error {
+	return UpdateMeetingMetadata(meetingID, func(metadata map[string]interface{}) bool {
+		if current, _ := metadata["status"].(string); current == status {
+			return false
+		}
+		metadata["status"] = status
+		return true
+	})
+}
@@ -0,0 +1,423 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/hertz-contrib/sse"
+)
+
+// scriptedLLM 按顺序返回预设输出的模型，调用次数超过预设输出数时重复最后一个输出
+type scriptedLLM struct {
+	mu      sync.Mutex
+	outputs []string
+	calls   int
+	inputs  [][]*schema.Message
+}
+
+func (m *scriptedLLM) next(input []*schema.Message) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	output := m.outputs[min(m.calls, len(m.outputs)-1)]
+	m.calls++
+	return output
+}
+
+func (m *scriptedLLM) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return schema.AssistantMessage(m.next(input), nil), nil
+}
+
+func (m *scriptedLLM) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage(m.next(input), nil)}), nil
+}
+
+// callCount 返回模型被调用的次数
+func (m *scriptedLLM) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+// withModel 返回注入了指定模型的 context，所有功能和温度都使用该模型
+func withModel(llm LLM) context.Context {
+	factory := NewModelFactory(func(ctx context.Context, spec ModelSpec) (LLM, error) {
+		return llm, nil
+	})
+	return WithModelFactory(context.Background(), factory)
+}
+
+// withScriptedModel 返回注入了按顺序返回 outputs 的模型的 context
+func withScriptedModel(outputs ...string) (context.Context, *scriptedLLM) {
+	llm := &scriptedLLM{outputs: outputs}
+	return withModel(llm), llm
+}
+
+// recordingPublisher 记录发布的流式事件
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []*sse.Event
+}
+
+func (p *recordingPublisher) Publish(event *sse.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+// data 返回已发布事件的数据
+func (p *recordingPublisher) data() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	data := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		data = append(data, string(event.Data))
+	}
+	return data
+}
+
+const testMeetingInfoJSON = `{
+  "title": "预算评审会",
+  "summary": "讨论了下季度预算，确定由张三整理预算表",
+  "participants": ["张三", "李四"],
+  "start_time": "",
+  "end_time": "",
+  "todo_list": [{"task": "整理预算表", "assignee": "张三", "due_date": ""}]
+}`
+
+func TestExtractMeetingInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		outputs   []string
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "well-formed JSON",
+			outputs:   []string{testMeetingInfoJSON},
+			wantCalls: 1,
+		},
+		{
+			name:      "JSON wrapped in prose",
+			outputs:   []string{"好的，以下是抽取结果：\n```json\n" + testMeetingInfoJSON + "\n```\n如需调整请告诉我。"},
+			wantCalls: 1,
+		},
+		{
+			name:      "malformed output",
+			outputs:   []string{`{"title": "预算评审会", "summary": "讨论了预算"`},
+			wantErr:   ErrExtractionFailed,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, llm := withScriptedModel(tt.outputs...)
+			metadata, err := ExtractMeetingInfo(ctx, "张三: 我们看一下预算\n李四: 好的", "")
+
+			if got := llm.callCount(); got != tt.wantCalls {
+				t.Errorf("模型调用次数 = %d，期望 %d", got, tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("错误 = %v，期望 %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("抽取失败: %v", err)
+			}
+			if metadata.Title != "预算评审会" {
+				t.Errorf("Title = %q", metadata.Title)
+			}
+			if strings.Join(metadata.Participants, ",") != "张三,李四" {
+				t.Errorf("Participants = %v", metadata.Participants)
+			}
+			if len(metadata.TodoList) != 1 || metadata.TodoList[0].Assignee != "张三" {
+				t.Errorf("TodoList = %+v", metadata.TodoList)
+			}
+			if metadata.MeetingType != MeetingTypeOther || metadata.MeetingTypeSource != MeetingTypeSourceModel {
+				t.Errorf("MeetingType = %q, MeetingTypeSource = %q", metadata.MeetingType, metadata.MeetingTypeSource)
+			}
+		})
+	}
+}
+
+func TestExtractMermaid(t *testing.T) {
+	const diagram = "'''mermaid\nflowchart TD\n    A[开始] --> B{是否通过预算}\n    B -->|是| C[执行]\n'''"
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "well-formed code block",
+			output: diagram,
+			want:   diagram,
+		},
+		{
+			name:   "code block wrapped in prose",
+			output: "会议流程如下：\n" + diagram + "\n以上流程图仅供参考。",
+			want:   diagram,
+		},
+		{
+			// 没有代码块时原样返回模型输出
+			name:   "malformed output",
+			output: "flowchart TD A[开始] --> B[结束]",
+			want:   "flowchart TD A[开始] --> B[结束]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := withScriptedModel(tt.output)
+			got, err := ExtractMermaid(ctx, "张三: 我们看一下预算")
+			if err != nil {
+				t.Fatalf("生成流程图失败: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractMermaid() = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+const testEvaluationJSON = `{
+  "goal_achievement": 4,
+  "goal_achievement_feedback": "目标明确",
+  "topic_focus": 3,
+  "topic_focus_feedback": "基本聚焦",
+  "participant_engagement": 2,
+  "participant_engagement_feedback": "李四发言较少",
+  "overall_feedback": "整体良好",
+  "short_verdict": "目标达成，参与度有待提高"
+}`
+
+func TestEvaluateMeeting(t *testing.T) {
+	tests := []struct {
+		name        string
+		outputs     []string
+		wantErr     bool
+		wantTotal   int
+		wantMax     int
+		wantMissing []string
+		wantCalls   int
+	}{
+		{
+			name:      "well-formed JSON",
+			outputs:   []string{testEvaluationJSON},
+			wantTotal: 9,
+			wantMax:   12,
+			wantCalls: 1,
+		},
+		{
+			name:      "JSON wrapped in prose",
+			outputs:   []string{"评估结果如下：\n```json\n" + testEvaluationJSON + "\n```\n以上评估基于会议记录。"},
+			wantTotal: 9,
+			wantMax:   12,
+			wantCalls: 1,
+		},
+		{
+			name:      "malformed output",
+			outputs:   []string{"目标达成度 4 分，主题聚焦度 3 分"},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			// 缺少指标时重试一次，仍然缺少时返回部分评分
+			name:        "missing criterion falls back to partial score",
+			outputs:     []string{`{"goal_achievement": 4, "topic_focus": 3, "short_verdict": "部分评分"}`},
+			wantTotal:   7,
+			wantMax:     8,
+			wantMissing: []string{CriterionParticipantEngagement},
+			wantCalls:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, llm := withScriptedModel(tt.outputs...)
+			score, err := EvaluateMeeting(ctx, "张三: 我们看一下预算\n李四: 好的", "")
+
+			if got := llm.callCount(); got != tt.wantCalls {
+				t.Errorf("模型调用次数 = %d，期望 %d", got, tt.wantCalls)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望返回错误，得到评分 %+v", score)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("评分失败: %v", err)
+			}
+			if score.TotalScore != tt.wantTotal || score.MaxPossibleScore != tt.wantMax {
+				t.Errorf("得分 = %d/%d，期望 %d/%d", score.TotalScore, score.MaxPossibleScore, tt.wantTotal, tt.wantMax)
+			}
+			if score.Partial != (len(tt.wantMissing) > 0) || strings.Join(score.MissingCriteria, ",") != strings.Join(tt.wantMissing, ",") {
+				t.Errorf("Partial = %v, MissingCriteria = %v，期望缺少 %v", score.Partial, score.MissingCriteria, tt.wantMissing)
+			}
+			if score.ShortVerdict == "" {
+				t.Error("ShortVerdict 为空")
+			}
+		})
+	}
+}
+
+func TestExtractMeetingInfoRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []string
+		wantErr bool
+	}{
+		{
+			name:    "empty response then JSON",
+			outputs: []string{"", testMeetingInfoJSON},
+		},
+		{
+			name:    "refusal then JSON",
+			outputs: []string{"I can't do that.", testMeetingInfoJSON},
+		},
+		{
+			name:    "empty response twice",
+			outputs: []string{"   "},
+			wantErr: true,
+		},
+		{
+			name:    "refusal twice",
+			outputs: []string{"抱歉，我无法处理这个请求。"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, llm := withScriptedModel(tt.outputs...)
+			metadata, err := ExtractMeetingInfo(ctx, "张三: 我们看一下预算\n李四: 好的", "")
+
+			// 首次结果不可用时只重试一次，重试时要求只输出JSON
+			if got := llm.callCount(); got != 2 {
+				t.Fatalf("模型调用次数 = %d，期望 2", got)
+			}
+			if retryPrompt := llm.inputs[1][0].Content; !strings.HasSuffix(retryPrompt, extractJSONOnlyInstruction) {
+				t.Errorf("重试的系统提示没有追加只输出JSON的要求: %q", truncateRunes(retryPrompt, 50))
+			}
+
+			if tt.wantErr {
+				// 不能把拒绝内容当作摘要保存为"未知会议"
+				if !errors.Is(err, ErrExtractionFailed) {
+					t.Fatalf("错误 = %v，期望 ErrExtractionFailed，得到 %+v", err, metadata)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("重试后抽取失败: %v", err)
+			}
+			if metadata.Title != "预算评审会" {
+				t.Errorf("Title = %q", metadata.Title)
+			}
+		})
+	}
+}
+
+// cancelingPublisher 发布 cancelAfter 个事件后取消 context，模拟客户端中途断开
+type cancelingPublisher struct {
+	recordingPublisher
+	cancel      context.CancelFunc
+	cancelAfter int
+}
+
+func (p *cancelingPublisher) Publish(event *sse.Event) error {
+	p.recordingPublisher.Publish(event)
+	if len(p.data()) == p.cancelAfter {
+		p.cancel()
+	}
+	return nil
+}
+
+func TestChatProcessStopsWhenCanceled(t *testing.T) {
+	const meetingContent = "张三: 预算需要在周五前确定\n李四: 好的"
+	const query = "会议的结论是什么？"
+	mock := newMockChatModel(ModelSpec{})
+
+	// 未取消时模拟模型的回答分为多个片段，之后是结束事件
+	complete := &recordingPublisher{}
+	if err := (ChatMessage{Data: meetingContent}).Process(withModel(mock), query, complete, "meeting_cancel_test", "session_complete"); err != nil {
+		t.Fatalf("聊天失败: %v", err)
+	}
+	if len(complete.data()) < 3 {
+		t.Fatalf("模拟回答只有 %d 个事件，无法验证中途取消", len(complete.data()))
+	}
+
+	// 第一个片段之后取消
+	ctx, cancel := context.WithCancel(withModel(mock))
+	defer cancel()
+	publisher := &cancelingPublisher{cancel: cancel, cancelAfter: 1}
+	err := (ChatMessage{Data: meetingContent}).Process(ctx, query, publisher, "meeting_cancel_test", "session_canceled")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("错误 = %v，期望 context.Canceled", err)
+	}
+
+	// 取消后不再发送回答片段和结束事件
+	if got := publisher.data(); len(got) != 1 || got[0] != complete.data()[0] {
+		t.Errorf("取消后发送的事件 = %q，期望只有第一个片段 %q", got, complete.data()[0])
+	}
+
+	// 未完成的回答不计入聊天历史
+	for _, item := range getChatHistory("meeting_cancel_test", "session_canceled").Items {
+		if item.Role == "assistant" {
+			t.Errorf("取消的回答被写入聊天历史: %q", item.Content)
+		}
+	}
+}
+
+// optionsLLM 记录每次调用时传入的模型选项
+type optionsLLM struct {
+	scriptedLLM
+	options []*model.Options
+}
+
+func (m *optionsLLM) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.mu.Lock()
+	m.options = append(m.options, model.GetCommonOptions(&model.Options{}, opts...))
+	m.mu.Unlock()
+	return m.scriptedLLM.Generate(ctx, input, opts...)
+}
+
+func TestGetPersonaChatModelPassesTemperaturePerCall(t *testing.T) {
+	llm := &optionsLLM{scriptedLLM: scriptedLLM{outputs: []string{"好的"}}}
+	created := 0
+	factory := NewModelFactory(func(ctx context.Context, spec ModelSpec) (LLM, error) {
+		created++
+		return llm, nil
+	})
+	ctx := WithModelFactory(context.Background(), factory)
+
+	temperatures := []float32{0.1, 0.25, 0.333, 0.9}
+	for _, temperature := range temperatures {
+		chatModel, err := GetPersonaChatModel(ctx, &temperature)
+		if err != nil {
+			t.Fatalf("获取角色扮演模型失败: %v", err)
+		}
+		if _, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("你好")}); err != nil {
+			t.Fatalf("调用模型失败: %v", err)
+		}
+	}
+
+	// 不同温度共享同一个缓存的模型，温度在每次调用时传入
+	if created != 1 {
+		t.Errorf("创建了 %d 个模型，期望不同温度共享 1 个", created)
+	}
+	for i, options := range llm.options {
+		if options.Temperature == nil || *options.Temperature != temperatures[i] {
+			t.Errorf("第 %d 次调用的温度 = %v，期望 %v", i+1, options.Temperature, temperatures[i])
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxMeetingDuration 超过该时长的会议视为时间可能有误，保留时长但给出警告
+const maxMeetingDuration = 24 * time.Hour
+
+// 会议时间的常见格式，模型抽取的时间为自由文本，按顺序尝试
+var (
+	// meetingDateTimeLayouts 同时包含日期和时间的格式
+	meetingDateTimeLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006/01/02 15:04:05",
+		"2006/01/02 15:04",
+		"2006.01.02 15:04",
+		"2006年1月2日 15:04:05",
+		"2006年1月2日 15:04",
+		"2006年1月2日15:04",
+		"2006年1月2日 15点04分",
+		"2006年1月2日15点04分",
+		"2006年1月2日 15点",
+		"2006年1月2日15点",
+		"01/02/2006 15:04",
+		"Jan 2, 2006 15:04",
+		"Jan 2, 2006 3:04 PM",
+		"2 Jan 2006 15:04",
+	}
+	// meetingDateLayouts 只有日期的格式
+	meetingDateLayouts = []string{
+		"2006-01-02",
+		"2006/01/02",
+		"2006.01.02",
+		"2006年1月2日",
+		"01/02/2006",
+		"Jan 2, 2006",
+		"2 Jan 2006",
+	}
+	// meetingClockLayouts 只有时间的格式，与会议的另一个时间组合出日期
+	meetingClockLayouts = []string{
+		"15:04:05",
+		"15:04",
+		"15点04分",
+		"15点",
+		"15时04分",
+		"3:04PM",
+		"3:04 PM",
+		"3PM",
+		"3 PM",
+	}
+)
+
+// meetingTimeValue 解析后的会议时间，hasDate 和 hasClock 表示原文是否包含日期和时间
+type meetingTimeValue struct {
+	t        time.Time
+	hasDate  bool
+	hasClock bool
+}
+
+// MeetingTiming 由开始时间和结束时间计算出的会议时间信息，无法解析的时间记录在 Warnings 中
+type MeetingTiming struct {
+	StartAt         string   // 标准化后的开始时间，包含时间时为 RFC3339，只有日期时为 YYYY-MM-DD，无法确定时为空
+	EndAt           string   // 标准化后的结束时间，格式同 StartAt
+	DurationMinutes int      // 会议时长（分钟），开始或结束时间缺少时刻、无法解析或结束早于开始时为0
+	Warnings        []string // 时间无法解析或不合理时的说明
+}
+
+// parseMeetingTime 按常见格式解析会议时间，支持"上午"、"下午"等中文时段前缀，无法解析时返回 false
+func parseMeetingTime(value string) (meetingTimeValue, bool) {
+	value = strings.Join(strings.Fields(strings.ReplaceAll(value, "：", ":")), " ")
+	if value == "" {
+		return meetingTimeValue{}, false
+	}
+
+	// 中文时段前缀：下午、晚上12点以前的时间加12小时，中午的1点、2点等同样换算为下午
+	pmBefore := 0
+	for marker, hour := range map[string]int{"上午": 0, "早上": 0, "中午": 11, "下午": 12, "晚上": 12} {
+		if strings.Contains(value, marker) {
+			pmBefore = hour
+			value = strings.Join(strings.Fields(strings.Replace(value, marker, " ", 1)), " ")
+			break
+		}
+	}
+	adjust := func(t time.Time) time.Time {
+		if t.Hour() < pmBefore {
+			return t.Add(12 * time.Hour)
+		}
+		return t
+	}
+
+	for _, layout := range meetingDateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return meetingTimeValue{t: adjust(t), hasDate: true, hasClock: true}, true
+		}
+	}
+	for _, layout := range meetingDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return meetingTimeValue{t: t, hasDate: true}, true
+		}
+	}
+	for _, layout := range meetingClockLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return meetingTimeValue{t: adjust(t), hasClock: true}, true
+		}
+	}
+	return meetingTimeValue{}, false
+}
+
+// withDate 将只有时刻的时间放到 date 所在的日期
+func (v meetingTimeValue) withDate(date time.Time) meetingTimeValue {
+	v.t = time.Date(date.Year(), date.Month(), date.Day(), v.t.Hour(), v.t.Minute(), v.t.Second(), 0, date.Location())
+	v.hasDate = true
+	return v
+}
+
+// format 返回标准化的时间文本，缺少日期时返回空字符串
+func (v meetingTimeValue) format() string {
+	switch {
+	case !v.hasDate:
+		return ""
+	case !v.hasClock:
+		return v.t.Format("2006-01-02")
+	default:
+		return v.t.Format(time.RFC3339)
+	}
+}
+
+// ComputeMeetingTiming 解析会议的开始和结束时间并计算时长。只有时刻（如 "15:30"）的一方使用另一方的日期；
+// 时间为空时不计算也不警告，无法解析、结束早于开始或时长超过24小时时在 Warnings 中说明
+func ComputeMeetingTiming(startTime, endTime string) MeetingTiming {
+	timing := MeetingTiming{}
+
+	start, startOK := parseMeetingTime(startTime)
+	if strings.TrimSpace(startTime) != "" && !startOK {
+		timing.Warnings = append(timing.Warnings, fmt.Sprintf("无法解析开始时间: %s", startTime))
+	}
+	end, endOK := parseMeetingTime(endTime)
+	if strings.TrimSpace(endTime) != "" && !endOK {
+		timing.Warnings = append(timing.Warnings, fmt.Sprintf("无法解析结束时间: %s", endTime))
+	}
+
+	if startOK && endOK {
+		if start.hasDate && !end.hasDate {
+			end = end.withDate(start.t)
+		} else if end.hasDate && !start.hasDate {
+			start = start.withDate(end.t)
+		}
+	}
+	if startOK {
+		timing.StartAt = start.format()
+	}
+	if endOK {
+		timing.EndAt = end.format()
+	}
+
+	if !startOK || !endOK || !start.hasClock || !end.hasClock {
+		return timing
+	}
+	duration := end.t.Sub(start.t)
+	if duration < 0 {
+		timing.Warnings = append(timing.Warnings, fmt.Sprintf("结束时间 %s 早于开始时间 %s", endTime, startTime))
+		return timing
+	}
+	if duration > maxMeetingDuration {
+		timing.Warnings = append(timing.Warnings, fmt.Sprintf("会议时长超过24小时，请检查开始时间 %s 和结束时间 %s", startTime, endTime))
+	}
+	timing.DurationMinutes = int(duration.Round(time.Minute) / time.Minute)
+	return timing
+}
+
+// Timing 按元数据中的开始和结束时间计算会议时间信息
+func (m MeetingMetadata) Timing() MeetingTiming {
+	return ComputeMeetingTiming(m.StartTime, m.EndTime)
+}
+
+// writeTo 将时间信息写入会议元数据，总是写入以覆盖修改时间前的计算结果
+func (t MeetingTiming) writeTo(metadata map[string]interface{}) {
+	warnings := make([]interface{}, 0, len(t.Warnings))
+	for _, warning := range t.Warnings {
+		warnings = append(warnings, warning)
+	}
+	metadata["start_at"] = t.StartAt
+	metadata["end_at"] = t.EndAt
+	metadata["duration_minutes"] = t.DurationMinutes
+	metadata["time_warnings"] = warnings
+}
+
+// FormatDuration 将分钟数格式化为"1小时30分钟"，不大于0时返回空字符串
+func FormatDuration(minutes int) string {
+	if minutes <= 0 {
+		return ""
+	}
+	hours, rest := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d分钟", rest)
+	case rest == 0:
+		return fmt.Sprintf("%d小时", hours)
+	default:
+		return fmt.Sprintf("%d小时%d分钟", hours, rest)
+	}
+}
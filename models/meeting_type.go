@@ -0,0 +1,303 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 会议类型，由抽取时的模型分类或创建会议时的 type 参数确定
+const (
+	MeetingTypeStandup        = "standup"         // 站会
+	MeetingTypeRetro          = "retro"           // 复盘会
+	MeetingTypePlanning       = "planning"        // 计划会
+	MeetingTypeOneOnOne       = "one_on_one"      // 一对一
+	MeetingTypeDecisionReview = "decision_review" // 决策评审
+	MeetingTypeOther          = "other"           // 其他
+)
+
+// 会议类型的来源
+const (
+	MeetingTypeSourceModel = "model" // 抽取时由模型分类
+	MeetingTypeSourceUser  = "user"  // 用户创建或修改会议时指定，重新抽取时保留
+)
+
+// MeetingTypes 所有会议类型，按展示顺序排列
+var MeetingTypes = []string{
+	MeetingTypeStandup,
+	MeetingTypeRetro,
+	MeetingTypePlanning,
+	MeetingTypeOneOnOne,
+	MeetingTypeDecisionReview,
+	MeetingTypeOther,
+}
+
+// ErrInvalidMeetingType 不支持的会议类型
+var ErrInvalidMeetingType = errors.New("无效的会议类型")
+
+// meetingTypeAliases 会议类型的常见别名，解析时忽略大小写、空格和连字符
+var meetingTypeAliases = map[string]string{
+	"daily":          MeetingTypeStandup,
+	"dailystandup":   MeetingTypeStandup,
+	"站会":             MeetingTypeStandup,
+	"晨会":             MeetingTypeStandup,
+	"retrospective":  MeetingTypeRetro,
+	"复盘":             MeetingTypeRetro,
+	"复盘会":            MeetingTypeRetro,
+	"回顾会":            MeetingTypeRetro,
+	"plan":           MeetingTypePlanning,
+	"计划会":            MeetingTypePlanning,
+	"规划会":            MeetingTypePlanning,
+	"1:1":            MeetingTypeOneOnOne,
+	"1on1":           MeetingTypeOneOnOne,
+	"oneonone":       MeetingTypeOneOnOne,
+	"一对一":            MeetingTypeOneOnOne,
+	"decision":       MeetingTypeDecisionReview,
+	"decisionreview": MeetingTypeDecisionReview,
+	"review":         MeetingTypeDecisionReview,
+	"决策评审":           MeetingTypeDecisionReview,
+	"评审会":            MeetingTypeDecisionReview,
+	"其他":             MeetingTypeOther,
+}
+
+// meetingTypeLabels 各语言的会议类型名称
+var meetingTypeLabels = map[string]map[string]string{
+	LocaleZH: {
+		MeetingTypeStandup:        "站会",
+		MeetingTypeRetro:          "复盘会",
+		MeetingTypePlanning:       "计划会",
+		MeetingTypeOneOnOne:       "一对一",
+		MeetingTypeDecisionReview: "决策评审",
+		MeetingTypeOther:          "其他",
+	},
+	LocaleEN: {
+		MeetingTypeStandup:        "Standup",
+		MeetingTypeRetro:          "Retrospective",
+		MeetingTypePlanning:       "Planning",
+		MeetingTypeOneOnOne:       "1:1",
+		MeetingTypeDecisionReview: "Decision Review",
+		MeetingTypeOther:          "Other",
+	},
+	LocaleJA: {
+		MeetingTypeStandup:        "朝会",
+		MeetingTypeRetro:          "振り返り",
+		MeetingTypePlanning:       "計画会議",
+		MeetingTypeOneOnOne:       "1on1",
+		MeetingTypeDecisionReview: "意思決定レビュー",
+		MeetingTypeOther:          "その他",
+	},
+}
+
+// meetingTypeProfile 不同会议类型的摘要长度和评分侧重点
+type meetingTypeProfile struct {
+	SummaryChars int    // 抽取摘要的最大字数
+	ScoreRubric  string // 追加到评分提示后的评分侧重说明
+}
+
+// meetingTypeProfiles 各会议类型的摘要和评分设置，未分类的会议使用 other
+var meetingTypeProfiles = map[string]meetingTypeProfile{
+	MeetingTypeStandup: {
+		SummaryChars: 60,
+		ScoreRubric: `本次会议是站会，评分时请按以下侧重理解各项指标：
+- 会议目标达成度：每人是否同步了进展、计划和阻碍，阻碍是否有人跟进，而不是要求产出决议
+- 主题聚焦度：是否简短高效，深入讨论是否被移到会后，超时或展开技术细节应扣分
+- 参与者互动与参与度：是否每位成员都做了同步，不要求深入讨论`,
+	},
+	MeetingTypeRetro: {
+		SummaryChars: 120,
+		ScoreRubric: `本次会议是复盘会，评分时请按以下侧重理解各项指标：
+- 会议目标达成度：是否总结了做得好和需要改进的地方，并形成了有负责人的改进行动
+- 主题聚焦度：是否围绕上一阶段的工作复盘，而不是讨论新需求
+- 参与者互动与参与度：成员是否坦诚表达意见，讨论是否对事不对人`,
+	},
+	MeetingTypePlanning: {
+		SummaryChars: 150,
+		ScoreRubric: `本次会议是计划会，评分时请按以下侧重理解各项指标：
+- 会议目标达成度：是否明确了范围、优先级、负责人和时间节点
+- 主题聚焦度：是否围绕本期计划讨论，并对工作量和风险做了评估
+- 参与者互动与参与度：相关成员是否参与了估算和承诺，而不是由一人直接分配`,
+	},
+	MeetingTypeOneOnOne: {
+		SummaryChars: 80,
+		ScoreRubric: `本次会议是一对一沟通，评分时请按以下侧重理解各项指标：
+- 会议目标达成度：双方关心的问题是否得到回应，是否约定了后续跟进
+- 主题聚焦度：允许话题随沟通自然展开，只在长时间偏离双方关心的问题时扣分
+- 参与者互动与参与度：是否是双向交流，双方的发言是否相对均衡`,
+	},
+	MeetingTypeDecisionReview: {
+		SummaryChars: 150,
+		ScoreRubric: `本次会议是决策评审，评分时请按以下侧重理解各项指标：
+- 会议目标达成度：是否做出了明确的决策并说明理由，未决事项是否明确了决策人和时间
+- 主题聚焦度：是否围绕待决策的问题比较了方案和风险
+- 参与者互动与参与度：相关方是否都表达了意见，反对意见是否得到了讨论`,
+	},
+	MeetingTypeOther: {
+		SummaryChars: 100,
+	},
+}
+
+// NormalizeMeetingType 将会议类型或其别名归一化为标准取值，无法识别时返回 false
+func NormalizeMeetingType(value string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(value))
+	key = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(key)
+	if key == "" {
+		return "", false
+	}
+	for _, meetingType := range MeetingTypes {
+		if key == strings.ReplaceAll(meetingType, "_", "") {
+			return meetingType, true
+		}
+	}
+	meetingType, ok := meetingTypeAliases[key]
+	return meetingType, ok
+}
+
+// ParseMeetingType 解析用户指定的会议类型，空值返回空字符串，无法识别时返回 ErrInvalidMeetingType
+func ParseMeetingType(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	meetingType, ok := NormalizeMeetingType(value)
+	if !ok {
+		return "", fmt.Errorf("%w: %s，可选值: %s", ErrInvalidMeetingType, value, strings.Join(MeetingTypes, ", "))
+	}
+	return meetingType, nil
+}
+
+// MeetingTypeLabel 返回会议类型在指定语言下的名称，未知类型原样返回
+func MeetingTypeLabel(meetingType, locale string) string {
+	labels, ok := meetingTypeLabels[locale]
+	if !ok {
+		labels = meetingTypeLabels[DefaultLocale]
+	}
+	if label, ok := labels[meetingType]; ok {
+		return label
+	}
+	return meetingType
+}
+
+// getMeetingTypeProfile 返回会议类型的摘要和评分设置，未分类或未知类型使用 other 的设置
+func getMeetingTypeProfile(meetingType string) meetingTypeProfile {
+	if profile, ok := meetingTypeProfiles[meetingType]; ok {
+		return profile
+	}
+	return meetingTypeProfiles[MeetingTypeOther]
+}
+
+// extractMeetingTypePrompt 返回抽取提示中会议类型和摘要长度的要求。
+// 用户已指定会议类型时要求模型直接使用该类型，否则由模型分类，并按分类结果控制摘要长度
+func extractMeetingTypePrompt(meetingType string) (typeInstruction, summaryLimit string) {
+	if meetingType != "" {
+		return fmt.Sprintf("已确定为 %s，直接返回该值", meetingType),
+			fmt.Sprintf("不超过%d字", getMeetingTypeProfile(meetingType).SummaryChars)
+	}
+
+	choices := make([]string, 0, len(MeetingTypes))
+	limits := make([]string, 0, len(MeetingTypes))
+	for _, t := range MeetingTypes {
+		label := MeetingTypeLabel(t, LocaleZH)
+		choices = append(choices, fmt.Sprintf("%s(%s)", t, label))
+		limits = append(limits, fmt.Sprintf("%s不超过%d字", label, getMeetingTypeProfile(t).SummaryChars))
+	}
+	return "从以下取值中选择最符合的一项：" + strings.Join(choices, ", "),
+		"按会议类型控制长度：" + strings.Join(limits, "，")
+}
+
+// withMeetingTypeRubric 将会议类型的评分侧重说明追加到评分提示后，未分类或没有侧重说明时原样返回
+func withMeetingTypeRubric(systemPrompt, meetingType string) string {
+	rubric := getMeetingTypeProfile(meetingType).ScoreRubric
+	if rubric == "" {
+		return systemPrompt
+	}
+	return systemPrompt + "\n\n" + rubric
+}
+
+// MeetingTypeCount 某一会议类型的会议数和时长
+type MeetingTypeCount struct {
+	Type                 string  `json:"type"`
+	Label                string  `json:"label"`
+	Count                int     `json:"count"`
+	Ratio                float64 `json:"ratio"`                  // 占已分类会议的比例
+	TimedCount           int     `json:"timed_count"`            // 能计算出时长的会议数
+	TotalDurationMinutes int     `json:"total_duration_minutes"` // 能计算出时长的会议的总时长
+	AvgDurationMinutes   float64 `json:"avg_duration_minutes"`   // 能计算出时长的会议的平均时长，没有时为0
+}
+
+// MeetingTypeStats 会议类型分布
+type MeetingTypeStats struct {
+	Total                int                `json:"total"`                  // 统计的会议总数
+	Unclassified         int                `json:"unclassified"`           // 没有会议类型的会议数，例如分类功能上线前创建的会议
+	TimedCount           int                `json:"timed_count"`            // 能计算出时长的会议数，包括未分类的会议
+	TotalDurationMinutes int                `json:"total_duration_minutes"` // 能计算出时长的会议的总时长
+	Types                []MeetingTypeCount `json:"types"`
+}
+
+// GetMeetingTypeStats 统计会议的类型分布和时长，所有类型都会列出（没有会议时数量为0），按数量降序排列。
+// includeArchived 为 false 时不统计已归档的会议
+func GetMeetingTypeStats(includeArchived bool, locale string) (*MeetingTypeStats, error) {
+	meetingIDs, err := ListMeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(MeetingTypes))
+	timedCounts := make(map[string]int, len(MeetingTypes))
+	durations := make(map[string]int, len(MeetingTypes))
+	stats := &MeetingTypeStats{}
+	for _, meetingID := range meetingIDs {
+		meetingData, err := LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("读取会议 %s 失败: %v\n", meetingID, err)
+			continue
+		}
+		rawMetadata, _ := meetingData["metadata"].(map[string]interface{})
+		if !includeArchived && IsMeetingArchived(rawMetadata) {
+			continue
+		}
+
+		stats.Total++
+		metadata, _ := GetMeetingMetadata(meetingData)
+		// 时长按开始和结束时间实时计算，早于时长功能创建的会议同样统计
+		duration := metadata.Timing().DurationMinutes
+		if duration > 0 {
+			stats.TimedCount++
+			stats.TotalDurationMinutes += duration
+		}
+		if metadata.MeetingType == "" {
+			stats.Unclassified++
+			continue
+		}
+		counts[metadata.MeetingType]++
+		if duration > 0 {
+			timedCounts[metadata.MeetingType]++
+			durations[metadata.MeetingType] += duration
+		}
+	}
+
+	classified := stats.Total - stats.Unclassified
+	stats.Types = make([]MeetingTypeCount, 0, len(MeetingTypes))
+	for _, meetingType := range MeetingTypes {
+		item := MeetingTypeCount{
+			Type:  meetingType,
+			Label: MeetingTypeLabel(meetingType, locale),
+			Count: counts[meetingType],
+
+			TimedCount:           timedCounts[meetingType],
+			TotalDurationMinutes: durations[meetingType],
+		}
+		if classified > 0 {
+			item.Ratio = float64(item.Count) / float64(classified)
+		}
+		if item.TimedCount > 0 {
+			item.AvgDurationMinutes = float64(item.TotalDurationMinutes) / float64(item.TimedCount)
+		}
+		stats.Types = append(stats.Types, item)
+	}
+	// 数量相同时保持 MeetingTypes 的顺序
+	sort.SliceStable(stats.Types, func(i, j int) bool {
+		return stats.Types[i].Count > stats.Types[j].Count
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,281 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultMeetingTitle 模型没有给出会议标题时使用的标题
+const defaultMeetingTitle = "未知会议"
+
+// MeetingMetadata 会议元数据中由LLM抽取的字段。会议文件的 metadata 中还保存状态、标签、
+// 归档时间等其他字段，通过 ToMap 合并写入，读取时忽略
+type MeetingMetadata struct {
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	Participants []string        `json:"participants"`
+	StartTime    string          `json:"start_time"`
+	EndTime      string          `json:"end_time"`
+	Summary      string          `json:"summary"`
+	TodoList     []ExtractedTodo `json:"todo_list"`
+	MeetingType  string          `json:"meeting_type"` // 会议类型，旧会议没有时为空字符串
+	// 会议类型的来源，model 或 user；用户指定的类型在重新抽取时保留
+	MeetingTypeSource string `json:"meeting_type_source,omitempty"`
+	// 超出每个会议待办数上限、没有自动创建的待办，可由用户按需手动添加
+	UntrackedTodos []ExtractedTodo `json:"untracked_todos,omitempty"`
+}
+
+// DecodeMeetingMetadata 将模型输出或会议文件中的元数据解码为 MeetingMetadata：
+// 字符串字段去除首尾空白，类型不符的字段按缺失处理，参会人员去除空白和重复项，
+// 待办事项兼容字符串和对象两种格式
+func DecodeMeetingMetadata(raw map[string]interface{}) MeetingMetadata {
+	var names []string
+	list, _ := raw["participants"].([]interface{})
+	for _, p := range list {
+		if name, ok := p.(string); ok {
+			names = append(names, name)
+		}
+	}
+
+	metadata := MeetingMetadata{
+		Title:          metadataString(raw, "title"),
+		Description:    metadataString(raw, "description"),
+		Participants:   normalizeParticipants(names),
+		StartTime:      metadataString(raw, "start_time"),
+		EndTime:        metadataString(raw, "end_time"),
+		Summary:        metadataString(raw, "summary"),
+		TodoList:       ParseExtractedTodos(raw["todo_list"]),
+		UntrackedTodos: ParseExtractedTodos(raw["untracked_todos"]),
+	}
+	if metadata.TodoList == nil {
+		metadata.TodoList = []ExtractedTodo{}
+	}
+	// 模型返回了无法识别的会议类型时归为 other
+	if meetingType := metadataString(raw, "meeting_type"); meetingType != "" {
+		metadata.MeetingType = MeetingTypeOther
+		if normalized, ok := NormalizeMeetingType(meetingType); ok {
+			metadata.MeetingType = normalized
+		}
+		metadata.MeetingTypeSource = metadataString(raw, "meeting_type_source")
+	}
+
+	return metadata
+}
+
+// normalizeParticipants 去除参会人员姓名的首尾空白、空姓名和重复姓名（忽略大小写和空白），保持原有顺序
+func normalizeParticipants(names []string) []string {
+	participants := []string{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := normalizeParticipantName(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		participants = append(participants, name)
+	}
+	return participants
+}
+
+// GetMeetingMetadata 读取会议数据中的元数据，没有 metadata 字段的旧格式会议返回 false
+func GetMeetingMetadata(meetingData map[string]interface{}) (MeetingMetadata, bool) {
+	raw, ok := meetingData["metadata"].(map[string]interface{})
+	return DecodeMeetingMetadata(raw), ok
+}
+
+// Validate 校验抽取结果：摘要和参会人员不能为空，缺少标题时使用默认标题
+func (m *MeetingMetadata) Validate() error {
+	if m.Summary == "" {
+		return fmt.Errorf("缺少会议摘要")
+	}
+	if len(m.Participants) == 0 {
+		return fmt.Errorf("缺少参会人员")
+	}
+	if m.Title == "" {
+		m.Title = defaultMeetingTitle
+	}
+	return nil
+}
+
+// ToMap 将元数据转换为写入会议文件的字段，并写入由开始和结束时间计算出的 start_at、end_at、duration_minutes 和 time_warnings
+func (m MeetingMetadata) ToMap() map[string]interface{} {
+	participants := make([]interface{}, 0, len(m.Participants))
+	for _, name := range m.Participants {
+		participants = append(participants, name)
+	}
+	result := map[string]interface{}{
+		"title":        m.Title,
+		"description":  m.Description,
+		"participants": participants,
+		"start_time":   m.StartTime,
+		"end_time":     m.EndTime,
+		"summary":      m.Summary,
+		"todo_list":    extractedTodosToList(m.TodoList),
+		"meeting_type": m.MeetingType,
+		// 总是写入，重新抽取时覆盖之前的来源
+		"meeting_type_source": m.MeetingTypeSource,
+		// 总是写入，重新抽取后不再超出上限时清除之前的记录
+		"untracked_todos": extractedTodosToList(m.UntrackedTodos),
+	}
+	m.Timing().writeTo(result)
+	return result
+}
+
+// extractedTodosToList 将待办事项转换为写入会议文件的列表
+func extractedTodosToList(todos []ExtractedTodo) []interface{} {
+	list := make([]interface{}, 0, len(todos))
+	for _, todo := range todos {
+		list = append(list, map[string]interface{}{
+			"task":     todo.Task,
+			"assignee": todo.Assignee,
+			"due_date": todo.DueDate,
+		})
+	}
+	return list
+}
+
+// TodoItems 返回待办事项的展示文本，用于报告推送和聊天上下文
+func (m MeetingMetadata) TodoItems() []string {
+	items := make([]string, 0, len(m.TodoList))
+	for _, todo := range m.TodoList {
+		items = append(items, FormatTodoItem(todo))
+	}
+	return items
+}
+
+// Describe 将元数据格式化为提供给LLM的会议信息，空字段不输出
+func (m MeetingMetadata) Describe() string {
+	var b strings.Builder
+	b.WriteString("会议信息:\n")
+	if m.Title != "" {
+		b.WriteString("标题: " + m.Title + "\n")
+	}
+	if m.Description != "" {
+		b.WriteString("描述: " + m.Description + "\n")
+	}
+	if len(m.Participants) > 0 {
+		b.WriteString("参会人员: " + strings.Join(m.Participants, ", ") + "\n")
+	}
+	if m.MeetingType != "" {
+		b.WriteString("会议类型: " + MeetingTypeLabel(m.MeetingType, LocaleZH) + "\n")
+	}
+	if m.StartTime != "" {
+		b.WriteString("开始时间: " + m.StartTime + "\n")
+	}
+	if m.EndTime != "" {
+		b.WriteString("结束时间: " + m.EndTime + "\n")
+	}
+	if m.Summary != "" {
+		b.WriteString("摘要: " + m.Summary + "\n")
+	}
+	return b.String()
+}
+
+// MeetingMetadataPatch 部分更新会议元数据的请求，为 nil 的字段保持不变。
+// 状态、标签和归档状态通过各自的接口修改，待办事项通过重新抽取或待办接口修改。
+// 修改会议类型后类型来源记为 user，重新抽取时不再由模型覆盖
+type MeetingMetadataPatch struct {
+	Title        *string   `json:"title"`
+	Description  *string   `json:"description"`
+	Participants *[]string `json:"participants"`
+	StartTime    *string   `json:"start_time"`
+	EndTime      *string   `json:"end_time"`
+	Summary      *string   `json:"summary"`
+	MeetingType  *string   `json:"meeting_type"`
+}
+
+// ParseMeetingMetadataPatch 解析并校验部分更新请求：不允许未知字段，标题和摘要不能改为空，
+// 参会人员去除空白和重复项后不能为空，至少需要提供一个字段
+func ParseMeetingMetadataPatch(data []byte) (*MeetingMetadataPatch, error) {
+	var patch MeetingMetadataPatch
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		return nil, fmt.Errorf("无效的请求体: %v", err)
+	}
+
+	for _, field := range []*string{patch.Title, patch.Description, patch.StartTime, patch.EndTime, patch.Summary} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	if patch.Title != nil && *patch.Title == "" {
+		return nil, fmt.Errorf("title 不能为空")
+	}
+	if patch.Summary != nil && *patch.Summary == "" {
+		return nil, fmt.Errorf("summary 不能为空")
+	}
+
+	if patch.MeetingType != nil {
+		meetingType, ok := NormalizeMeetingType(*patch.MeetingType)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s，可选值: %s", ErrInvalidMeetingType, *patch.MeetingType, strings.Join(MeetingTypes, ", "))
+		}
+		patch.MeetingType = &meetingType
+	}
+
+	if patch.Participants != nil {
+		participants := normalizeParticipants(*patch.Participants)
+		if len(participants) == 0 {
+			return nil, fmt.Errorf("participants 至少需要一名参会人员")
+		}
+		patch.Participants = &participants
+	}
+
+	if patch.Title == nil && patch.Description == nil && patch.Participants == nil &&
+		patch.StartTime == nil && patch.EndTime == nil && patch.Summary == nil && patch.MeetingType == nil {
+		return nil, fmt.Errorf("至少需要提供一个要修改的字段")
+	}
+
+	return &patch, nil
+}
+
+// PatchMeetingMetadata 在会议锁内将部分更新合并到会议元数据中，只覆盖请求中提供的字段，
+// 修改开始或结束时间时重新计算会议时长，并记录 updated_at，返回更新后的完整元数据
+func PatchMeetingMetadata(meetingID string, patch *MeetingMetadataPatch) (map[string]interface{}, error) {
+	var updated map[string]interface{}
+	err := UpdateMeetingMetadata(meetingID, func(metadata map[string]interface{}) bool {
+		for key, value := range map[string]*string{
+			"title":       patch.Title,
+			"description": patch.Description,
+			"start_time":  patch.StartTime,
+			"end_time":    patch.EndTime,
+			"summary":     patch.Summary,
+		} {
+			if value != nil {
+				metadata[key] = *value
+			}
+		}
+		if patch.Participants != nil {
+			participants := make([]interface{}, 0, len(*patch.Participants))
+			for _, name := range *patch.Participants {
+				participants = append(participants, name)
+			}
+			metadata["participants"] = participants
+		}
+		if patch.MeetingType != nil {
+			metadata["meeting_type"] = *patch.MeetingType
+			metadata["meeting_type_source"] = MeetingTypeSourceUser
+		}
+		if patch.StartTime != nil || patch.EndTime != nil {
+			DecodeMeetingMetadata(metadata).Timing().writeTo(metadata)
+		}
+		metadata["updated_at"] = time.Now().Format(time.RFC3339)
+		updated = metadata
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// metadataString 读取字符串字段并去除首尾空白，字段缺失或不是字符串时返回空字符串
+func metadataString(raw map[string]interface{}, key string) string {
+	value, _ := raw[key].(string)
+	return strings.TrimSpace(value)
+}
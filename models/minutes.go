@@ -0,0 +1,271 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// minutesArtifactKind 正式会议纪要的缓存类型，不同回答语言分别缓存
+const minutesArtifactKind = "minutes"
+
+// MinutesDiscussionItem 会议纪要中一个议题的讨论要点
+type MinutesDiscussionItem struct {
+	Topic   string `json:"topic"`
+	Summary string `json:"summary"`
+}
+
+// MinutesActionItem 会议纪要中的一项行动项，来自会议抽取出的待办事项
+type MinutesActionItem struct {
+	Task    string `json:"task"`
+	Owner   string `json:"owner"`
+	DueDate string `json:"due_date"`
+}
+
+// MeetingMinutes 正式会议纪要，章节固定为会议信息、参会人员、议程、讨论要点、决议事项、行动项和下次会议。
+// 参会人员和行动项取自会议元数据，其余章节由模型按会议内容整理
+type MeetingMinutes struct {
+	MeetingID   string                  `json:"meeting_id"`
+	Title       string                  `json:"title"`
+	Date        string                  `json:"date"`
+	Attendees   []string                `json:"attendees"`
+	Agenda      []string                `json:"agenda"`
+	Discussion  []MinutesDiscussionItem `json:"discussion"`
+	Decisions   []string                `json:"decisions"`
+	ActionItems []MinutesActionItem     `json:"action_items"`
+	NextMeeting string                  `json:"next_meeting"`
+	Markdown    string                  `json:"markdown"`
+	GeneratedAt time.Time               `json:"generated_at"`
+	Cached      bool                    `json:"cached"` // 是否来自缓存
+}
+
+// minutesLabels 纪要 Markdown 中的章节标题和表头
+type minutesLabels struct {
+	Date, Attendees, Agenda, Discussion, Decisions, ActionItems, NextMeeting string
+	Task, Owner, Due, None                                                   string
+}
+
+// minutesHeadings 各语言的纪要章节标题
+var minutesHeadings = map[string]minutesLabels{
+	LocaleZH: {"日期", "参会人员", "议程", "讨论要点", "决议事项", "行动项", "下次会议", "事项", "负责人", "截止", "无"},
+	LocaleEN: {"Date", "Attendees", "Agenda", "Discussion", "Decisions", "Action Items", "Next Meeting", "Task", "Owner", "Due", "None"},
+	LocaleJA: {"日付", "出席者", "議題", "討議内容", "決定事項", "アクションアイテム", "次回会議", "項目", "担当者", "期限", "なし"},
+}
+
+// GetMeetingMinutes 获取会议的正式纪要，优先使用缓存，refresh 为 true 时强制重新生成。
+// 会议内容、元数据或待办事项变化后缓存自动失效
+func GetMeetingMinutes(ctx context.Context, meetingID string, refresh bool) (*MeetingMinutes, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, _ := GetMeetingMetadata(meetingData)
+	meetingContent := MeetingDataContent(meetingData)
+	meetingInfo := metadata.Describe()
+
+	todoLines := make([]string, 0, len(metadata.TodoList))
+	for _, todo := range metadata.TodoList {
+		todoLines = append(todoLines, FormatTodoItem(todo))
+	}
+
+	locale := LocaleFromContext(ctx)
+	kind := minutesArtifactKind + "_" + locale
+	sourceHash := HashContent(meetingInfo, meetingContent, strings.Join(todoLines, "\n"))
+
+	if !refresh {
+		var cached MeetingMinutes
+		if LoadCachedArtifact(meetingID, kind, sourceHash, &cached) {
+			cached.Cached = true
+			return &cached, nil
+		}
+	}
+
+	minutes, err := GenerateMeetingMinutes(ctx, meetingInfo+"\n会议内容:\n"+meetingContent, todoLines)
+	if err != nil {
+		return nil, err
+	}
+
+	minutes.MeetingID = meetingID
+	minutes.Title = metadata.Title
+	if minutes.Title == "" {
+		minutes.Title = "未知会议"
+	}
+	minutes.Date = metadata.StartTime
+	if minutes.Date == "" {
+		if createdAt, ok := MeetingCreatedAt(meetingID); ok {
+			minutes.Date = createdAt.Format("2006-01-02 15:04")
+		}
+	}
+	if len(metadata.Participants) > 0 {
+		minutes.Attendees = metadata.Participants
+	}
+	minutes.ActionItems = make([]MinutesActionItem, 0, len(metadata.TodoList))
+	for _, todo := range metadata.TodoList {
+		minutes.ActionItems = append(minutes.ActionItems, MinutesActionItem{Task: todo.Task, Owner: todo.Assignee, DueDate: todo.DueDate})
+	}
+	minutes.Markdown = renderMinutesMarkdown(minutes, locale)
+	minutes.GeneratedAt = time.Now()
+
+	if err := SaveCachedArtifact(meetingID, kind, sourceHash, minutes); err != nil {
+		// 缓存失败不影响本次结果
+		Logf(ctx, "缓存会议纪要失败: %v\n", err)
+	}
+
+	return minutes, nil
+}
+
+// GenerateMeetingMinutes 使用LLM将会议记录整理为正式纪要的议程、讨论要点、决议事项和下次会议安排。
+// todoLines 为会议抽取出的待办事项，作为行动项提供给模型，避免讨论要点和决议与行动项不一致
+func GenerateMeetingMinutes(ctx context.Context, documentText string, todoLines []string) (*MeetingMinutes, error) {
+	chatModel, err := GetChatModel(ctx, 0.2) // 正式纪要需要稳定、贴近原文的输出
+	if err != nil {
+		return nil, fmt.Errorf("创建LLM客户端失败: %v", err)
+	}
+
+	actionItems := "无"
+	if len(todoLines) > 0 {
+		actionItems = "- " + strings.Join(todoLines, "\n- ")
+	}
+
+	systemPrompt := fmt.Sprintf(`你是一个专业的会议记录员，负责为组织存档撰写正式的会议纪要。请根据会议内容整理以下章节：
+1. agenda: 会议议程，按讨论顺序列出议题
+2. attendees: 参会人员姓名
+3. discussion: 每个议题的讨论要点，客观陈述各方观点
+4. decisions: 会议中明确做出的决议，没有明确决议时返回空数组
+5. next_meeting: 下次会议的时间、议题等安排，会议中没有提到时返回空字符串
+
+会议已确认的行动项如下，行动项章节由系统直接使用这些内容，讨论要点和决议应与其保持一致：
+%s
+
+要求：
+1. 只记录会议中实际出现的内容，不要编造或推测
+2. 使用正式、客观的书面语，不使用口语和第一人称
+
+以下是你必须返回的JSON格式（不要输出其他内容）：
+{
+  "agenda": ["议题1", "议题2"],
+  "attendees": ["姓名"],
+  "discussion": [{"topic": "议题1", "summary": "讨论要点..."}],
+  "decisions": ["决议1"],
+  "next_meeting": "下次会议安排"
+}`, actionItems)
+
+	messages := []*schema.Message{
+		schema.SystemMessage(withLocaleInstruction(ctx, systemPrompt)),
+		schema.UserMessage(documentText),
+	}
+
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	response, err := chatModel.Generate(callCtx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("生成会议纪要失败: %v", err)
+	}
+
+	var minutes MeetingMinutes
+	if err := parseJSONObject(response.Content, &minutes); err != nil {
+		return nil, fmt.Errorf("解析会议纪要失败: %v", err)
+	}
+
+	minutes.Agenda = nonEmptyStrings(minutes.Agenda)
+	minutes.Attendees = nonEmptyStrings(minutes.Attendees)
+	minutes.Decisions = nonEmptyStrings(minutes.Decisions)
+	discussion := make([]MinutesDiscussionItem, 0, len(minutes.Discussion))
+	for _, item := range minutes.Discussion {
+		item.Topic = strings.TrimSpace(item.Topic)
+		item.Summary = strings.TrimSpace(item.Summary)
+		if item.Summary != "" {
+			discussion = append(discussion, item)
+		}
+	}
+	minutes.Discussion = discussion
+	minutes.NextMeeting = strings.TrimSpace(minutes.NextMeeting)
+
+	return &minutes, nil
+}
+
+// nonEmptyStrings 去除首尾空白并丢弃空字符串，结果不为 nil，便于序列化为空数组
+func nonEmptyStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// renderMinutesMarkdown 按固定章节将纪要渲染为 Markdown，章节标题使用 locale 对应的语言，没有内容的章节写"无"
+func renderMinutesMarkdown(minutes *MeetingMinutes, locale string) string {
+	headings, ok := minutesHeadings[locale]
+	if !ok {
+		headings = minutesHeadings[DefaultLocale]
+	}
+	none := headings.None
+
+	var b strings.Builder
+	b.WriteString("# " + minutes.Title + "\n\n")
+	date := minutes.Date
+	if date == "" {
+		date = none
+	}
+	b.WriteString(fmt.Sprintf("**%s:** %s\n\n", headings.Date, date))
+
+	writeList := func(title string, items []string) {
+		b.WriteString("## " + title + "\n\n")
+		if len(items) == 0 {
+			b.WriteString(none + "\n\n")
+			return
+		}
+		for _, item := range items {
+			b.WriteString("- " + item + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	writeList(headings.Attendees, minutes.Attendees)
+	writeList(headings.Agenda, minutes.Agenda)
+
+	b.WriteString("## " + headings.Discussion + "\n\n")
+	if len(minutes.Discussion) == 0 {
+		b.WriteString(none + "\n\n")
+	}
+	for _, item := range minutes.Discussion {
+		if item.Topic != "" {
+			b.WriteString("### " + item.Topic + "\n\n")
+		}
+		b.WriteString(item.Summary + "\n\n")
+	}
+
+	writeList(headings.Decisions, minutes.Decisions)
+
+	// 行动项以表格列出，便于存档和核对负责人
+	b.WriteString("## " + headings.ActionItems + "\n\n")
+	if len(minutes.ActionItems) == 0 {
+		b.WriteString(none + "\n\n")
+	} else {
+		b.WriteString(fmt.Sprintf("| # | %s | %s | %s |\n|---|---|---|---|\n", headings.Task, headings.Owner, headings.Due))
+		for i, item := range minutes.ActionItems {
+			b.WriteString(fmt.Sprintf("| %d | %s | %s | %s |\n", i+1, markdownCell(item.Task), markdownCell(item.Owner), markdownCell(item.DueDate)))
+		}
+		b.WriteString("\n")
+	}
+
+	nextMeeting := minutes.NextMeeting
+	if nextMeeting == "" {
+		nextMeeting = none
+	}
+	b.WriteString("## " + headings.NextMeeting + "\n\n" + nextMeeting + "\n")
+
+	return b.String()
+}
+
+// markdownCell 转义表格单元格中的竖线和换行
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,250 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// mockModelName 模拟模式下使用的模型名称，记录在健康检查和用量日志中
+const mockModelName = "mock"
+
+// mockStreamChunkRunes 模拟流式输出时每个分片的字符数
+const mockStreamChunkRunes = 8
+
+// mockChatModel 离线模拟的聊天模型，不需要 API 密钥和网络，供本地开发和 CI 跑通各接口。
+// 按系统提示要求的输出格式返回固定的示例结果（抽取、评分、流程图、风险、纪要等 JSON 可以正常解析），
+// 相同的输入总是得到相同的输出
+type mockChatModel struct {
+	model string
+}
+
+// newMockChatModel 创建模拟聊天模型，spec 中的模型名称只用于标识，温度不影响输出
+func newMockChatModel(spec ModelSpec) *mockChatModel {
+	modelName := spec.ModelName
+	if modelName == "" {
+		modelName = mockModelName
+	}
+	return &mockChatModel{model: modelName}
+}
+
+// Generate 生成完整回复
+func (m *mockChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content := mockResponse(input)
+	return &schema.Message{
+		Role:    schema.Assistant,
+		Content: content,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: "stop",
+			Usage:        mockUsage(input, content),
+		},
+	}, nil
+}
+
+// Stream 将完整回复按固定字符数切分为多个分片返回，token 用量放在最后一个分片中
+func (m *mockChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content := mockResponse(input)
+
+	runes := []rune(content)
+	chunks := make([]*schema.Message, 0, len(runes)/mockStreamChunkRunes+1)
+	for start := 0; start < len(runes); start += mockStreamChunkRunes {
+		end := min(start+mockStreamChunkRunes, len(runes))
+		chunks = append(chunks, &schema.Message{Role: schema.Assistant, Content: string(runes[start:end])})
+	}
+	chunks = append(chunks, &schema.Message{
+		Role: schema.Assistant,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: "stop",
+			Usage:        mockUsage(input, content),
+		},
+	})
+	return schema.StreamReaderFromArray(chunks), nil
+}
+
+// mockUsage 按字符数估算 token 用量，使用量统计和费用估算在模拟模式下同样有数据
+func mockUsage(input []*schema.Message, content string) *schema.TokenUsage {
+	promptTokens := 0
+	for _, msg := range input {
+		promptTokens += utf8.RuneCountInString(msg.Content)
+	}
+	completionTokens := utf8.RuneCountInString(content)
+	return &schema.TokenUsage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// mockResponse 根据系统提示判断调用方需要的输出格式并返回对应的示例结果。
+// 系统提示可能包含会议内容，摘要类请求只按第一行的指令判断，避免会议内容中的字词影响判断
+func mockResponse(input []*schema.Message) string {
+	var system, user string
+	for _, msg := range input {
+		switch msg.Role {
+		case schema.System:
+			system += msg.Content + "\n"
+		case schema.User:
+			user = msg.Content
+		}
+	}
+	instruction := firstNonEmptyLine(system)
+
+	switch {
+	case strings.Contains(system, "标注说话人"):
+		return mockDiarization(user)
+	case strings.Contains(system, `"goal_achievement"`):
+		return mockJSON(map[string]interface{}{
+			"goal_achievement":                3,
+			"goal_achievement_feedback":       "（模拟评价）会议基本达成了预定目标，形成了后续行动项。",
+			"topic_focus":                     3,
+			"topic_focus_feedback":            "（模拟评价）讨论基本围绕会议主题展开。",
+			"participant_engagement":          3,
+			"participant_engagement_feedback": "（模拟评价）多数参会者参与了讨论。",
+			"overall_feedback":                "（模拟评价）这是模拟模型生成的固定评分，未调用真实模型。",
+			"short_verdict":                   "模拟评分：会议整体表现良好",
+		})
+	case strings.Contains(system, "todo_list"):
+		return mockMeetingInfo(user)
+	case strings.Contains(system, "mermaid"):
+		return "'''mermaid\nflowchart TD\n    A[会议开始] --> B[讨论议题]\n    B --> C{是否达成共识}\n    C -->|是| D[确定行动项]\n    C -->|否| E[会后继续讨论]\n    D --> F[会议结束]\n    E --> F\n'''"
+	case strings.Contains(system, `"overall_level"`):
+		return mockJSON(map[string]interface{}{
+			"overall_level": RiskLevelLow,
+			"conclusion":    "（模拟分析）未发现需要重点关注的风险。",
+			"risks": []map[string]string{{
+				"dimension":   "进度",
+				"level":       RiskLevelLow,
+				"description": "（模拟分析）会议中的行动项没有明确的截止日期。",
+				"evidence":    mockExcerpt(user, 30),
+				"mitigation":  "为每项行动指定负责人和截止日期。",
+			}},
+		})
+	case strings.Contains(system, `"next_meeting"`):
+		return mockJSON(map[string]interface{}{
+			"agenda":       []string{"会议议题"},
+			"attendees":    mockParticipants(user),
+			"discussion":   []map[string]string{{"topic": "会议议题", "summary": mockSummary(user)}},
+			"decisions":    []string{},
+			"next_meeting": "",
+		})
+	case strings.Contains(system, `"converged"`):
+		return mockJSON(map[string]interface{}{"converged": false, "reason": "模拟模型不判断讨论是否收敛"})
+	case strings.Contains(system, "JSON"):
+		// 其他要求返回 JSON 的提示（如结构化摘要）直接返回提示中的 JSON 示例
+		if example, ok := mockJSONExample(system); ok {
+			return example
+		}
+	case strings.Contains(instruction, "摘要") || strings.Contains(instruction, "总结"):
+		return mockSummary(user)
+	}
+	return fmt.Sprintf("（模拟回复）收到：%s。这是模拟模型生成的固定回复，未调用真实模型。", mockExcerpt(user, 40))
+}
+
+// mockMeetingInfo 返回会议信息抽取的示例结果，参会人员取自会议记录中的说话人标签
+func mockMeetingInfo(content string) string {
+	participants := mockParticipants(content)
+	title := "模拟会议"
+	if line := mockExcerpt(content, 20); line != "" {
+		title += "：" + line
+	}
+	return mockJSON(map[string]interface{}{
+		"title":        title,
+		"description":  "模拟模式生成的会议描述，未调用真实模型",
+		"participants": participants,
+		"start_time":   "",
+		"end_time":     "",
+		"summary":      mockSummary(content),
+		"todo_list": []map[string]string{{
+			"task":     "整理会议结论并同步给参会人员",
+			"assignee": participants[0],
+			"due_date": "",
+		}},
+		"meeting_type": MeetingTypeOther,
+	})
+}
+
+// mockParticipants 返回会议记录中出现的说话人，没有说话人标签时返回两位示例参会人员
+func mockParticipants(content string) []string {
+	speakers, _, _ := speakerLabels(content)
+	if len(speakers) == 0 {
+		return []string{"张三", "李四"}
+	}
+	if len(speakers) > 10 {
+		speakers = speakers[:10]
+	}
+	return speakers
+}
+
+// mockDiarization 按行交替标注"发言人1"、"发言人2"，保留原文内容和空行
+func mockDiarization(content string) string {
+	lines := strings.Split(content, "\n")
+	speaker := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines[i] = fmt.Sprintf("发言人%d: %s", speaker%2+1, strings.TrimSpace(line))
+		speaker++
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mockSummary 以内容开头的一段文字作为示例摘要
+func mockSummary(content string) string {
+	return "（模拟摘要）会议讨论了：" + mockExcerpt(content, 80)
+}
+
+// mockExcerpt 返回去掉说话人标签和用户输入分隔标签、合并空白后的内容开头，最多 maxRunes 个字符
+func mockExcerpt(content string, maxRunes int) string {
+	content = strings.NewReplacer(userInputOpenTag, "", userInputCloseTag, "").Replace(content)
+	var parts []string
+	for _, line := range strings.Split(content, "\n") {
+		if match := speakerLabelPattern.FindStringSubmatch(line); match != nil {
+			line = match[2]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return truncateRunes(strings.Join(strings.Fields(strings.Join(parts, " ")), " "), maxRunes)
+}
+
+// mockJSONExample 取出提示中最后一个 "JSON格式" 之后的 JSON 示例，不是合法 JSON 时返回 false
+func mockJSONExample(prompt string) (string, bool) {
+	index := strings.LastIndex(prompt, "JSON格式")
+	if index < 0 {
+		return "", false
+	}
+	var example map[string]interface{}
+	if err := parseJSONObject(prompt[index:], &example); err != nil {
+		return "", false
+	}
+	return mockJSON(example), true
+}
+
+// mockJSON 序列化示例结果，输入均为固定结构，不会失败
+func mockJSON(value interface{}) string {
+	data, _ := json.MarshalIndent(value, "", "  ")
+	return string(data)
+}
+
+// firstNonEmptyLine 返回文本的第一个非空行
+func firstNonEmptyLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
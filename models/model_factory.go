@@ -0,0 +1,217 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ModelFactory 按模型名称和温度缓存聊天模型，避免每次请求都重新读取配置和初始化客户端。
+// 其余模型参数都来自配置文件，因此 ModelSpec 是唯一的缓存键
+type ModelFactory struct {
+	newModel ModelConstructor
+	models   sync.Map // ModelSpec -> *modelEntry
+}
+
+// ModelSpec 创建聊天模型的参数，ModelName 为空时使用模型提供方配置中的模型名称
+type ModelSpec struct {
+	ModelName   string
+	Temperature float32
+}
+
+// ModelConstructor 创建指定参数的聊天模型
+type ModelConstructor func(ctx context.Context, spec ModelSpec) (LLM, error)
+
+// modelEntry 一组参数对应的模型，once 保证并发请求只初始化一次
+type modelEntry struct {
+	once  sync.Once
+	model LLM
+	err   error
+}
+
+// defaultModelFactory 全局共享的模型工厂，context 中没有注入模型工厂时通过它获取模型
+var defaultModelFactory = NewModelFactory(nil)
+
+// NewModelFactory 创建使用指定构造函数的模型工厂，newModel 为 nil 时按配置的模型提供方创建
+func NewModelFactory(newModel ModelConstructor) *ModelFactory {
+	if newModel == nil {
+		newModel = newChatModel
+	}
+	return &ModelFactory{newModel: newModel}
+}
+
+// modelFactoryKey context 中模型工厂的键
+type modelFactoryKey struct{}
+
+// WithModelFactory 返回使用指定模型工厂的 context，抽取、评分、流程图等逻辑从该 context 获取的模型都由 f 创建。
+// 用于注入返回预设输出的模型，使这些逻辑可以脱离真实模型运行，且不影响同时处理的其他请求
+func WithModelFactory(ctx context.Context, f *ModelFactory) context.Context {
+	return context.WithValue(ctx, modelFactoryKey{}, f)
+}
+
+// modelFactoryFromContext 返回 context 中注入的模型工厂，没有注入时返回全局模型工厂
+func modelFactoryFromContext(ctx context.Context) *ModelFactory {
+	if f, ok := ctx.Value(modelFactoryKey{}).(*ModelFactory); ok && f != nil {
+		return f
+	}
+	return defaultModelFactory
+}
+
+// GetChatModel 获取指定温度的聊天模型，使用模型提供方配置中的模型
+func GetChatModel(ctx context.Context, temperature float32) (LLM, error) {
+	return modelFactoryFromContext(ctx).Get(ctx, ModelSpec{Temperature: temperature})
+}
+
+// GetFeatureChatModel 获取指定功能使用的聊天模型：配置了 models.<feature> 时使用其中的模型名称和温度，
+// 未配置的部分分别使用模型提供方配置中的模型和 temperature
+func GetFeatureChatModel(ctx context.Context, feature string, temperature float32) (LLM, error) {
+	return modelFactoryFromContext(ctx).Get(ctx, GetFeatureModelSpec(feature, temperature))
+}
+
+// 角色扮演中人物发言的默认温度和请求可指定的温度范围
+const (
+	defaultPersonaTemperature float32 = 0.7
+	minPersonaTemperature     float32 = 0
+	maxPersonaTemperature     float32 = 1
+)
+
+// ValidatePersonaTemperature 校验请求中指定的角色扮演温度，未指定（nil）时不校验
+func ValidatePersonaTemperature(temperature *float32) error {
+	if temperature == nil {
+		return nil
+	}
+	// 写成取反的形式，NaN 同样校验不通过
+	if !(*temperature >= minPersonaTemperature && *temperature <= maxPersonaTemperature) {
+		return fmt.Errorf("temperature 必须在 %g 到 %g 之间，当前为 %g", minPersonaTemperature, maxPersonaTemperature, *temperature)
+	}
+	return nil
+}
+
+// GetPersonaChatModel 获取角色扮演中人物发言使用的聊天模型。temperature 为请求中指定的温度，
+// 不为 nil 时优先于配置 models.roleplay.temperature 和默认温度0.7。
+// 请求指定的温度在每次调用时通过 model.WithTemperature 传入，不作为模型缓存的键，
+// 避免每个不同的温度值都创建并缓存一个模型客户端
+func GetPersonaChatModel(ctx context.Context, temperature *float32) (LLM, error) {
+	chatModel, err := modelFactoryFromContext(ctx).Get(ctx, GetFeatureModelSpec(ModelFeatureRoleplay, defaultPersonaTemperature))
+	if err != nil || temperature == nil {
+		return chatModel, err
+	}
+	return &temperatureLLM{LLM: chatModel, temperature: *temperature}, nil
+}
+
+// temperatureLLM 每次调用都使用指定温度的聊天模型，调用方传入的选项仍可覆盖该温度
+type temperatureLLM struct {
+	LLM
+	temperature float32
+}
+
+func (m *temperatureLLM) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.LLM.Generate(ctx, input, m.withTemperature(opts)...)
+}
+
+func (m *temperatureLLM) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return m.LLM.Stream(ctx, input, m.withTemperature(opts)...)
+}
+
+// withTemperature 在调用方的选项之前加上温度选项
+func (m *temperatureLLM) withTemperature(opts []model.Option) []model.Option {
+	return append([]model.Option{model.WithTemperature(m.temperature)}, opts...)
+}
+
+// ResetChatModels 清空全局模型工厂的缓存，下次获取时重新创建
+func ResetChatModels() {
+	defaultModelFactory.Reset()
+}
+
+// 可以在配置文件 models 中单独指定模型的功能
+const (
+	ModelFeatureExtract  = "extract"
+	ModelFeatureScore    = "score"
+	ModelFeatureChat     = "chat"
+	ModelFeatureRoleplay = "roleplay"
+)
+
+// GetFeatureModelSpec 获取功能使用的模型参数，配置中未指定模型名称或温度时
+// 分别使用模型提供方配置中的模型和 temperature，未知功能直接使用默认参数
+func GetFeatureModelSpec(feature string, temperature float32) ModelSpec {
+	spec := ModelSpec{Temperature: temperature}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return spec
+	}
+
+	var override ModelOverride
+	switch feature {
+	case ModelFeatureExtract:
+		override = cfg.Models.Extract
+	case ModelFeatureScore:
+		override = cfg.Models.Score
+	case ModelFeatureChat:
+		override = cfg.Models.Chat
+	case ModelFeatureRoleplay:
+		override = cfg.Models.Roleplay
+	}
+
+	spec.ModelName = strings.TrimSpace(override.ModelName)
+	if override.Temperature != nil {
+		spec.Temperature = *override.Temperature
+	}
+	return spec
+}
+
+// Get 获取指定参数的聊天模型，首次获取时创建。创建失败不会被缓存，下次获取时重试。
+// 返回的模型受全局并发上限 llm.max_concurrent 限制
+func (f *ModelFactory) Get(ctx context.Context, spec ModelSpec) (LLM, error) {
+	value, _ := f.models.LoadOrStore(spec, &modelEntry{})
+	entry := value.(*modelEntry)
+
+	entry.once.Do(func() {
+		entry.model, entry.err = f.newModel(ctx, spec)
+		if entry.err == nil {
+			entry.model = &limitedLLM{LLM: entry.model, limiter: defaultLLMLimiter}
+		}
+	})
+
+	if entry.err != nil {
+		f.models.CompareAndDelete(spec, entry)
+		return nil, entry.err
+	}
+	return entry.model, nil
+}
+
+// Reset 清空缓存的模型
+func (f *ModelFactory) Reset() {
+	f.models.Range(func(key, _ interface{}) bool {
+		f.models.Delete(key)
+		return true
+	})
+}
+
+// newARKChatModel 根据配置文件创建ARK聊天模型，spec 中指定模型名称时覆盖 ark.model_name
+func newARKChatModel(ctx context.Context, spec ModelSpec) (*ark.ChatModel, error) {
+	// 从配置文件中获取API密钥和模型名称
+	arkAPIKey, err := GetARKAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("获取API密钥失败: %v", err)
+	}
+
+	arkModelName := spec.ModelName
+	if arkModelName == "" {
+		arkModelName, err = GetARKModelName()
+		if err != nil {
+			return nil, fmt.Errorf("获取模型名称失败: %v", err)
+		}
+	}
+
+	return ark.NewChatModel(ctx, &ark.ChatModelConfig{
+		APIKey:      arkAPIKey,
+		Model:       arkModelName,
+		Temperature: Of(spec.Temperature),
+	})
+}
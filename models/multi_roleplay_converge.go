@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 提前结束讨论的默认设置
+const (
+	defaultEarlyStopMinRounds  = 2   // 至少进行的讨论轮数，之后才检查是否收敛
+	defaultEarlyStopSimilarity = 0.6 // 本轮发言与之前发言的平均相似度达到该值时直接视为收敛
+)
+
+// EarlyStopResult 开启提前结束时讨论的实际轮数，RoundsSaved 为少进行的轮数
+type EarlyStopResult struct {
+	Stopped         bool   `json:"stopped"`
+	Reason          string `json:"reason,omitempty"`
+	RoundsRequested int    `json:"rounds_requested"`
+	RoundsCompleted int    `json:"rounds_completed"`
+	RoundsSaved     int    `json:"rounds_saved"`
+}
+
+// GetEarlyStopSimilarity 获取判定发言重复的相似度阈值，未配置或不在 0-1 之间时使用默认值
+func GetEarlyStopSimilarity() float64 {
+	cfg, err := LoadConfig()
+	if err != nil || cfg.MultiRoleplay.EarlyStopSimilarity <= 0 || cfg.MultiRoleplay.EarlyStopSimilarity > 1 {
+		return defaultEarlyStopSimilarity
+	}
+	return cfg.MultiRoleplay.EarlyStopSimilarity
+}
+
+// checkDiscussionConverged 判断第 round 轮讨论后是否已没有新的实质内容。
+// 先比较本轮专家发言与之前所有发言的相似度，重复度高时直接结束，不再调用模型；
+// 否则由模型判断本轮是否带来了新的观点或信息。模型调用失败时视为未收敛，继续讨论
+func checkDiscussionConverged(ctx context.Context, messages []DiscussionMessage, round int, hostName string) (bool, string) {
+	similarity := roundSimilarity(messages, round, hostName)
+	if threshold := GetEarlyStopSimilarity(); similarity >= threshold {
+		return true, fmt.Sprintf("本轮发言与之前的发言高度重复（相似度 %.2f）", similarity)
+	}
+
+	converged, reason, err := judgeDiscussionConverged(ctx, messages, round)
+	if err != nil {
+		Logf(ctx, "判断讨论是否收敛失败，继续讨论: %v\n", err)
+		return false, ""
+	}
+	return converged, reason
+}
+
+// roundSimilarity 计算第 round 轮每位专家的发言与之前所有非系统发言的最大相似度，返回平均值。
+// 主持人的发言通常是总结和点名，不参与比较
+func roundSimilarity(messages []DiscussionMessage, round int, hostName string) float64 {
+	var previous []map[uint64]struct{}
+	var current []map[uint64]struct{}
+	for _, msg := range messages {
+		if msg.IsSystem || msg.Round > round {
+			continue
+		}
+		shingles := dedupShingles(normalizeForDedup(msg.Content))
+		switch {
+		case msg.Round < round:
+			previous = append(previous, shingles)
+		case msg.Role != hostName:
+			current = append(current, shingles)
+		}
+	}
+	if len(previous) == 0 || len(current) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, shingles := range current {
+		best := 0.0
+		for _, earlier := range previous {
+			if similarity := jaccardSimilarity(shingles, earlier); similarity > best {
+				best = similarity
+			}
+		}
+		total += best
+	}
+	return total / float64(len(current))
+}
+
+// judgeDiscussionConverged 调用模型判断第 round 轮讨论相比之前是否还有新的观点、信息或进展
+func judgeDiscussionConverged(ctx context.Context, messages []DiscussionMessage, round int) (bool, string, error) {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureRoleplay, 0) // 判断结果需要稳定
+	if err != nil {
+		return false, "", fmt.Errorf("创建聊天模型失败: %v", err)
+	}
+
+	var earlier, latest strings.Builder
+	for _, msg := range messages {
+		if msg.IsSystem {
+			continue
+		}
+		line := fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content)
+		if msg.Round == round {
+			latest.WriteString(line)
+		} else {
+			earlier.WriteString(line)
+		}
+	}
+
+	systemPrompt := `你负责判断一场多人讨论是否已经收敛。请比较"最新一轮发言"和"之前的讨论"：
+如果最新一轮只是重复、附和或换种说法复述之前的观点，没有提出新的观点、信息、分歧或行动建议，则认为讨论已收敛。
+
+以下是你必须返回的JSON格式（不要输出其他内容）：
+{
+  "converged": true 或 false,
+  "reason": "不超过30字的理由"
+}`
+
+	promptMessages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(fmt.Sprintf("之前的讨论:\n%s\n最新一轮发言:\n%s", earlier.String(), latest.String())),
+	}
+
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	response, err := chatModel.Generate(callCtx, promptMessages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return false, "", err
+	}
+
+	var result struct {
+		Converged bool   `json:"converged"`
+		Reason    string `json:"reason"`
+	}
+	if err := parseJSONObject(response.Content, &result); err != nil {
+		return false, "", fmt.Errorf("解析判断结果失败: %v", err)
+	}
+	return result.Converged, strings.TrimSpace(result.Reason), nil
+}
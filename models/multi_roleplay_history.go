@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// defaultMultiRoleplayHistoryWindow 多角色扮演中保留原文的最近发言条数的默认值
+const defaultMultiRoleplayHistoryWindow = 12
+
+// discussionHistory 多角色扮演的讨论上下文：最近 window 条发言保留原文，
+// 更早的发言合并为一段滚动摘要，避免轮数和专家较多时上下文超出模型限制
+type discussionHistory struct {
+	window     int
+	summary    string // 已移出窗口的发言的摘要
+	summarized int    // 已合并到摘要中的发言条数
+}
+
+// newDiscussionHistory 创建讨论上下文，window 小于1时使用1
+func newDiscussionHistory(window int) *discussionHistory {
+	if window < 1 {
+		window = 1
+	}
+	return &discussionHistory{window: window}
+}
+
+// Update 根据目前为止的全部讨论消息更新上下文并返回下一轮使用的消息。
+// 超出窗口的发言会合并到摘要中，生成摘要失败时丢弃这些发言并保留原有摘要，不影响讨论继续
+func (h *discussionHistory) Update(ctx context.Context, messages []DiscussionMessage, hostName string, meetingInfo string) []*schema.Message {
+	var turns []DiscussionMessage
+	for _, msg := range messages {
+		if !msg.IsSystem && msg.Round > 0 {
+			turns = append(turns, msg)
+		}
+	}
+
+	if evict := len(turns) - h.window; evict > h.summarized {
+		summary, err := summarizeDiscussionTurns(ctx, h.summary, turns[h.summarized:evict], meetingInfo)
+		if err != nil {
+			Logf(ctx, "生成讨论历史摘要失败，丢弃较早的发言: %v\n", err)
+		} else {
+			h.summary = summary
+		}
+		h.summarized = evict
+	}
+
+	var result []*schema.Message
+	if h.summary != "" {
+		result = append(result, schema.UserMessage("此前讨论的摘要:\n"+h.summary))
+	}
+	return append(result, discussionTurnMessages(turns[h.summarized:], hostName)...)
+}
+
+// discussionTurnMessages 将发言转换为模型上下文。
+// 主持人发言作为 assistant 消息，专家发言作为带发言人前缀的 user 消息
+func discussionTurnMessages(turns []DiscussionMessage, hostName string) []*schema.Message {
+	result := make([]*schema.Message, 0, len(turns))
+	for _, msg := range turns {
+		if msg.Role == hostName {
+			result = append(result, schema.AssistantMessage(msg.Content, nil))
+		} else {
+			result = append(result, schema.UserMessage(fmt.Sprintf("%s: %s", msg.Role, msg.Content)))
+		}
+	}
+	return result
+}
+
+// summarizeDiscussionTurns 将已有摘要和移出窗口的发言合并为新的摘要
+func summarizeDiscussionTurns(ctx context.Context, previous string, turns []DiscussionMessage, meetingInfo string) (string, error) {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureRoleplay, 0.3)
+	if err != nil {
+		return "", fmt.Errorf("创建聊天模型失败: %v", err)
+	}
+
+	var content strings.Builder
+	content.WriteString("会议背景信息:\n")
+	content.WriteString(meetingInfo)
+	if previous != "" {
+		content.WriteString("\n\n已有摘要:\n")
+		content.WriteString(previous)
+	}
+	content.WriteString("\n\n新的发言:\n")
+	for _, msg := range turns {
+		content.WriteString(fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content))
+	}
+
+	systemPrompt := `请将已有摘要和新的发言合并为一段新的讨论摘要，供后续讨论参考。
+摘要应按发言人概括各自的主要观点、提出的问题和达成的共识，保留关键的数据和结论，不要评价，长度控制在300字以内。只输出摘要内容。`
+
+	response, err := chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(content.String()),
+	})
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(response.Content), nil
+}
@@ -5,14 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/schema"
-	"github.com/hertz-contrib/sse"
 )
 
 // MultiRoleplayRequest 多角色扮演会议请求
@@ -22,6 +18,78 @@ type MultiRoleplayRequest struct {
 	Specialists []string `json:"specialists"`
 	Rounds      int      `json:"rounds"`
 	Topic       string   `json:"topic"`
+	Temperature *float32 `json:"temperature,omitempty"` // 主持人和专家发言的温度，0-1，默认0.7
+	// 为 true 时每轮结束后检查讨论是否已没有新内容，收敛后不再进行剩余轮次
+	EarlyStop bool `json:"early_stop,omitempty"`
+	MinRounds int  `json:"min_rounds,omitempty"` // 开启提前结束时至少进行的轮数，默认2，不超过 rounds
+	// 讨论总结的格式: narrative（默认）、action_items 或 decision_log
+	SummaryStyle string `json:"summary_style,omitempty"`
+}
+
+// defaultMultiRoleplayRounds 未指定轮数时的默认讨论轮数
+const defaultMultiRoleplayRounds = 3
+
+// NormalizeMultiRoleplayRequest 校验并规范化多角色扮演请求：去除重复和空白的专家，
+// 未指定轮数时使用默认值，超出配置的轮数或专家人数上限时返回错误
+func NormalizeMultiRoleplayRequest(req *MultiRoleplayRequest) error {
+	req.MeetingID = strings.TrimSpace(req.MeetingID)
+	req.Host = strings.TrimSpace(req.Host)
+
+	if req.MeetingID == "" {
+		return fmt.Errorf("meeting_id 是必需的")
+	}
+	if req.Host == "" {
+		return fmt.Errorf("host 是必需的")
+	}
+
+	seen := make(map[string]bool)
+	specialists := make([]string, 0, len(req.Specialists))
+	for _, name := range req.Specialists {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if name == req.Host {
+			return fmt.Errorf("专家 %s 不能与主持人相同", name)
+		}
+		seen[name] = true
+		specialists = append(specialists, name)
+	}
+	req.Specialists = specialists
+
+	if len(req.Specialists) == 0 {
+		return fmt.Errorf("至少需要一名专家参与者")
+	}
+
+	maxRounds, maxSpecialists := GetMultiRoleplayLimits()
+	if len(req.Specialists) > maxSpecialists {
+		return fmt.Errorf("专家人数不能超过 %d 人，当前为 %d 人", maxSpecialists, len(req.Specialists))
+	}
+
+	if req.Rounds <= 0 {
+		req.Rounds = defaultMultiRoleplayRounds
+	}
+	if req.Rounds > maxRounds {
+		return fmt.Errorf("讨论轮数不能超过 %d 轮，当前为 %d 轮", maxRounds, req.Rounds)
+	}
+
+	if req.MinRounds < 0 {
+		return fmt.Errorf("min_rounds 不能为负数")
+	}
+	if req.MinRounds > req.Rounds {
+		return fmt.Errorf("min_rounds 不能超过讨论轮数 %d，当前为 %d", req.Rounds, req.MinRounds)
+	}
+	if req.MinRounds == 0 {
+		req.MinRounds = min(defaultEarlyStopMinRounds, req.Rounds)
+	}
+
+	style, err := normalizeSummaryStyle(req.SummaryStyle)
+	if err != nil {
+		return err
+	}
+	req.SummaryStyle = style
+
+	return ValidatePersonaTemperature(req.Temperature)
 }
 
 // DiscussionMessage 讨论消息
@@ -29,20 +97,68 @@ type DiscussionMessage struct {
 	Role     string `json:"role"`
 	Content  string `json:"content"`
 	IsSystem bool   `json:"is_system"`
+	Round    int    `json:"round,omitempty"` // 所属讨论轮次，从1开始；开场和总结消息为0
 }
 
 // MultiRoleplayResponse 多角色扮演会议响应
 type MultiRoleplayResponse struct {
+	ID       string              `json:"id,omitempty"` // 讨论记录ID，保存失败时为空
 	Messages []DiscussionMessage `json:"messages"`
 	Summary  string              `json:"summary"`
+	// 请求开启提前结束时返回实际进行的轮数和节省的轮数
+	EarlyStop *EarlyStopResult `json:"early_stop,omitempty"`
 }
 
 // LogCallbackHandler 记录agent消息的处理器
 type LogCallbackHandler struct {
 	Messages     []DiscussionMessage
 	messagesLock sync.Mutex
-	Stream       *sse.Stream
+	Stream       EventPublisher
 	AgentNameMap map[string]string
+	round        int // 当前讨论轮次，由 StartRound 设置
+}
+
+// StartRound 标记新一轮讨论开始，之后记录的消息都归属该轮次，并发送轮次开始的系统消息
+func (h *LogCallbackHandler) StartRound(round int) error {
+	h.messagesLock.Lock()
+	defer h.messagesLock.Unlock()
+
+	h.round = round
+	return h.appendLocked(StreamEventSystem, DiscussionMessage{
+		Role:     "系统",
+		Content:  fmt.Sprintf("【第%d轮讨论】", round),
+		IsSystem: true,
+	})
+}
+
+// SystemMessage 记录一条属于当前轮次的系统消息并推送
+func (h *LogCallbackHandler) SystemMessage(content string) error {
+	h.messagesLock.Lock()
+	defer h.messagesLock.Unlock()
+
+	return h.appendLocked(StreamEventSystem, DiscussionMessage{
+		Role:     "系统",
+		Content:  content,
+		IsSystem: true,
+	})
+}
+
+// appendLocked 记录消息并推送名为 event 的SSE事件，调用方需持有锁
+func (h *LogCallbackHandler) appendLocked(event string, message DiscussionMessage) error {
+	if message.Round == 0 {
+		message.Round = h.round
+	}
+	h.Messages = append(h.Messages, message)
+
+	if h.Stream == nil {
+		return nil
+	}
+
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return h.Stream.Publish(NewStreamEvent(event, jsonData))
 }
 
 // OnAgentMessage 处理Agent消息回调
@@ -58,31 +174,17 @@ func (h *LogCallbackHandler) OnAgentMessage(_ context.Context, msg *schema.Messa
 		roleName = actualName
 	}
 
+	event := StreamEventMessage
+	if msg.Role == schema.System {
+		event = StreamEventSystem
+	}
+
 	// 添加消息到列表
-	message := DiscussionMessage{
+	return h.appendLocked(event, DiscussionMessage{
 		Role:     roleName,
 		Content:  content,
 		IsSystem: msg.Role == schema.System,
-	}
-	h.Messages = append(h.Messages, message)
-
-	// 发送SSE事件
-	if h.Stream != nil {
-		jsonData, err := json.Marshal(message)
-		if err != nil {
-			return err
-		}
-
-		event := &sse.Event{
-			Data: jsonData,
-		}
-
-		if err := h.Stream.Publish(event); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	})
 }
 
 // OnAgentHandoff 处理Agent切换回调
@@ -95,30 +197,12 @@ func (h *LogCallbackHandler) OnAgentHandoff(_ context.Context, reason string, ta
 
 	h.messagesLock.Lock()
 	defer h.messagesLock.Unlock()
-	h.Messages = append(h.Messages, message)
-
-	// 发送SSE事件
-	if h.Stream != nil {
-		jsonData, err := json.Marshal(message)
-		if err != nil {
-			return err
-		}
-
-		event := &sse.Event{
-			Data: jsonData,
-		}
-
-		if err := h.Stream.Publish(event); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return h.appendLocked(StreamEventHandoff, message)
 }
 
 // Host 主持人代理
 type Host struct {
-	ChatModel    *ark.ChatModel
+	ChatModel    LLM
 	SystemPrompt string
 	Name         string
 }
@@ -126,7 +210,7 @@ type Host struct {
 // Specialist 专家代理
 type Specialist struct {
 	Name         string
-	ChatModel    *ark.ChatModel
+	ChatModel    LLM
 	SystemPrompt string
 }
 
@@ -158,6 +242,7 @@ func (ma *MultiAgent) Stream(ctx context.Context, messages []*schema.Message, cb
 		}, messages...)
 
 		hostResp, err := ma.Host.ChatModel.Generate(ctx, hostMessages)
+		recordLLMCall(ctx, hostResp, err)
 		if err != nil {
 			fmt.Fprintf(pw, "错误: %v", err)
 			return
@@ -172,16 +257,28 @@ func (ma *MultiAgent) Stream(ctx context.Context, messages []*schema.Message, cb
 		// 设置角色映射
 		cb.AgentNameMap[string(schema.Assistant)] = ma.Host.Name
 
-		// 记录主持人消息
-		cb.OnAgentMessage(ctx, hostMsg)
+		// 记录主持人消息，发送失败说明客户端已断开，停止后续发言
+		if err := cb.OnAgentMessage(ctx, hostMsg); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
 
 		// 更新消息列表
 		currentContext := append(messages, hostMsg)
 
 		// 专家依次发言
 		for _, specialist := range ma.Specialists {
+			// 请求已取消时不再调用模型
+			if err := ctx.Err(); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
 			// 通知切换到专家
-			cb.OnAgentHandoff(ctx, "轮到专家发言", specialist.Name)
+			if err := cb.OnAgentHandoff(ctx, "轮到专家发言", specialist.Name); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
 
 			// 专家提示
 			specialistPrompt := fmt.Sprintf("主持人%s邀请你(%s)发表意见。请根据主持人的提问，分享你的看法。",
@@ -204,16 +301,24 @@ func (ma *MultiAgent) Stream(ctx context.Context, messages []*schema.Message, cb
 
 			// 生成专家回复
 			specialistResp, err := specialist.ChatModel.Generate(ctx, specialistMessages)
+			recordLLMCall(ctx, specialistResp, err)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				pw.CloseWithError(ctxErr)
+				return
+			}
 			if err != nil {
 				errMsg := fmt.Sprintf("专家%s回复失败: %v", specialist.Name, err)
-				fmt.Fprintf(pw, errMsg)
+				fmt.Fprint(pw, errMsg)
 
 				specialistMsg := &schema.Message{
 					Role:    schema.Assistant,
 					Content: "（因技术原因，暂未收到回复）",
 				}
 
-				cb.OnAgentMessage(ctx, specialistMsg)
+				if err := cb.OnAgentMessage(ctx, specialistMsg); err != nil {
+					pw.CloseWithError(err)
+					return
+				}
 				continue
 			}
 
@@ -230,7 +335,10 @@ func (ma *MultiAgent) Stream(ctx context.Context, messages []*schema.Message, cb
 			}
 
 			// 记录专家消息
-			cb.OnAgentMessage(ctx, specialistMsg)
+			if err := cb.OnAgentMessage(ctx, specialistMsg); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
 
 			// 添加到当前上下文
 			currentContext = append(currentContext,
@@ -244,10 +352,14 @@ func (ma *MultiAgent) Stream(ctx context.Context, messages []*schema.Message, cb
 	return pr, nil
 }
 
-// ProcessMultiRoleplayMeeting 处理多角色扮演会议
-func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest, stream *sse.Stream) (*MultiRoleplayResponse, error) {
+// ProcessMultiRoleplayMeeting 处理多角色扮演会议，ctx 取消或流式发送失败时停止剩余轮次的生成
+func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest, stream EventPublisher) (*MultiRoleplayResponse, error) {
+	// 发送失败时取消尚未完成的模型调用
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// 获取会议内容
-	meetingContent, meetingInfo, err := getMeetingContent(req.MeetingID)
+	meetingContent, meetingInfo, err := getMeetingSpeakerContent(req.MeetingID)
 	if err != nil {
 		return nil, err
 	}
@@ -269,14 +381,11 @@ func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest,
 
 	if stream != nil {
 		jsonData, _ := json.Marshal(startMsg)
-		event := &sse.Event{
-			Data: jsonData,
-		}
-		stream.Publish(event)
+		stream.Publish(NewStreamEvent(StreamEventSystem, jsonData))
 	}
 
 	// 创建主持人代理
-	hostAgent, err := newHost(ctx, req.Host, meetingContent, meetingInfo, req.Specialists)
+	hostAgent, err := newHost(ctx, req.Host, meetingContent, meetingInfo, req.Specialists, req.Temperature)
 	if err != nil {
 		return nil, fmt.Errorf("创建主持人代理失败: %v", err)
 	}
@@ -284,7 +393,7 @@ func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest,
 	// 创建专家代理
 	specialists := make([]Specialist, 0, len(req.Specialists))
 	for _, name := range req.Specialists {
-		specialist, err := newSpecialist(ctx, name, meetingContent, meetingInfo, req.Host)
+		specialist, err := newSpecialist(ctx, name, meetingContent, meetingInfo, req.Host, req.Temperature)
 		if err != nil {
 			return nil, fmt.Errorf("创建专家代理 %s 失败: %v", name, err)
 		}
@@ -294,11 +403,26 @@ func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest,
 	// 创建多代理
 	multiAgent := NewMultiAgent(*hostAgent, specialists)
 
-	// 讨论历史
+	// 讨论历史，较早的发言合并为摘要
+	history := newDiscussionHistory(GetMultiRoleplayHistoryWindow())
 	discussionHistory := []*schema.Message{}
 
+	var earlyStop *EarlyStopResult
+	if req.EarlyStop {
+		earlyStop = &EarlyStopResult{RoundsRequested: req.Rounds}
+	}
+
 	// 进行指定轮数对话
 	for round := 0; round < req.Rounds; round++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// 标记轮次，下一轮据此取出本轮的全部发言作为上下文
+		if err := cb.StartRound(round + 1); err != nil {
+			return nil, fmt.Errorf("第%d轮对话中断: %w", round+1, err)
+		}
+
 		// 构建主持人指导消息
 		var hostPrompt string
 		if round == 0 {
@@ -330,19 +454,43 @@ func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest,
 			return nil, fmt.Errorf("第%d轮对话生成失败: %v", round+1, err)
 		}
 
-		io.Copy(io.Discard, out)
+		_, err = io.Copy(io.Discard, out)
 		out.Close()
+		if err != nil {
+			return nil, fmt.Errorf("第%d轮对话中断: %w", round+1, err)
+		}
 
+		if earlyStop != nil {
+			earlyStop.RoundsCompleted = round + 1
+		}
 		if round == req.Rounds-1 {
 			break
 		}
 
-		// 收集本轮消息
-		discussionHistory = collectRoundMessages(cb.Messages, req.Host, req.Specialists)
+		// 达到最少轮数后检查讨论是否收敛，收敛时跳过剩余轮次直接总结
+		if earlyStop != nil && round+1 >= req.MinRounds {
+			if converged, reason := checkDiscussionConverged(ctx, cb.Messages, round+1, req.Host); converged {
+				earlyStop.Stopped = true
+				earlyStop.Reason = reason
+				earlyStop.RoundsSaved = req.Rounds - earlyStop.RoundsCompleted
+				notice := fmt.Sprintf("【讨论已收敛，提前结束：%s。已进行%d/%d轮，节省%d轮】", reason, earlyStop.RoundsCompleted, req.Rounds, earlyStop.RoundsSaved)
+				if err := cb.SystemMessage(notice); err != nil {
+					return nil, fmt.Errorf("第%d轮对话中断: %w", round+1, err)
+				}
+				break
+			}
+		}
+
+		// 收集目前为止的发言作为下一轮上下文
+		discussionHistory = history.Update(ctx, cb.Messages, req.Host, meetingInfo)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// 生成总结
-	summary, err := generateDiscussionSummary(ctx, cb.Messages, meetingInfo)
+	summary, err := generateDiscussionSummary(ctx, cb.Messages, meetingInfo, req.SummaryStyle)
 	if err != nil {
 		return nil, fmt.Errorf("生成讨论总结失败: %v", err)
 	}
@@ -355,145 +503,70 @@ func ProcessMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest,
 	}
 	cb.Messages = append(cb.Messages, summaryMsg)
 
-	if stream != nil {
-		jsonData, _ := json.Marshal(summaryMsg)
-		event := &sse.Event{
-			Data: jsonData,
-		}
-		stream.Publish(event)
+	response := &MultiRoleplayResponse{
+		Messages:  cb.Messages,
+		Summary:   summary,
+		EarlyStop: earlyStop,
 	}
 
-	return &MultiRoleplayResponse{
-		Messages: cb.Messages,
-		Summary:  summary,
-	}, nil
-}
-
-// collectRoundMessages 收集本轮消息，转换为下一轮上下文
-func collectRoundMessages(messages []DiscussionMessage, hostName string, specialistNames []string) []*schema.Message {
-	var result []*schema.Message
-
-	// 计算当前轮次开始位置
-	startPos := 0
-	for i, msg := range messages {
-		if msg.IsSystem {
-			startPos = i + 1
-		}
+	// 保存讨论记录，保存失败不影响本次返回结果。先保存再推送总结，使流式客户端拿到记录ID
+	if id, err := SaveRoleplayDiscussion(req, response); err != nil {
+		Logf(ctx, "保存多角色扮演讨论记录失败: %v\n", err)
+	} else {
+		response.ID = id
 	}
 
-	// 收集非系统消息
-	for i := startPos; i < len(messages); i++ {
-		msg := messages[i]
-		if !msg.IsSystem {
-			var role schema.RoleType
-			if msg.Role == hostName {
-				role = schema.Assistant
-			} else {
-				role = schema.User
-			}
-
-			result = append(result, &schema.Message{
-				Role:    role,
-				Content: msg.Content,
-			})
-		}
+	if stream != nil {
+		jsonData, _ := json.Marshal(summaryEvent{DiscussionMessage: summaryMsg, DiscussionID: response.ID})
+		stream.Publish(NewStreamEvent(StreamEventSummary, jsonData))
 	}
 
-	return result
+	return response, nil
+}
+
+// summaryEvent 流式接口最后推送的讨论总结，附带讨论记录ID，用于之后查询讨论历史
+type summaryEvent struct {
+	DiscussionMessage
+	DiscussionID string `json:"discussion_id,omitempty"` // 讨论记录ID，保存失败时省略
 }
 
 // getMeetingContent 获取会议内容和元数据
 func getMeetingContent(meetingID string) (string, string, error) {
 	// 读取会议文件
-	storageDir := "./storage/meetings"
-	filePath := filepath.Join(storageDir, meetingID+".json")
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return "", "", fmt.Errorf("会议不存在")
-	}
-
-	data, err := os.ReadFile(filePath)
+	meetingData, err := LoadMeeting(meetingID)
 	if err != nil {
-		return "", "", fmt.Errorf("无法读取会议信息: %v", err)
-	}
-
-	// 解析JSON
-	var meetingData map[string]interface{}
-	if err := json.Unmarshal(data, &meetingData); err != nil {
-		return "", "", fmt.Errorf("无法解析会议数据: %v", err)
-	}
-
-	// 提取会议内容
-	var meetingContent string
-
-	if rawContent, ok := meetingData["raw_content"].(string); ok {
-		meetingContent = rawContent
-	} else if content, ok := meetingData["content"].(string); ok {
-		meetingContent = content
-	} else {
-		contentBytes, _ := json.MarshalIndent(meetingData, "", "  ")
-		meetingContent = string(contentBytes)
+		return "", "", err
 	}
 
 	// 提取会议元数据
-	meetingInfo := "会议信息:\n"
+	metadata, _ := GetMeetingMetadata(meetingData)
+	meetingInfo := metadata.Describe()
 
-	if metadata, ok := meetingData["metadata"].(map[string]interface{}); ok {
-		if title, ok := metadata["title"].(string); ok && title != "" {
-			meetingInfo += "标题: " + title + "\n"
-		}
-
-		if description, ok := metadata["description"].(string); ok && description != "" {
-			meetingInfo += "描述: " + description + "\n"
-		}
-
-		if participants, ok := metadata["participants"].([]interface{}); ok && len(participants) > 0 {
-			meetingInfo += "参会人员: "
-			for i, p := range participants {
-				if i > 0 {
-					meetingInfo += ", "
-				}
-				if pStr, ok := p.(string); ok {
-					meetingInfo += pStr
-				}
-			}
-			meetingInfo += "\n"
-		}
-
-		if startTime, ok := metadata["start_time"].(string); ok && startTime != "" {
-			meetingInfo += "开始时间: " + startTime + "\n"
-		}
-		if endTime, ok := metadata["end_time"].(string); ok && endTime != "" {
-			meetingInfo += "结束时间: " + endTime + "\n"
-		}
-
-		if summary, ok := metadata["summary"].(string); ok && summary != "" {
-			meetingInfo += "摘要: " + summary + "\n"
-		}
-	}
-
-	return meetingContent, meetingInfo, nil
+	return MeetingDataContent(meetingData), meetingInfo, nil
 }
 
-// newHost 创建主持人代理
-func newHost(ctx context.Context, hostName string, meetingContent string, meetingInfo string, specialists []string) (*Host, error) {
-	// 获取API配置
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		return nil, fmt.Errorf("获取API密钥失败: %v", err)
+// MeetingDataContent 提取会议原始内容，旧版本的会议文件没有 raw_content 时使用 content 或整个会议JSON
+func MeetingDataContent(meetingData map[string]interface{}) string {
+	if content, ok := meetingRawContent(meetingData); ok {
+		return content
 	}
+	contentBytes, _ := json.MarshalIndent(meetingData, "", "  ")
+	return string(contentBytes)
+}
 
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		return nil, fmt.Errorf("获取模型名称失败: %v", err)
+// meetingRawContent 返回会议的原始内容，兼容只有 content 字段的旧格式，两个字段都没有时 ok 为 false
+func meetingRawContent(meetingData map[string]interface{}) (string, bool) {
+	if rawContent, ok := meetingData["raw_content"].(string); ok {
+		return rawContent, true
 	}
+	content, ok := meetingData["content"].(string)
+	return content, ok
+}
 
+// newHost 创建主持人代理，temperature 为 nil 时使用默认温度
+func newHost(ctx context.Context, hostName string, meetingContent string, meetingInfo string, specialists []string, temperature *float32) (*Host, error) {
 	// 创建聊天模型
-	chatModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.7)),
-	})
+	chatModel, err := GetPersonaChatModel(ctx, temperature)
 	if err != nil {
 		return nil, fmt.Errorf("创建聊天模型失败: %v", err)
 	}
@@ -529,19 +602,8 @@ func newHost(ctx context.Context, hostName string, meetingContent string, meetin
 	}, nil
 }
 
-// newSpecialist 创建专家参会者代理
-func newSpecialist(ctx context.Context, specialistName string, meetingContent string, meetingInfo string, hostName string) (Specialist, error) {
-	// 获取API配置
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		return Specialist{}, fmt.Errorf("获取API密钥失败: %v", err)
-	}
-
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		return Specialist{}, fmt.Errorf("获取模型名称失败: %v", err)
-	}
-
+// newSpecialist 创建专家参会者代理，temperature 为 nil 时使用默认温度
+func newSpecialist(ctx context.Context, specialistName string, meetingContent string, meetingInfo string, hostName string, temperature *float32) (Specialist, error) {
 	// 创建代理系统提示
 	systemPrompt := fmt.Sprintf(`你是会议参会者%s，在会议中扮演你自己的角色。
 
@@ -565,11 +627,7 @@ func newSpecialist(ctx context.Context, specialistName string, meetingContent st
 		specialistName, meetingInfo, meetingContent, hostName, specialistName)
 
 	// 创建聊天模型
-	chatModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.7)),
-	})
+	chatModel, err := GetPersonaChatModel(ctx, temperature)
 	if err != nil {
 		return Specialist{}, fmt.Errorf("创建聊天模型失败: %v", err)
 	}
@@ -581,25 +639,10 @@ func newSpecialist(ctx context.Context, specialistName string, meetingContent st
 	}, nil
 }
 
-// generateDiscussionSummary 生成讨论总结
-func generateDiscussionSummary(ctx context.Context, messages []DiscussionMessage, meetingInfo string) (string, error) {
-	// 获取API配置
-	arkAPIKey, err := GetARKAPIKey()
-	if err != nil {
-		return "", fmt.Errorf("获取API密钥失败: %v", err)
-	}
-
-	arkModelName, err := GetARKModelName()
-	if err != nil {
-		return "", fmt.Errorf("获取模型名称失败: %v", err)
-	}
-
+// generateDiscussionSummary 按 style 指定的格式生成讨论总结，提示来自 discussion_summary 提示模板
+func generateDiscussionSummary(ctx context.Context, messages []DiscussionMessage, meetingInfo string, style string) (string, error) {
 	// 创建聊天模型
-	chatModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:      arkAPIKey,
-		Model:       arkModelName,
-		Temperature: Of(float32(0.4)),
-	})
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureRoleplay, 0.4)
 	if err != nil {
 		return "", fmt.Errorf("创建聊天模型失败: %v", err)
 	}
@@ -617,14 +660,10 @@ func generateDiscussionSummary(ctx context.Context, messages []DiscussionMessage
 	}
 
 	// 系统提示
-	systemPrompt := `作为专业会议纪要专家，请对提供的会议讨论内容进行总结。总结应包括：
-1. 讨论的主要话题和议题
-2. 各方观点的概述
-3. 达成的共识或结论
-4. 需要进一步讨论的问题
-5. 确定的下一步行动项目
-
-总结应该清晰、简洁、客观，长度控制在300-500字之间。请以第三人称编写，不要添加个人评价。`
+	systemPrompt, err := renderDiscussionSummaryPrompt(style)
+	if err != nil {
+		return "", err
+	}
 
 	// 准备消息
 	promptMessages := []*schema.Message{
@@ -634,6 +673,7 @@ func generateDiscussionSummary(ctx context.Context, messages []DiscussionMessage
 
 	// 生成回答
 	response, err := chatModel.Generate(ctx, promptMessages)
+	recordLLMCall(ctx, response, err)
 	if err != nil {
 		return "", fmt.Errorf("生成总结失败: %v", err)
 	}
@@ -642,13 +682,12 @@ func generateDiscussionSummary(ctx context.Context, messages []DiscussionMessage
 }
 
 // PerformMultiRoleplayMeeting 执行多角色扮演会议并返回结果
-func PerformMultiRoleplayMeeting(req *MultiRoleplayRequest) (*MultiRoleplayResponse, error) {
-	ctx := context.Background()
+func PerformMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest) (*MultiRoleplayResponse, error) {
 	return ProcessMultiRoleplayMeeting(ctx, req, nil)
 }
 
 // StreamMultiRoleplayMeeting 执行多角色扮演会议并流式返回结果
-func StreamMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest, stream *sse.Stream) error {
+func StreamMultiRoleplayMeeting(ctx context.Context, req *MultiRoleplayRequest, stream EventPublisher) error {
 	_, err := ProcessMultiRoleplayMeeting(ctx, req, stream)
 	return err
 }
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestLogCallbackHandlerRounds(t *testing.T) {
+	const host = "主持人"
+	specialists := []string{"张三", "李四", "王五"}
+
+	publisher := &recordingPublisher{}
+	cb := &LogCallbackHandler{
+		Messages:     []DiscussionMessage{},
+		Stream:       publisher,
+		AgentNameMap: make(map[string]string),
+	}
+	// 开场消息在第一轮开始前记录，不属于任何轮次
+	cb.Messages = append(cb.Messages, DiscussionMessage{Role: "系统", Content: "【会议扩展讨论开始】", IsSystem: true})
+
+	// 按 MultiAgent.Stream 的顺序记录两轮讨论：主持人发言，然后依次切换到每位专家发言。
+	// 第2轮开始前按 ProcessMultiRoleplayMeeting 的方式取出交给第2轮代理的讨论上下文
+	ctx := context.Background()
+	history := newDiscussionHistory(defaultMultiRoleplayHistoryWindow)
+	var round2Context []*schema.Message
+	for round := 1; round <= 2; round++ {
+		if round == 2 {
+			round2Context = history.Update(ctx, cb.Messages, host, "预算评审会")
+		}
+		if err := cb.StartRound(round); err != nil {
+			t.Fatalf("StartRound(%d) 失败: %v", round, err)
+		}
+		cb.AgentNameMap[string(schema.Assistant)] = host
+		if err := cb.OnAgentMessage(ctx, schema.AssistantMessage(fmt.Sprintf("第%d轮，请各位发言", round), nil)); err != nil {
+			t.Fatalf("记录主持人发言失败: %v", err)
+		}
+		for _, name := range specialists {
+			if err := cb.OnAgentHandoff(ctx, "轮到专家发言", name); err != nil {
+				t.Fatalf("记录切换失败: %v", err)
+			}
+			cb.AgentNameMap[string(schema.Assistant)] = name
+			if err := cb.OnAgentMessage(ctx, schema.AssistantMessage(fmt.Sprintf("%s的第%d轮发言", name, round), nil)); err != nil {
+				t.Fatalf("记录专家发言失败: %v", err)
+			}
+		}
+	}
+
+	// 每轮依次为轮次标题、主持人发言，以及每位专家的切换消息和发言
+	const perRound = 2 + 2*3
+	if len(cb.Messages) != 1+2*perRound {
+		t.Fatalf("记录了 %d 条消息，期望 %d", len(cb.Messages), 1+2*perRound)
+	}
+	if cb.Messages[0].Round != 0 {
+		t.Errorf("开场消息的轮次 = %d，期望 0", cb.Messages[0].Round)
+	}
+	for round := 1; round <= 2; round++ {
+		messages := cb.Messages[1+(round-1)*perRound : 1+round*perRound]
+		for _, msg := range messages {
+			if msg.Round != round {
+				t.Errorf("消息 %q 的轮次 = %d，期望 %d", msg.Content, msg.Round, round)
+			}
+		}
+		if want := fmt.Sprintf("【第%d轮讨论】", round); messages[0].Content != want || !messages[0].IsSystem {
+			t.Errorf("第%d轮的第一条消息 = %+v，期望轮次标题 %q", round, messages[0], want)
+		}
+		if messages[1].Role != host {
+			t.Errorf("第%d轮的第二条消息由 %q 发出，期望主持人", round, messages[1].Role)
+		}
+		for i, name := range specialists {
+			handoff, speech := messages[2+2*i], messages[3+2*i]
+			if handoff.Content != fmt.Sprintf("【%s 将继续发言】", name) || !handoff.IsSystem {
+				t.Errorf("第%d轮切换消息 = %+v，期望切换到 %s", round, handoff, name)
+			}
+			if speech.Role != name || speech.Content != fmt.Sprintf("%s的第%d轮发言", name, round) || speech.IsSystem {
+				t.Errorf("第%d轮发言 = %+v，期望 %s 的发言", round, speech, name)
+			}
+		}
+	}
+
+	// 第2轮的代理只收到第1轮的发言：主持人发言为 assistant 消息，专家发言为带发言人前缀的 user 消息，
+	// 开场、轮次标题和切换等系统消息不进入上下文
+	wantContext := []*schema.Message{schema.AssistantMessage("第1轮，请各位发言", nil)}
+	for _, name := range specialists {
+		wantContext = append(wantContext, schema.UserMessage(fmt.Sprintf("%s: %s的第1轮发言", name, name)))
+	}
+	if len(round2Context) != len(wantContext) {
+		t.Fatalf("第2轮收到 %d 条上下文消息，期望 %d: %v", len(round2Context), len(wantContext), round2Context)
+	}
+	for i, msg := range round2Context {
+		if msg.Role != wantContext[i].Role || msg.Content != wantContext[i].Content {
+			t.Errorf("第2轮上下文第%d条 = %s %q，期望 %s %q", i+1, msg.Role, msg.Content, wantContext[i].Role, wantContext[i].Content)
+		}
+	}
+
+	// 发言记录按轮次分段，供分页查看
+	discussion := &RoleplayDiscussion{Messages: cb.Messages}
+	wantRounds := []DiscussionRound{{Round: 1, Offset: 1, Count: perRound}, {Round: 2, Offset: 1 + perRound, Count: perRound}}
+	if got := discussion.RoundBoundaries(); !reflect.DeepEqual(got, wantRounds) {
+		t.Errorf("RoundBoundaries() = %+v，期望 %+v", got, wantRounds)
+	}
+	if page, total := discussion.MessagesPage(2, 0, 0); total != perRound || page[0].Content != "【第2轮讨论】" {
+		t.Errorf("第2轮共 %d 条消息，第一条为 %q", total, page[0].Content)
+	}
+
+	// 推送的事件与记录的消息一致，带有所属轮次
+	events := publisher.data()
+	if len(events) != 2*perRound {
+		t.Fatalf("推送了 %d 个事件，期望 %d", len(events), 2*perRound)
+	}
+	for i, data := range events {
+		var msg DiscussionMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatalf("解析事件失败: %v", err)
+		}
+		if !reflect.DeepEqual(msg, cb.Messages[i+1]) {
+			t.Errorf("第%d个事件 = %+v，期望 %+v", i+1, msg, cb.Messages[i+1])
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// 支持的推送渠道
+const (
+	NotifierFeiShu     = "feishu"
+	NotifierWeChatWork = "wechat_work"
+	NotifierSlack      = "slack"
+)
+
+// Notifier 消息推送渠道的统一接口
+type Notifier interface {
+	// Name 返回渠道名称
+	Name() string
+	// SendReport 推送会议报告
+	SendReport(report *MeetingReport) error
+	// SendText 推送一条带标题的简单文本通知
+	SendText(title, content string) error
+	// BuildReportMessages 返回推送会议报告时依次发送的消息体，不发送任何请求，用于预览
+	BuildReportMessages(report *MeetingReport) []interface{}
+}
+
+// NewNotifier 根据渠道名称创建推送渠道，渠道为空时默认使用飞书
+func NewNotifier(channel string) (Notifier, error) {
+	switch channel {
+	case "", NotifierFeiShu:
+		return &FeiShuNotifier{}, nil
+	case NotifierWeChatWork:
+		return &WeChatWorkNotifier{}, nil
+	case NotifierSlack:
+		return &SlackNotifier{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的推送渠道: %s", channel)
+	}
+}
+
+// PushMeetingReport 根据会议ID创建报告并通过指定渠道推送
+func PushMeetingReport(meetingID string, notifier Notifier) error {
+	// 创建会议报告
+	report, err := CreateMeetingReport(meetingID)
+	if err != nil {
+		return fmt.Errorf("创建会议报告失败: %v", err)
+	}
+
+	// 发送报告
+	if err := notifier.SendReport(report); err != nil {
+		return fmt.Errorf("发送报告到%s失败: %v", notifier.Name(), err)
+	}
+
+	return nil
+}
+
+// ReportOptions 生成会议报告时的可选内容
+type ReportOptions struct {
+	Color        string // 飞书卡片标题颜色，需先经 NormalizeFeiShuCardColor 校验
+	IncludeScore bool   // 是否附带会议评分，评分结果会被缓存
+	// CachedScoreOnly 附带评分时只使用缓存的评分，没有缓存时不调用模型，报告不附带评分
+	CachedScoreOnly bool
+}
+
+// CreateMeetingReportWithOptions 根据会议ID和选项创建会议报告
+func CreateMeetingReportWithOptions(ctx context.Context, meetingID string, opts ReportOptions) (*MeetingReport, error) {
+	report, err := CreateMeetingReport(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	report.CardColor = opts.Color
+
+	if opts.IncludeScore {
+		var score *MeetingScore
+		if opts.CachedScoreOnly {
+			score, err = LoadCachedMeetingScore(ctx, meetingID)
+		} else {
+			score, _, err = GetCachedMeetingScore(ctx, meetingID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Score = score
+	}
+
+	return report, nil
+}
+
+// FeiShuNotifier 飞书群机器人推送
+type FeiShuNotifier struct{}
+
+// Name 返回渠道名称
+func (n *FeiShuNotifier) Name() string {
+	return NotifierFeiShu
+}
+
+// SendReport 推送会议报告到飞书
+func (n *FeiShuNotifier) SendReport(report *MeetingReport) error {
+	return SendMeetingReportToFeiShu(report)
+}
+
+// BuildReportMessages 返回推送到飞书的会议报告卡片
+func (n *FeiShuNotifier) BuildReportMessages(report *MeetingReport) []interface{} {
+	return []interface{}{BuildFeiShuReportMessage(report)}
+}
+
+// SendText 推送文本通知到飞书
+func (n *FeiShuNotifier) SendText(title, content string) error {
+	webhookURL, err := GetFeiShuWebhookURL()
+	if err != nil {
+		return fmt.Errorf("获取飞书Webhook URL失败: %v", err)
+	}
+
+	message := FeiShuMessage{
+		MsgType: "interactive",
+		Card: Card{
+			Header: Header{
+				Title: Title{
+					Content: title,
+					Tag:     "plain_text",
+				},
+				Template: "blue",
+			},
+			Elements: []Element{
+				{
+					Tag: "div",
+					Text: &Text{
+						Content: content,
+						Tag:     "lark_md",
+					},
+				},
+			},
+		},
+	}
+
+	return postWebhookJSON(webhookURL, message)
+}
+
+// postWebhookJSON 将消息序列化为JSON并POST到Webhook地址
+func postWebhookJSON(webhookURL string, message interface{}) error {
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(messageJSON))
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("返回错误状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
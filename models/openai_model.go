@@ -0,0 +1,229 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// defaultOpenAIBaseURL 未配置 openai.base_url 时使用的接口地址
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// openAIChatModel 调用 OpenAI 兼容的 /chat/completions 接口，也可用于本地部署的兼容服务
+type openAIChatModel struct {
+	client      *http.Client
+	baseURL     string
+	apiKey      string
+	model       string
+	temperature float32
+}
+
+// openAIMessage 请求和响应中的一条消息
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	Name    string `json:"name,omitempty"`
+}
+
+// openAIRequest /chat/completions 请求体
+type openAIRequest struct {
+	Model         string               `json:"model"`
+	Messages      []openAIMessage      `json:"messages"`
+	Temperature   *float32             `json:"temperature,omitempty"`
+	MaxTokens     *int                 `json:"max_tokens,omitempty"`
+	TopP          *float32             `json:"top_p,omitempty"`
+	Stop          []string             `json:"stop,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+// openAIStreamOptions 流式请求参数，include_usage 使最后一个分片携带token用量
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIResponse /chat/completions 响应体，流式分片使用 Delta，非流式使用 Message
+type openAIResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		Delta        openAIMessage `json:"delta"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *schema.TokenUsage `json:"usage"`
+}
+
+// newOpenAIChatModel 根据配置文件创建 OpenAI 兼容的聊天模型，spec 中指定模型名称时覆盖 openai.model_name
+func newOpenAIChatModel(spec ModelSpec) (*openAIChatModel, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	modelName := spec.ModelName
+	if modelName == "" {
+		modelName = cfg.OpenAI.ModelName
+	}
+	if modelName == "" {
+		return nil, fmt.Errorf("OpenAI模型名称未配置")
+	}
+
+	baseURL := strings.TrimRight(cfg.OpenAI.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	return &openAIChatModel{
+		client:      &http.Client{},
+		baseURL:     baseURL,
+		apiKey:      cfg.OpenAI.APIKey,
+		model:       modelName,
+		temperature: spec.Temperature,
+	}, nil
+}
+
+// Generate 生成完整回复
+func (m *openAIChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	resp, err := m.post(ctx, m.buildRequest(input, false, opts))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析OpenAI响应失败: %v", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("OpenAI响应中没有回复")
+	}
+
+	choice := result.Choices[0]
+	return &schema.Message{
+		Role:    schema.Assistant,
+		Content: choice.Message.Content,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: choice.FinishReason,
+			Usage:        result.Usage,
+		},
+	}, nil
+}
+
+// Stream 流式生成回复，读取方关闭流后停止读取并断开连接
+func (m *openAIChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	resp, err := m.post(ctx, m.buildRequest(input, true, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer resp.Body.Close()
+		defer sw.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				return
+			}
+
+			var chunk openAIResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				sw.Send(nil, fmt.Errorf("解析OpenAI流式响应失败: %v", err))
+				return
+			}
+
+			msg := &schema.Message{Role: schema.Assistant}
+			if len(chunk.Choices) > 0 {
+				msg.Content = chunk.Choices[0].Delta.Content
+				msg.ResponseMeta = &schema.ResponseMeta{FinishReason: chunk.Choices[0].FinishReason}
+			}
+			if chunk.Usage != nil {
+				if msg.ResponseMeta == nil {
+					msg.ResponseMeta = &schema.ResponseMeta{}
+				}
+				msg.ResponseMeta.Usage = chunk.Usage
+			}
+
+			if closed := sw.Send(msg, nil); closed {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			sw.Send(nil, fmt.Errorf("读取OpenAI流式响应失败: %v", err))
+		}
+	}()
+
+	return sr, nil
+}
+
+// buildRequest 组装请求体，调用时传入的选项覆盖默认的模型和温度
+func (m *openAIChatModel) buildRequest(input []*schema.Message, stream bool, opts []model.Option) *openAIRequest {
+	options := model.GetCommonOptions(&model.Options{
+		Model:       &m.model,
+		Temperature: &m.temperature,
+	}, opts...)
+
+	messages := make([]openAIMessage, 0, len(input))
+	for _, msg := range input {
+		messages = append(messages, openAIMessage{
+			Role:    string(msg.Role),
+			Content: msg.Content,
+			Name:    msg.Name,
+		})
+	}
+
+	req := &openAIRequest{
+		Model:       *options.Model,
+		Messages:    messages,
+		Temperature: options.Temperature,
+		MaxTokens:   options.MaxTokens,
+		TopP:        options.TopP,
+		Stop:        options.Stop,
+		Stream:      stream,
+	}
+	if stream {
+		req.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+	return req
+}
+
+// post 发送请求，非200状态码时返回包含响应内容的错误
+func (m *openAIChatModel) post(ctx context.Context, body *openAIRequest) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("序列化OpenAI请求失败: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("创建OpenAI请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求OpenAI接口失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("OpenAI接口返回错误状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
+	}
+	return resp, nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"sync"
+)
+
+// mermaidArtifactKind 会议流程图在缓存中的类型
+const mermaidArtifactKind = "mermaid"
+
+// MeetingOverview 会议详情页需要的全部数据，流程图和评分优先使用缓存
+type MeetingOverview struct {
+	MeetingID     string            `json:"meeting_id"`
+	Title         string            `json:"title"`
+	Summary       string            `json:"summary"`
+	Participants  []string          `json:"participants"`
+	MermaidCode   string            `json:"mermaid_code"`
+	MermaidCached bool              `json:"mermaid_cached"`   // 流程图是否来自缓存
+	Score         *MeetingScore     `json:"score"`            // 评估失败时为 null
+	ScoreCached   bool              `json:"score_cached"`     // 评分是否来自缓存
+	Errors        map[string]string `json:"errors,omitempty"` // 生成失败的部分及原因
+}
+
+// GetCachedMeetingMermaid 获取会议流程图，会议内容未变化时使用缓存的流程图，返回值 cached 表示是否来自缓存
+func GetCachedMeetingMermaid(ctx context.Context, meetingID string) (string, bool, error) {
+	meetingContent, _, err := getMeetingContent(meetingID)
+	if err != nil {
+		return "", false, err
+	}
+
+	// 流程图只根据会议内容生成，内容变化后缓存自动失效
+	sourceHash := HashContent(meetingContent)
+
+	var cached string
+	if LoadCachedArtifact(meetingID, mermaidArtifactKind, sourceHash, &cached) {
+		return cached, true, nil
+	}
+
+	mermaidCode, err := ExtractMermaid(ctx, meetingContent)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := SaveCachedArtifact(meetingID, mermaidArtifactKind, sourceHash, mermaidCode); err != nil {
+		// 缓存失败不影响本次结果
+		Logf(ctx, "缓存会议流程图失败: %v\n", err)
+	}
+
+	return mermaidCode, false, nil
+}
+
+// GetMeetingOverview 汇总会议的摘要、参会人员、流程图和评分。缺少缓存的流程图和评分并发生成，
+// 其中一项生成失败时记录在 Errors 中（键为 mermaid 或 score），不影响其他部分
+func GetMeetingOverview(ctx context.Context, meetingID string) (*MeetingOverview, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _ := GetMeetingMetadata(meetingData)
+	overview := &MeetingOverview{
+		MeetingID:    meetingID,
+		Title:        metadata.Title,
+		Summary:      metadata.Summary,
+		Participants: metadata.Participants,
+	}
+
+	var mermaidErr, scoreErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		overview.MermaidCode, overview.MermaidCached, mermaidErr = GetCachedMeetingMermaid(ctx, meetingID)
+	}()
+	go func() {
+		defer wg.Done()
+		overview.Score, overview.ScoreCached, scoreErr = GetCachedMeetingScore(ctx, meetingID)
+	}()
+	wg.Wait()
+
+	if mermaidErr != nil || scoreErr != nil {
+		overview.Errors = make(map[string]string)
+		if mermaidErr != nil {
+			overview.Errors["mermaid"] = mermaidErr.Error()
+		}
+		if scoreErr != nil {
+			overview.Errors["score"] = scoreErr.Error()
+		}
+	}
+
+	return overview, nil
+}
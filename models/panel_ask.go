@@ -0,0 +1,155 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 向参会者集体提问的限制
+const (
+	panelAskWorkers = 4     // 同时生成回答的参会者数
+	panelAskerName  = "提问者" // 代替主持人向参会者提问的角色名称
+)
+
+// PanelAskRequest 向多位参会者提出同一个问题的请求
+type PanelAskRequest struct {
+	MeetingID    string   `json:"meeting_id"`
+	Participants []string `json:"participants"`
+	Question     string   `json:"question"`
+}
+
+// PanelAskResponse 各参会者对问题的回答
+type PanelAskResponse struct {
+	MeetingID string            `json:"meeting_id"`
+	Question  string            `json:"question"`
+	Answers   map[string]string `json:"answers"`          // 参会者姓名到回答
+	Failed    map[string]string `json:"failed,omitempty"` // 生成回答失败的参会者及原因
+}
+
+// NormalizePanelAskRequest 校验并规范化集体提问请求：去除重复和空白的参会者，
+// 人数上限与多角色扮演的专家人数上限相同
+func NormalizePanelAskRequest(req *PanelAskRequest) error {
+	req.MeetingID = strings.TrimSpace(req.MeetingID)
+	req.Question = strings.TrimSpace(req.Question)
+
+	if req.MeetingID == "" {
+		return fmt.Errorf("meeting_id 是必需的")
+	}
+	if req.Question == "" {
+		return fmt.Errorf("question 是必需的")
+	}
+
+	seen := make(map[string]bool)
+	participants := make([]string, 0, len(req.Participants))
+	for _, name := range req.Participants {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		participants = append(participants, name)
+	}
+	req.Participants = participants
+
+	if len(req.Participants) == 0 {
+		return fmt.Errorf("至少需要一名参会者")
+	}
+
+	_, maxSpecialists := GetMultiRoleplayLimits()
+	if len(req.Participants) > maxSpecialists {
+		return fmt.Errorf("参会者人数不能超过 %d 人，当前为 %d 人", maxSpecialists, len(req.Participants))
+	}
+
+	return nil
+}
+
+// AskPanel 让每位参会者以自己的身份对问题回答一次，参会者之间互不可见。
+// 回答并发生成，单个参会者失败时记录在 Failed 中而不影响其他参会者
+func AskPanel(ctx context.Context, req *PanelAskRequest) (*PanelAskResponse, error) {
+	meetingContent, meetingInfo, err := getMeetingSpeakerContent(req.MeetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make([]string, len(req.Participants))
+	errs := make([]error, len(req.Participants))
+
+	workers := panelAskWorkers
+	if len(req.Participants) < workers {
+		workers = len(req.Participants)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				answers[i], errs[i] = askPanelist(ctx, req.Participants[i], req.Question, meetingContent, meetingInfo)
+			}
+		}()
+	}
+
+	for i := range req.Participants {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	response := &PanelAskResponse{
+		MeetingID: req.MeetingID,
+		Question:  req.Question,
+		Answers:   make(map[string]string, len(req.Participants)),
+	}
+	for i, name := range req.Participants {
+		if errs[i] != nil {
+			if response.Failed == nil {
+				response.Failed = make(map[string]string)
+			}
+			response.Failed[name] = errs[i].Error()
+			continue
+		}
+		response.Answers[name] = answers[i]
+	}
+
+	return response, nil
+}
+
+// askPanelist 使用与多角色扮演相同的参会者人设生成一次回答
+func askPanelist(ctx context.Context, name, question, meetingContent, meetingInfo string) (string, error) {
+	specialist, err := newSpecialist(ctx, name, meetingContent, meetingInfo, panelAskerName, nil)
+	if err != nil {
+		return "", err
+	}
+
+	messages := []*schema.Message{
+		schema.SystemMessage(withLocaleInstruction(ctx, specialist.SystemPrompt)),
+		schema.UserMessage(fmt.Sprintf("%s向你提问：%s\n请以%s的身份直接回答。", panelAskerName, question, name)),
+	}
+
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	response, err := specialist.ChatModel.Generate(callCtx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return "", fmt.Errorf("生成回答失败: %v", err)
+	}
+
+	answer := strings.TrimSpace(response.Content)
+	if answer == "" {
+		return fmt.Sprintf("（%s表示暂时没有补充意见）", name), nil
+	}
+	if answer, exceeded := newOutputGuard().Push(answer); exceeded {
+		return answer + truncatedMarker, nil
+	}
+	return answer, nil
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GetMeetingParticipants 返回会议元数据中记录的参会人员，去除空白和重复项并保持原有顺序
+func GetMeetingParticipants(meetingData map[string]interface{}) []string {
+	metadata, _ := GetMeetingMetadata(meetingData)
+	return metadata.Participants
+}
+
+// MatchParticipant 在参会人员中查找指定姓名，忽略大小写和空白，返回会议中记录的原始姓名
+func MatchParticipant(participants []string, name string) (string, bool) {
+	key := normalizeParticipantName(name)
+	if key == "" {
+		return "", false
+	}
+	for _, participant := range participants {
+		if normalizeParticipantName(participant) == key {
+			return participant, true
+		}
+	}
+	return "", false
+}
+
+// normalizeParticipantName 去除姓名中的所有空白并转为小写，例如 "Zhang  San" 与 "zhangsan" 视为同一人
+func normalizeParticipantName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), ""))
+}
+
+// ParticipantCount 参会人员及其参加的会议数
+type ParticipantCount struct {
+	Name         string `json:"name"`
+	MeetingCount int    `json:"meeting_count"`
+}
+
+// participantIndex 所有会议参会人员的聚合结果缓存，会议保存后失效
+var participantIndex struct {
+	sync.Mutex
+	generation   int                // 每次失效时递增，避免把失效前开始统计的结果写入缓存
+	meetingCount int                // 统计时的会议文件数，会议文件被删除后据此重新统计
+	participants []ParticipantCount // 为 nil 表示缓存未生成或已失效
+}
+
+// invalidateParticipantIndex 使参会人员聚合缓存失效，会议新建或内容变化时调用
+func invalidateParticipantIndex() {
+	participantIndex.Lock()
+	participantIndex.generation++
+	participantIndex.participants = nil
+	participantIndex.Unlock()
+}
+
+// ListAllParticipants 返回所有会议中出现过的参会人员及参加的会议数，按会议数降序、姓名升序排列。
+// 姓名忽略大小写和空白去重，prefix 非空时只返回以其开头的姓名（同样忽略大小写和空白）
+func ListAllParticipants(prefix string) ([]ParticipantCount, error) {
+	meetingIDs, err := ListMeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	participantIndex.Lock()
+	participants := participantIndex.participants
+	if participantIndex.meetingCount != len(meetingIDs) {
+		participants = nil
+	}
+	generation := participantIndex.generation
+	participantIndex.Unlock()
+
+	if participants == nil {
+		participants = aggregateParticipants(meetingIDs)
+		participantIndex.Lock()
+		if participantIndex.generation == generation {
+			participantIndex.participants = participants
+			participantIndex.meetingCount = len(meetingIDs)
+		}
+		participantIndex.Unlock()
+	}
+
+	key := normalizeParticipantName(prefix)
+	result := make([]ParticipantCount, 0, len(participants))
+	for _, participant := range participants {
+		if strings.HasPrefix(normalizeParticipantName(participant.Name), key) {
+			result = append(result, participant)
+		}
+	}
+	return result, nil
+}
+
+// aggregateParticipants 扫描所有会议元数据统计参会人员，同一人的不同写法以最先出现的写法展示
+func aggregateParticipants(meetingIDs []string) []ParticipantCount {
+	meetingIDs = append([]string(nil), meetingIDs...)
+	sort.Strings(meetingIDs)
+
+	indexes := make(map[string]int)
+	participants := []ParticipantCount{}
+	for _, meetingID := range meetingIDs {
+		meetingData, err := LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("读取会议 %s 失败: %v\n", meetingID, err)
+			continue
+		}
+		for _, name := range GetMeetingParticipants(meetingData) {
+			key := normalizeParticipantName(name)
+			if i, ok := indexes[key]; ok {
+				participants[i].MeetingCount++
+				continue
+			}
+			indexes[key] = len(participants)
+			participants = append(participants, ParticipantCount{Name: name, MeetingCount: 1})
+		}
+	}
+
+	sort.SliceStable(participants, func(i, j int) bool {
+		if participants[i].MeetingCount != participants[j].MeetingCount {
+			return participants[i].MeetingCount > participants[j].MeetingCount
+		}
+		return participants[i].Name < participants[j].Name
+	})
+	return participants
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 判断发言多少的阈值，相对每人平均占比
+const (
+	dominantShareFactor = 2.0 // 发言量超过平均占比的两倍视为主导讨论
+	quietShareFactor    = 0.5 // 发言量不到平均占比的一半视为发言较少
+)
+
+// ParticipantContribution 一位参会者的发言统计
+type ParticipantContribution struct {
+	Name      string  `json:"name"`
+	Turns     int     `json:"turns"`      // 发言次数，连续多行的同一段发言计为一次
+	Words     int     `json:"words"`      // 发言字数，中文按字、英文按单词计算
+	TurnShare float64 `json:"turn_share"` // 发言次数占比
+	WordShare float64 `json:"word_share"` // 发言字数占比
+	Listed    bool    `json:"listed"`     // 是否在会议元数据的参会人员中，说话人标注中的"发言人1"等为 false
+}
+
+// ParticipationAnalysis 会议的发言占比分析。会议记录没有说话人标签时 Determinable 为 false，不返回统计
+type ParticipationAnalysis struct {
+	MeetingID    string                    `json:"meeting_id"`
+	Determinable bool                      `json:"determinable"`
+	Reason       string                    `json:"reason,omitempty"` // 无法统计的原因
+	TotalTurns   int                       `json:"total_turns"`
+	TotalWords   int                       `json:"total_words"`
+	Participants []ParticipantContribution `json:"participants"` // 按发言字数降序，没有发言的参会人员排在最后
+	Dominant     []string                  `json:"dominant"`     // 发言字数超过平均占比两倍的参会者
+	Quiet        []string                  `json:"quiet"`        // 发言字数不到平均占比一半的参会者，包括没有发言的参会人员
+}
+
+// GetMeetingParticipation 按说话人标签统计会议中每位参会者的发言次数和字数，有说话人标注版本时使用标注版本。
+// 没有发言的参会人员同样列出，便于发现全程沉默的人
+func GetMeetingParticipation(meetingID string) (*ParticipationAnalysis, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	analysis := AnalyzeParticipation(meetingData)
+	analysis.MeetingID = meetingID
+	return analysis, nil
+}
+
+// AnalyzeParticipation 统计会议数据中每位参会者的发言，会议记录没有说话人标签时返回无法统计的结果
+func AnalyzeParticipation(meetingData map[string]interface{}) *ParticipationAnalysis {
+	analysis := &ParticipationAnalysis{
+		Participants: []ParticipantContribution{},
+		Dominant:     []string{},
+		Quiet:        []string{},
+	}
+
+	content := MeetingSpeakerContent(meetingData)
+	if !HasSpeakerLabels(content) {
+		analysis.Reason = "会议记录没有清晰的说话人标签，无法确定各参会者的发言占比"
+		if !IsDiarizationEnabled() {
+			analysis.Reason += "；可开启 extraction.diarization 后重新抽取，由模型标注说话人"
+		}
+		return analysis
+	}
+	analysis.Determinable = true
+
+	// 说话人与参会人员按姓名匹配（忽略大小写和空白），使用参会人员中的姓名
+	metadata, _ := GetMeetingMetadata(meetingData)
+	byKey := make(map[string]*ParticipantContribution)
+	var order []string
+	add := func(name string, listed bool) *ParticipantContribution {
+		key := normalizeParticipantName(name)
+		if c, ok := byKey[key]; ok {
+			return c
+		}
+		c := &ParticipantContribution{Name: name, Listed: listed}
+		byKey[key] = c
+		order = append(order, key)
+		return c
+	}
+	for _, name := range metadata.Participants {
+		add(name, true)
+	}
+
+	// 没有说话人标签的行视为上一位说话人发言的延续，第一个标签之前的内容不计入
+	var current *ParticipantContribution
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		text := line
+		if match := speakerLabelPattern.FindStringSubmatch(line); match != nil {
+			speaker := add(strings.TrimSpace(match[1]), false)
+			if speaker != current {
+				speaker.Turns++
+				analysis.TotalTurns++
+			}
+			current = speaker
+			text = match[2]
+		}
+		if current == nil {
+			continue
+		}
+		words := countWords(text)
+		current.Words += words
+		analysis.TotalWords += words
+	}
+
+	for _, key := range order {
+		c := byKey[key]
+		if analysis.TotalTurns > 0 {
+			c.TurnShare = float64(c.Turns) / float64(analysis.TotalTurns)
+		}
+		if analysis.TotalWords > 0 {
+			c.WordShare = float64(c.Words) / float64(analysis.TotalWords)
+		}
+		analysis.Participants = append(analysis.Participants, *c)
+	}
+	sort.SliceStable(analysis.Participants, func(i, j int) bool {
+		return analysis.Participants[i].Words > analysis.Participants[j].Words
+	})
+
+	// 只有一位参会者时无所谓主导或沉默
+	if n := len(analysis.Participants); n > 1 {
+		average := 1 / float64(n)
+		for _, c := range analysis.Participants {
+			switch {
+			case c.WordShare > average*dominantShareFactor:
+				analysis.Dominant = append(analysis.Dominant, c.Name)
+			case c.WordShare < average*quietShareFactor:
+				analysis.Quiet = append(analysis.Quiet, c.Name)
+			}
+		}
+	}
+
+	return analysis
+}
+
+// countWords 统计发言字数：中日韩文字按字计算，其他文字按空白和标点分隔的单词计算
+func countWords(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// ParticipationScoreNote 返回附加到评分输入中的发言统计，供模型评价"参与者互动与参与度"时参考，无法统计时返回空字符串
+func ParticipationScoreNote(meetingData map[string]interface{}) string {
+	analysis := AnalyzeParticipation(meetingData)
+	if !analysis.Determinable || analysis.TotalWords == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n发言统计（按说话人标签估算，供评价参与者互动与参与度时参考，请在该项的评价中指出主导讨论或发言较少的参会者）:\n")
+	for _, c := range analysis.Participants {
+		b.WriteString(fmt.Sprintf("- %s: 发言%d次，约%d字，占%.0f%%\n", c.Name, c.Turns, c.Words, c.WordShare*100))
+	}
+	if len(analysis.Dominant) > 0 {
+		b.WriteString("发言明显较多: " + strings.Join(analysis.Dominant, "、") + "\n")
+	}
+	if len(analysis.Quiet) > 0 {
+		b.WriteString("发言明显较少: " + strings.Join(analysis.Quiet, "、") + "\n")
+	}
+	return b.String()
+}
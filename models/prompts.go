@@ -0,0 +1,223 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// 可自定义的提示模板名称，对应提示目录下的 <名称>.txt 文件
+const (
+	PromptChat     = "chat"     // 会议问答的系统提示
+	PromptRolePlay = "roleplay" // 角色扮演的提问提示
+	PromptExtract  = "extract"  // 会议信息抽取的系统提示
+	PromptScore    = "score"    // 会议评分规则
+	// 多角色扮演讨论总结的系统提示
+	PromptDiscussionSummary = "discussion_summary"
+)
+
+// 提示模板的来源
+const (
+	PromptSourceBuiltin = "builtin"
+	PromptSourceFile    = "file"
+)
+
+// defaultPromptsDir 默认的提示模板目录
+const defaultPromptsDir = "./prompts"
+
+// PromptData 渲染提示模板时可用的占位符，例如 {{.MeetingContent}}
+type PromptData struct {
+	MeetingContent  string // 会议内容
+	ParticipantName string // 角色扮演的参会者
+	Query           string // 用户问题，已清理并放入 <user_input> 分隔标签
+	AnswerLimits    string // 回答长度和引用数量的约束说明
+	CurrentDate     string // 当前日期，格式 YYYY-MM-DD，用于换算相对日期
+	MeetingType     string // 抽取时会议类型的要求：已指定的类型或可选的类型列表
+	SummaryLimit    string // 摘要长度的要求：抽取时随会议类型变化，讨论总结时来自配置
+	SummaryStyle    string // 讨论总结的格式要求，由请求的 summary_style 选择
+}
+
+// ResolvedPrompt 生效中的提示模板
+type ResolvedPrompt struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`         // builtin 或 file
+	Path   string `json:"path,omitempty"` // 来源为文件时的文件路径
+	Text   string `json:"text"`
+}
+
+// builtinPrompts 内置的默认提示模板
+var builtinPrompts = map[string]string{
+	PromptChat: `你是一个会议助手，负责回答用户关于会议内容的问题。{{.AnswerLimits}}`,
+
+	PromptRolePlay: `
+会议内容:
+{{.MeetingContent}}
+
+你现在扮演的角色是会议中的参会者"{{.ParticipantName}}"。你需要以这个人的身份、语气、知识背景、态度和说话方式来回答问题。
+根据会议记录中这个人的言论和表现来模仿他/她的回答风格。如果会议内容中没有足够的信息来确定这个人的某些观点，
+你可以基于这个人在会议中表现出的性格特点来合理推测，但要保持一致性。
+
+用户将以对话形式向你提问，你要始终保持角色扮演，不要暴露你是AI的事实。回答要简洁自然，符合真实对话的风格。
+`,
+
+	PromptExtract: `你是一个专业的会议分析助手。请从会议文本中提取以下信息：
+1. 会议标题(必须包含)
+2. 会议描述或主题(必须包含)
+3. 参会人员列表(必须包含)
+4. 会议开始时间（尽可能精确到日期和时间，格式为 YYYY-MM-DD HH:MM，会议中没有提到时为空字符串）
+5. 会议结束时间（尽可能精确到日期和时间，格式同开始时间）
+6. 会议主要内容摘要({{.SummaryLimit}})
+7. 会议中提到的一些待办事项(必须包含)，每项包含：
+   - task: 任务内容
+   - assignee: 负责人姓名，使用会议中的称呼，无法确定时为空字符串
+   - due_date: 截止日期，格式为 YYYY-MM-DD；"周五"、"下周一"等相对日期按会议日期换算（会议日期未知时按今天 {{.CurrentDate}} 换算），无法确定时为空字符串
+8. 会议类型 meeting_type，{{.MeetingType}}
+
+以JSON格式返回,字段包括:title, description, participants(数组), start_time, end_time, summary, todo_list(对象数组，每项包含 task, assignee, due_date), meeting_type。`,
+
+	PromptScore: `你是一个专业的会议评估专家。你需要根据以下评分规则对提供的会议文本进行全面客观的评估：
+
+核心指标一：会议目标达成度 (Meeting Goal Achievement) - 总分 /4
+4 分 (优秀): 会议完全实现了预定的目标，目标非常明确且可衡量，产出了清晰、可执行的成果和行动项，问题（如果会议目的是解决问题）得到了高效解决。
+3 分 (良好): 会议基本实现了预定的目标，目标比较明确，产出了较为具体的成果和行动项，问题（如果会议目的是解决问题）得到了较好解决。
+2 分 (一般): 会议部分实现了预定的目标，目标相对模糊，成果和行动项较为笼统，问题（如果会议目的是解决问题）得到了初步讨论，但解决程度有限。
+1 分 (较差): 会议未能有效实现预定的目标，目标不明确，缺乏有效成果和行动项，问题（如果会议目的是解决问题）未得到有效解决。
+
+核心指标二：主题聚焦度 (Topic Focus) - 总分 /4
+4 分 (优秀): 讨论完全聚焦于会议主题和议程，严格遵循议程，所有内容高度相关，时间利用非常高效，无跑题。
+3 分 (良好): 讨论基本聚焦于会议主题和议程，大部分遵循议程，内容基本相关，时间利用效率较高，偶有少量跑题但能及时拉回。
+2 分 (一般): 讨论部分偏离会议主题和议程，议程遵循度一般，部分内容关联性较弱，时间利用效率一般，跑题现象较为明显。
+1 分 (较差): 讨论严重偏离会议主题和议程，议程形同虚设，大量内容无关，时间利用效率极低，严重跑题。
+
+核心指标三：参与者互动与参与度 (Participant Engagement & Interaction) - 总分 /4
+4 分 (优秀): 绝大多数参与者都积极参与，互动频繁且深入，认真倾听并尊重他人，讨论氛围非常积极合作，充分体现集体智慧。
+3 分 (良好): 多数参与者都积极参与，互动较好，基本认真倾听并尊重他人，讨论氛围较为友好，参与度良好。
+2 分 (一般): 部分参与者参与，互动较少，倾听和尊重程度一般，讨论氛围有待改善，参与度一般，部分人沉默。
+1 分 (较差): 少数人主导，参与度极低，几乎没有互动，缺乏倾听和尊重，讨论氛围紧张或冷淡，如同单向汇报。
+
+必须严格按照以上评分标准，根据会议文本的内容和质量，为每个核心指标打分，并给出总体评价。你的评估必须客观、公正、详细，基于事实而非主观假设。
+你的回答必须包含每个指标的得分（1-4分）和详细理由、一个总体评价，以及一句不超过30字的简短结论。
+
+以下是你必须返回的JSON格式（不要输出其他内容）：
+{
+  "goal_achievement": 分数,
+  "goal_achievement_feedback": "理由...",
+  "topic_focus": 分数,
+  "topic_focus_feedback": "理由...",
+  "participant_engagement": 分数,
+  "participant_engagement_feedback": "理由...",
+  "overall_feedback": "总体评价...",
+  "short_verdict": "一句话结论..."
+}`,
+
+	PromptDiscussionSummary: `作为专业会议纪要专家，请对提供的会议讨论内容进行总结。{{.SummaryStyle}}
+
+{{.SummaryLimit}}请以第三人称编写，不要添加个人评价。`,
+}
+
+// promptTemplate 解析后的提示模板
+type promptTemplate struct {
+	info ResolvedPrompt
+	tmpl *template.Template
+}
+
+var (
+	prompts      map[string]*promptTemplate
+	promptsMutex sync.RWMutex
+)
+
+// GetPromptsDir 获取提示模板目录，优先级为环境变量 PROMPTS_DIR > 配置文件 > 默认值
+func GetPromptsDir() string {
+	dir := defaultPromptsDir
+	if cfg, err := LoadConfig(); err == nil && cfg.Prompts.Dir != "" {
+		dir = cfg.Prompts.Dir
+	}
+	if envDir := os.Getenv("PROMPTS_DIR"); envDir != "" {
+		dir = envDir
+	}
+	return dir
+}
+
+// LoadPrompts 从提示目录加载提示模板，文件不存在时使用内置默认值。
+// 模板文件存在但无法读取或解析时返回错误，避免带着错误的提示启动
+func LoadPrompts() error {
+	dir := GetPromptsDir()
+	loaded := make(map[string]*promptTemplate, len(builtinPrompts))
+
+	for name, text := range builtinPrompts {
+		info := ResolvedPrompt{Name: name, Source: PromptSourceBuiltin, Text: text}
+
+		filePath := filepath.Join(dir, name+".txt")
+		data, err := os.ReadFile(filePath)
+		switch {
+		case err == nil:
+			info.Source = PromptSourceFile
+			info.Path = filePath
+			info.Text = string(data)
+		case !os.IsNotExist(err):
+			return fmt.Errorf("读取提示模板 %s 失败: %v", filePath, err)
+		}
+
+		tmpl, err := template.New(name).Parse(info.Text)
+		if err != nil {
+			return fmt.Errorf("解析提示模板 %s 失败: %v", name, err)
+		}
+		loaded[name] = &promptTemplate{info: info, tmpl: tmpl}
+	}
+
+	promptsMutex.Lock()
+	prompts = loaded
+	promptsMutex.Unlock()
+
+	return nil
+}
+
+// GetResolvedPrompts 返回当前生效的提示模板，按名称排序
+func GetResolvedPrompts() []ResolvedPrompt {
+	promptsMutex.RLock()
+	defer promptsMutex.RUnlock()
+
+	result := make([]ResolvedPrompt, 0, len(builtinPrompts))
+	for name, text := range builtinPrompts {
+		if p, ok := prompts[name]; ok {
+			result = append(result, p.info)
+		} else {
+			result = append(result, ResolvedPrompt{Name: name, Source: PromptSourceBuiltin, Text: text})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// RenderPrompt 使用给定数据渲染提示模板，未调用 LoadPrompts 时使用内置默认模板
+func RenderPrompt(name string, data PromptData) (string, error) {
+	promptsMutex.RLock()
+	p, ok := prompts[name]
+	promptsMutex.RUnlock()
+
+	var tmpl *template.Template
+	if ok {
+		tmpl = p.tmpl
+	} else {
+		text, exists := builtinPrompts[name]
+		if !exists {
+			return "", fmt.Errorf("提示模板 %s 不存在", name)
+		}
+		var err error
+		if tmpl, err = template.New(name).Parse(text); err != nil {
+			return "", fmt.Errorf("解析提示模板 %s 失败: %v", name, err)
+		}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("渲染提示模板 %s 失败: %v", name, err)
+	}
+	return sb.String(), nil
+}
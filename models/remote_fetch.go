@@ -0,0 +1,221 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// 远程会议记录拉取的默认限制
+const (
+	defaultFetchTimeout  = 15 * time.Second
+	defaultFetchMaxBytes = 2 << 20 // 2MB
+	maxFetchRedirects    = 5
+)
+
+var (
+	// ErrFetchURLNotAllowed URL 格式无效，或目标地址不在允许范围内
+	ErrFetchURLNotAllowed = errors.New("不允许访问该地址")
+	// ErrFetchUnsupportedContent 远程内容不是文本
+	ErrFetchUnsupportedContent = errors.New("远程内容不是文本")
+	// ErrFetchTooLarge 远程内容超过大小上限
+	ErrFetchTooLarge = errors.New("远程内容过大")
+)
+
+// FetchSettings 从URL拉取会议记录的限制
+type FetchSettings struct {
+	Timeout              time.Duration
+	MaxBytes             int64
+	AllowedHosts         []string // 非空时只允许访问这些域名及其子域名
+	DeniedHosts          []string // 禁止访问的域名及其子域名
+	AllowPrivateNetworks bool     // 是否允许访问回环、内网等地址
+}
+
+// GetFetchSettings 获取拉取远程会议记录的限制，未配置时使用默认值
+func GetFetchSettings() FetchSettings {
+	settings := FetchSettings{
+		Timeout:  defaultFetchTimeout,
+		MaxBytes: defaultFetchMaxBytes,
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return settings
+	}
+	if cfg.Fetch.TimeoutSeconds > 0 {
+		settings.Timeout = time.Duration(cfg.Fetch.TimeoutSeconds) * time.Second
+	}
+	if cfg.Fetch.MaxBytes > 0 {
+		settings.MaxBytes = cfg.Fetch.MaxBytes
+	}
+	settings.AllowedHosts = cfg.Fetch.AllowedHosts
+	settings.DeniedHosts = cfg.Fetch.DeniedHosts
+	settings.AllowPrivateNetworks = cfg.Fetch.AllowPrivateNetworks
+	return settings
+}
+
+// subripMediaType SRT 字幕常用的 Content-Type，按文本处理
+const subripMediaType = "application/x-subrip"
+
+// FetchTranscript 通过HTTP拉取远程会议记录文本。只允许 http/https，域名需通过允许/禁止列表校验，
+// 默认拒绝回环、内网、链路本地等地址（在建立连接时按解析出的IP校验，重定向同样校验），
+// 只接受 text/* 类型（以及 SRT 字幕的 application/x-subrip）且为 UTF-8 编码的内容
+func FetchTranscript(ctx context.Context, rawURL string) (string, error) {
+	settings := GetFetchSettings()
+
+	target, err := validateFetchURL(rawURL, settings)
+	if err != nil {
+		return "", err
+	}
+
+	client := newRestrictedHTTPClient(settings)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrFetchURLNotAllowed, err)
+	}
+	req.Header.Set("Accept", "text/plain, text/*;q=0.9, "+subripMediaType+";q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrFetchURLNotAllowed) {
+			return "", fmt.Errorf("%w: 目标地址或重定向地址不在允许范围内", ErrFetchURLNotAllowed)
+		}
+		return "", fmt.Errorf("拉取远程内容失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("拉取远程内容失败: 返回状态码 %d", resp.StatusCode)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (!strings.HasPrefix(mediaType, "text/") && mediaType != subripMediaType) {
+		return "", fmt.Errorf("%w: Content-Type 为 %q", ErrFetchUnsupportedContent, resp.Header.Get("Content-Type"))
+	}
+	if charset := strings.ToLower(params["charset"]); charset != "" && charset != "utf-8" && charset != "us-ascii" {
+		return "", fmt.Errorf("%w: 不支持的字符集 %s", ErrFetchUnsupportedContent, charset)
+	}
+
+	if resp.ContentLength > settings.MaxBytes {
+		return "", fmt.Errorf("%w: %d 字节，最多 %d 字节", ErrFetchTooLarge, resp.ContentLength, settings.MaxBytes)
+	}
+
+	// 多读一个字节以判断是否超过上限
+	data, err := io.ReadAll(io.LimitReader(resp.Body, settings.MaxBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("读取远程内容失败: %v", err)
+	}
+	if int64(len(data)) > settings.MaxBytes {
+		return "", fmt.Errorf("%w: 最多 %d 字节", ErrFetchTooLarge, settings.MaxBytes)
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("%w: 内容不是有效的 UTF-8 文本", ErrFetchUnsupportedContent)
+	}
+
+	// 去掉部分编辑器保存的 UTF-8 BOM
+	return strings.TrimPrefix(string(data), "\ufeff"), nil
+}
+
+// newRestrictedHTTPClient 创建访问外部地址的HTTP客户端：建立连接时按解析出的IP拒绝内网地址（允许内网时除外），
+// 重定向的目标同样经过 validateFetchURL 校验
+func newRestrictedHTTPClient(settings FetchSettings) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: settings.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if settings.AllowPrivateNetworks {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateAddress(ip) {
+				return fmt.Errorf("%w: %s", ErrFetchURLNotAllowed, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: settings.Timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   settings.Timeout,
+			ResponseHeaderTimeout: settings.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFetchRedirects {
+				return fmt.Errorf("重定向次数过多")
+			}
+			_, err := validateFetchURL(req.URL.String(), settings)
+			return err
+		},
+	}
+}
+
+// validateFetchURL 校验URL的协议和域名，域名为IP字面量时同时校验是否为内网地址
+func validateFetchURL(rawURL string, settings FetchSettings) (*url.URL, error) {
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: URL 格式无效", ErrFetchURLNotAllowed)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("%w: 只支持 http 和 https", ErrFetchURLNotAllowed)
+	}
+	if target.User != nil {
+		return nil, fmt.Errorf("%w: URL 不能包含用户信息", ErrFetchURLNotAllowed)
+	}
+
+	host := strings.ToLower(target.Hostname())
+	if host == "" {
+		return nil, fmt.Errorf("%w: URL 缺少主机名", ErrFetchURLNotAllowed)
+	}
+
+	if matchHostList(host, settings.DeniedHosts) {
+		return nil, fmt.Errorf("%w: %s 在禁止列表中", ErrFetchURLNotAllowed, host)
+	}
+	if len(settings.AllowedHosts) > 0 && !matchHostList(host, settings.AllowedHosts) {
+		return nil, fmt.Errorf("%w: %s 不在允许列表中", ErrFetchURLNotAllowed, host)
+	}
+
+	if !settings.AllowPrivateNetworks {
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return nil, fmt.Errorf("%w: %s", ErrFetchURLNotAllowed, host)
+		}
+		if ip := net.ParseIP(host); ip != nil && isPrivateAddress(ip) {
+			return nil, fmt.Errorf("%w: %s", ErrFetchURLNotAllowed, host)
+		}
+	}
+
+	return target, nil
+}
+
+// matchHostList 判断域名是否等于列表中的某一项或是其子域名
+func matchHostList(host string, hosts []string) bool {
+	for _, item := range hosts {
+		item = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(item), "."))
+		if item == "" {
+			continue
+		}
+		if host == item || strings.HasSuffix(host, "."+item) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrivateAddress 判断IP是否为回环、内网、链路本地、未指定或组播地址
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
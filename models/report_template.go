@@ -0,0 +1,151 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 报告模板中的区块类型，每种类型对应会议报告中的一个字段，divider 为分割线
+const (
+	ReportSectionTime         = "time"
+	ReportSectionDescription  = "description"
+	ReportSectionSummary      = "summary"
+	ReportSectionParticipants = "participants"
+	ReportSectionScore        = "score"
+	ReportSectionTodos        = "todos"
+	ReportSectionDivider      = "divider"
+)
+
+// ReportSection 报告模板中的一个区块，Title 为空时使用该类型的默认标题
+type ReportSection struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+}
+
+// reportSectionTitles 各区块类型的默认标题
+var reportSectionTitles = map[string]string{
+	ReportSectionTime:         "会议时间",
+	ReportSectionDescription:  "会议描述",
+	ReportSectionSummary:      "会议摘要",
+	ReportSectionParticipants: "参会人员",
+	ReportSectionScore:        "会议评分",
+	ReportSectionTodos:        "待办事项",
+	ReportSectionDivider:      "",
+}
+
+// DefaultReportSections 默认的报告模板：时间、描述、摘要、分割线、参会人员、评分、待办事项
+var DefaultReportSections = []ReportSection{
+	{Type: ReportSectionTime},
+	{Type: ReportSectionDescription},
+	{Type: ReportSectionSummary},
+	{Type: ReportSectionDivider},
+	{Type: ReportSectionParticipants},
+	{Type: ReportSectionScore},
+	{Type: ReportSectionTodos},
+}
+
+// ValidateReportSections 校验报告模板：区块类型必须受支持，除分割线外每种类型最多出现一次，且至少包含一个非分割线区块
+func ValidateReportSections(sections []ReportSection) error {
+	seen := make(map[string]bool)
+	for i, section := range sections {
+		sectionType := strings.TrimSpace(section.Type)
+		if _, ok := reportSectionTitles[sectionType]; !ok {
+			return fmt.Errorf("第 %d 个区块的类型 %q 不受支持，可选值: %s", i+1, section.Type, strings.Join(reportSectionTypes(), ", "))
+		}
+		if sectionType == ReportSectionDivider {
+			continue
+		}
+		if seen[sectionType] {
+			return fmt.Errorf("区块类型 %s 重复出现", sectionType)
+		}
+		seen[sectionType] = true
+	}
+	if len(seen) == 0 {
+		return fmt.Errorf("报告模板至少需要包含一个内容区块")
+	}
+	return nil
+}
+
+// GetReportSections 获取配置的报告模板，未配置时使用默认模板，未配置标题的区块使用默认标题
+func GetReportSections() []ReportSection {
+	sections := DefaultReportSections
+	if cfg, err := LoadConfig(); err == nil && len(cfg.Report.Sections) > 0 {
+		sections = cfg.Report.Sections
+	}
+
+	result := make([]ReportSection, 0, len(sections))
+	for _, section := range sections {
+		section.Type = strings.TrimSpace(section.Type)
+		if section.Title = strings.TrimSpace(section.Title); section.Title == "" {
+			section.Title = reportSectionTitles[section.Type]
+		}
+		result = append(result, section)
+	}
+	return result
+}
+
+// findReportSection 查找模板中指定类型的区块，模板中没有该类型时返回 false
+func findReportSection(sections []ReportSection, sectionType string) (ReportSection, bool) {
+	for _, section := range sections {
+		if section.Type == sectionType {
+			return section, true
+		}
+	}
+	return ReportSection{}, false
+}
+
+// reportSectionText 返回文本类区块的内容，字段为空时返回空字符串，调用方据此跳过该区块。
+// 评分区块返回各项得分按行拼接的文本，各渠道可以自行选择展示方式
+func reportSectionText(report *MeetingReport, sectionType string) string {
+	switch sectionType {
+	case ReportSectionTime:
+		return reportTimeText(report)
+	case ReportSectionDescription:
+		return report.Description
+	case ReportSectionSummary:
+		return report.Summary
+	case ReportSectionParticipants:
+		return strings.Join(report.Participants, "、")
+	case ReportSectionScore:
+		if report.Score == nil {
+			return ""
+		}
+		return strings.Join(reportScoreLines(report.Score), "\n")
+	case ReportSectionTodos:
+		return reportTodoLines(report.TodoList)
+	}
+	return ""
+}
+
+// reportTimeText 返回"开始时间 - 结束时间（时长）"形式的会议时间，缺少的部分省略
+func reportTimeText(report *MeetingReport) string {
+	var parts []string
+	for _, value := range []string{report.StartTime, report.EndTime} {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	text := strings.Join(parts, " - ")
+	if report.Duration != "" {
+		text += "（" + report.Duration + "）"
+	}
+	return text
+}
+
+// reportTodoLines 将待办事项编号后按行拼接，每行以换行结尾
+func reportTodoLines(todoList []string) string {
+	var sb strings.Builder
+	for i, todo := range todoList {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, todo))
+	}
+	return sb.String()
+}
+
+// reportSectionTypes 返回所有支持的区块类型，按默认模板中的顺序排列
+func reportSectionTypes() []string {
+	types := make([]string, 0, len(DefaultReportSections))
+	for _, section := range DefaultReportSections {
+		types = append(types, section.Type)
+	}
+	return types
+}
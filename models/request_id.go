@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"fmt"
+)
+
+// requestIDKey 请求ID在 context 中的键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求ID的 context，用于关联同一请求的日志
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 获取 context 中的请求ID，没有时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// Logf 打印日志，context 中带有请求ID时在行首加上 [request_id=...]，便于按请求关联日志
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		format = "[request_id=" + requestID + "] " + format
+	}
+	fmt.Printf(format, args...)
+}
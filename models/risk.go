@@ -0,0 +1,194 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 风险等级
+const (
+	RiskLevelNone   = "none"
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+// riskArtifactKind 风险分析结果的缓存类型
+const riskArtifactKind = "risks"
+
+// defaultRiskDimensions 默认的风险识别维度
+var defaultRiskDimensions = []string{
+	"法律合规风险",
+	"执行风险",
+	"分歧未解决",
+	"承诺模糊",
+}
+
+// riskLevelOrder 风险等级的排序权重
+var riskLevelOrder = map[string]int{
+	RiskLevelHigh:   3,
+	RiskLevelMedium: 2,
+	RiskLevelLow:    1,
+	RiskLevelNone:   0,
+}
+
+// MeetingRisk 表示会议中识别出的一项风险
+type MeetingRisk struct {
+	Dimension   string `json:"dimension"`   // 风险维度
+	Level       string `json:"level"`       // 风险等级: high / medium / low
+	Description string `json:"description"` // 风险描述
+	Evidence    string `json:"evidence"`    // 会议中的相关依据
+	Mitigation  string `json:"mitigation"`  // 缓解建议
+}
+
+// MeetingRiskReport 表示会议风险分析结果
+type MeetingRiskReport struct {
+	MeetingID    string        `json:"meeting_id"`
+	OverallLevel string        `json:"overall_level"` // 整体风险等级: none / low / medium / high
+	Conclusion   string        `json:"conclusion"`    // 总体结论
+	Risks        []MeetingRisk `json:"risks"`         // 按风险等级从高到低排列
+	Dimensions   []string      `json:"dimensions"`    // 本次分析使用的风险维度
+	GeneratedAt  time.Time     `json:"generated_at"`
+	Cached       bool          `json:"cached"` // 是否来自缓存
+}
+
+// GetRiskDimensions 获取配置的风险识别维度，未配置时使用默认维度
+func GetRiskDimensions() []string {
+	cfg, err := LoadConfig()
+	if err != nil || len(cfg.Risk.Dimensions) == 0 {
+		return defaultRiskDimensions
+	}
+	return cfg.Risk.Dimensions
+}
+
+// GetMeetingRisks 获取会议风险分析结果，优先使用缓存，refresh 为 true 时强制重新分析
+func GetMeetingRisks(ctx context.Context, meetingID string, refresh bool) (*MeetingRiskReport, error) {
+	meetingContent, meetingInfo, err := getMeetingContent(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := GetRiskDimensions()
+
+	// 会议内容或风险维度变化后缓存自动失效
+	sourceHash := HashContent(meetingInfo, meetingContent, strings.Join(dimensions, "|"))
+
+	if !refresh {
+		var cached MeetingRiskReport
+		if LoadCachedArtifact(meetingID, riskArtifactKind, sourceHash, &cached) {
+			cached.Cached = true
+			return &cached, nil
+		}
+	}
+
+	report, err := AnalyzeMeetingRisks(ctx, meetingInfo+"\n会议内容:\n"+meetingContent, dimensions)
+	if err != nil {
+		return nil, err
+	}
+	report.MeetingID = meetingID
+
+	if err := SaveCachedArtifact(meetingID, riskArtifactKind, sourceHash, report); err != nil {
+		// 缓存失败不影响本次结果
+		Logf(ctx, "缓存会议风险分析结果失败: %v\n", err)
+	}
+
+	return report, nil
+}
+
+// AnalyzeMeetingRisks 使用LLM识别会议中的潜在风险
+func AnalyzeMeetingRisks(ctx context.Context, documentText string, dimensions []string) (*MeetingRiskReport, error) {
+	chatModel, err := GetChatModel(ctx, 0.2) // 低温度以获得一致的识别结果
+
+	if err != nil {
+		return nil, fmt.Errorf("创建LLM客户端失败: %v", err)
+	}
+
+	// 准备系统提示
+	systemPrompt := fmt.Sprintf(`你是一个专业的会议风险评估专家，擅长识别合同、法务和重大决策类会议中的潜在风险。
+请仅从以下维度识别会议中明确提到或隐含的风险：%s
+
+要求：
+1. 每项风险必须基于会议内容，给出会议中的相关依据，不要凭空臆测
+2. 风险等级只能是 high、medium、low 之一
+3. 为每项风险给出具体可执行的缓解建议
+4. 如果会议是常规的低风险会议，没有值得关注的风险，risks 返回空数组，overall_level 返回 none
+
+以下是你必须返回的JSON格式（不要输出其他内容）：
+{
+  "overall_level": "none/low/medium/high",
+  "conclusion": "总体结论...",
+  "risks": [
+    {
+      "dimension": "风险维度",
+      "level": "high/medium/low",
+      "description": "风险描述...",
+      "evidence": "会议中的相关依据...",
+      "mitigation": "缓解建议..."
+    }
+  ]
+}`, strings.Join(dimensions, "、"))
+
+	// 准备消息
+	messages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(documentText),
+	}
+
+	// 生成回答
+	response, err := chatModel.Generate(ctx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("识别会议风险失败: %v", err)
+	}
+
+	// 解析JSON响应
+	var report MeetingRiskReport
+	if err := parseJSONObject(response.Content, &report); err != nil {
+		return nil, fmt.Errorf("解析风险分析结果失败: %v", err)
+	}
+
+	normalizeRiskReport(&report)
+	report.Dimensions = dimensions
+	report.GeneratedAt = time.Now()
+
+	return &report, nil
+}
+
+// normalizeRiskReport 规范化模型返回的风险等级并按等级排序
+func normalizeRiskReport(report *MeetingRiskReport) {
+	risks := make([]MeetingRisk, 0, len(report.Risks))
+	highest := RiskLevelNone
+	for _, risk := range report.Risks {
+		risk.Level = strings.ToLower(strings.TrimSpace(risk.Level))
+		if _, ok := riskLevelOrder[risk.Level]; !ok || risk.Level == RiskLevelNone {
+			risk.Level = RiskLevelLow
+		}
+		if strings.TrimSpace(risk.Description) == "" {
+			continue
+		}
+		if riskLevelOrder[risk.Level] > riskLevelOrder[highest] {
+			highest = risk.Level
+		}
+		risks = append(risks, risk)
+	}
+
+	sort.SliceStable(risks, func(i, j int) bool {
+		return riskLevelOrder[risks[i].Level] > riskLevelOrder[risks[j].Level]
+	})
+	report.Risks = risks
+
+	// 整体等级不低于单项风险的最高等级
+	report.OverallLevel = strings.ToLower(strings.TrimSpace(report.OverallLevel))
+	if _, ok := riskLevelOrder[report.OverallLevel]; !ok || riskLevelOrder[highest] > riskLevelOrder[report.OverallLevel] {
+		report.OverallLevel = highest
+	}
+
+	if report.Conclusion == "" && len(risks) == 0 {
+		report.Conclusion = "未发现明显风险"
+	}
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrDiscussionNotFound 多角色扮演讨论记录不存在
+var ErrDiscussionNotFound = errors.New("讨论记录不存在")
+
+// RoleplayDiscussion 一次多角色扮演讨论的完整记录
+type RoleplayDiscussion struct {
+	ID           string              `json:"id"`
+	MeetingID    string              `json:"meeting_id"`
+	Host         string              `json:"host"`
+	Specialists  []string            `json:"specialists"`
+	Rounds       int                 `json:"rounds"`
+	Topic        string              `json:"topic"`
+	Temperature  *float32            `json:"temperature,omitempty"`   // 请求中指定的发言温度，未指定时省略
+	SummaryStyle string              `json:"summary_style,omitempty"` // 总结格式，旧记录没有该字段时为默认的 narrative
+	Messages     []DiscussionMessage `json:"messages,omitempty"`      // 列表接口中省略
+	Summary      string              `json:"summary"`
+	EarlyStop    *EarlyStopResult    `json:"early_stop,omitempty"` // 开启提前结束时的实际轮数
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// roleplayHistoryDir 返回讨论记录的存储目录
+func roleplayHistoryDir() string {
+	return filepath.Join(dataDir(), "roleplay")
+}
+
+// SaveRoleplayDiscussion 保存讨论的请求参数、发言记录和总结，返回记录ID（<会议ID>_<时间戳>）
+func SaveRoleplayDiscussion(req *MultiRoleplayRequest, resp *MultiRoleplayResponse) (string, error) {
+	if !validMeetingID(req.MeetingID) {
+		return "", fmt.Errorf("无效的会议ID: %s", req.MeetingID)
+	}
+
+	now := time.Now()
+	discussion := RoleplayDiscussion{
+		ID:           fmt.Sprintf("%s_%s%03d", req.MeetingID, now.Format("20060102150405"), now.Nanosecond()/int(time.Millisecond)),
+		MeetingID:    req.MeetingID,
+		Host:         req.Host,
+		Specialists:  req.Specialists,
+		Rounds:       req.Rounds,
+		Topic:        req.Topic,
+		Temperature:  req.Temperature,
+		SummaryStyle: req.SummaryStyle,
+		Messages:     resp.Messages,
+		Summary:      resp.Summary,
+		EarlyStop:    resp.EarlyStop,
+		CreatedAt:    now,
+	}
+
+	data, err := json.Marshal(discussion)
+	if err != nil {
+		return "", fmt.Errorf("序列化讨论记录失败: %v", err)
+	}
+
+	if err := os.MkdirAll(roleplayHistoryDir(), 0755); err != nil {
+		return "", fmt.Errorf("创建讨论记录目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleplayHistoryDir(), discussion.ID+".json"), data, 0644); err != nil {
+		return "", fmt.Errorf("保存讨论记录失败: %v", err)
+	}
+
+	return discussion.ID, nil
+}
+
+// GetRoleplayDiscussion 获取一次讨论的完整记录
+func GetRoleplayDiscussion(id string) (*RoleplayDiscussion, error) {
+	if !validMeetingID(id) {
+		return nil, ErrDiscussionNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(roleplayHistoryDir(), id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrDiscussionNotFound
+		}
+		return nil, fmt.Errorf("读取讨论记录失败: %v", err)
+	}
+
+	var discussion RoleplayDiscussion
+	if err := json.Unmarshal(data, &discussion); err != nil {
+		return nil, fmt.Errorf("解析讨论记录失败: %v", err)
+	}
+	return &discussion, nil
+}
+
+// ListRoleplayDiscussions 列出会议的历史讨论（不含发言记录），按创建时间从新到旧排列
+func ListRoleplayDiscussions(meetingID string) ([]RoleplayDiscussion, error) {
+	discussions := []RoleplayDiscussion{}
+	if !validMeetingID(meetingID) {
+		return discussions, nil
+	}
+
+	entries, err := os.ReadDir(roleplayHistoryDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return discussions, nil
+		}
+		return nil, fmt.Errorf("读取讨论记录目录失败: %v", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, meetingID+"_") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		discussion, err := GetRoleplayDiscussion(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			fmt.Printf("读取讨论记录 %s 失败: %v\n", name, err)
+			continue
+		}
+		// 文件名前缀可能与其他会议ID重叠，以记录中的会议ID为准
+		if discussion.MeetingID != meetingID {
+			continue
+		}
+
+		discussion.Messages = nil
+		discussions = append(discussions, *discussion)
+	}
+
+	sort.Slice(discussions, func(i, j int) bool {
+		return discussions[i].CreatedAt.After(discussions[j].CreatedAt)
+	})
+
+	return discussions, nil
+}
+
+// DiscussionRound 一轮讨论在完整发言记录中的位置，Offset 为该轮第一条消息的下标
+type DiscussionRound struct {
+	Round  int `json:"round"`
+	Offset int `json:"offset"`
+	Count  int `json:"count"`
+}
+
+// RoundBoundaries 返回每轮讨论在发言记录中的起止位置，按轮次排列；不属于任何轮次的开场和总结消息不计入
+func (d *RoleplayDiscussion) RoundBoundaries() []DiscussionRound {
+	rounds := []DiscussionRound{}
+	for i, message := range d.Messages {
+		if message.Round <= 0 {
+			continue
+		}
+		if n := len(rounds); n > 0 && rounds[n-1].Round == message.Round {
+			rounds[n-1].Count++
+			continue
+		}
+		rounds = append(rounds, DiscussionRound{Round: message.Round, Offset: i, Count: 1})
+	}
+	return rounds
+}
+
+// MessagesPage 分页获取发言记录，round 大于0时只返回该轮的消息，offset 和 limit 在筛选后的消息中计算，
+// 返回本页消息和筛选后的消息总数
+func (d *RoleplayDiscussion) MessagesPage(round, offset, limit int) ([]DiscussionMessage, int) {
+	messages := d.Messages
+	if round > 0 {
+		messages = nil
+		for _, message := range d.Messages {
+			if message.Round == round {
+				messages = append(messages, message)
+			}
+		}
+	}
+
+	page := []DiscussionMessage{}
+	total := len(messages)
+	if offset >= total {
+		return page, total
+	}
+	end := total
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return append(page, messages[offset:end]...), total
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+// scoreArtifactKind 会议评分在缓存中的类型
+const scoreArtifactKind = "score"
+
+// 会议评分对比的限制
+const (
+	scoreCompareWorkers = 4  // 同时评估的会议数
+	MaxCompareMeetings  = 50 // 单次最多对比的会议数
+)
+
+// ScoredMeeting 对比结果中的单个会议评分
+type ScoredMeeting struct {
+	MeetingID string        `json:"meeting_id"`
+	Title     string        `json:"title"`
+	Score     *MeetingScore `json:"score"`
+	Cached    bool          `json:"cached"` // 是否来自缓存
+}
+
+// ScoreAverage 参与对比的会议各项得分的平均值
+type ScoreAverage struct {
+	GoalAchievement       float64 `json:"goal_achievement"`
+	TopicFocus            float64 `json:"topic_focus"`
+	ParticipantEngagement float64 `json:"participant_engagement"`
+	TotalScore            float64 `json:"total_score"`
+	ScorePercentage       float64 `json:"score_percentage"`
+	Grade                 string  `json:"grade"` // 平均得分百分比对应的等级
+}
+
+// ScoreFailure 评估失败的会议
+type ScoreFailure struct {
+	MeetingID string `json:"meeting_id"`
+	Error     string `json:"error"`
+}
+
+// ScoreComparison 多个会议的评分对比结果
+type ScoreComparison struct {
+	Meetings []ScoredMeeting `json:"meetings"`  // 按总分降序排列
+	Average  *ScoreAverage   `json:"average"`   // 没有评估成功的会议时为 null
+	NotFound []string        `json:"not_found"` // 不存在的会议ID
+	Failed   []ScoreFailure  `json:"failed"`    // 评估失败的会议
+}
+
+// GetCachedMeetingScore 获取会议评分，会议内容未变化时使用缓存的评分，返回值 cached 表示是否来自缓存
+func GetCachedMeetingScore(ctx context.Context, meetingID string) (*MeetingScore, bool, error) {
+	return getMeetingScore(ctx, meetingID, true)
+}
+
+// LoadCachedMeetingScore 只读取缓存的会议评分，不调用模型；没有可用的缓存时返回 nil
+func LoadCachedMeetingScore(ctx context.Context, meetingID string) (*MeetingScore, error) {
+	score, _, err := getMeetingScore(ctx, meetingID, false)
+	return score, err
+}
+
+// getMeetingScore 优先使用缓存的会议评分，没有缓存且 evaluate 为 true 时调用模型评估并缓存结果
+func getMeetingScore(ctx context.Context, meetingID string, evaluate bool) (*MeetingScore, bool, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return nil, false, err
+	}
+	metadata, _ := GetMeetingMetadata(meetingData)
+	meetingContent := MeetingDataContent(meetingData)
+	meetingInfo := metadata.Describe()
+
+	// 会议内容或回答语言变化后缓存自动失效
+	sourceHash := HashContent(meetingInfo, meetingContent, LocaleFromContext(ctx))
+
+	var cached MeetingScore
+	if LoadCachedArtifact(meetingID, scoreArtifactKind, sourceHash, &cached) {
+		// 等级阈值可能在缓存后调整，按当前配置重新划分
+		cached.Grade = ScoreGrade(cached.ScorePercentage)
+		return &cached, true, nil
+	}
+	if !evaluate {
+		return nil, false, nil
+	}
+
+	score, err := EvaluateMeeting(ctx, meetingInfo+"\n会议内容:\n"+meetingContent+ParticipationScoreNote(meetingData), metadata.MeetingType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 部分评分不缓存，下次请求时重新评估
+	if !score.Partial {
+		if err := SaveCachedArtifact(meetingID, scoreArtifactKind, sourceHash, score); err != nil {
+			// 缓存失败不影响本次结果
+			Logf(ctx, "缓存会议评分失败: %v\n", err)
+		}
+	}
+
+	return score, false, nil
+}
+
+// meetingScoreResult 单个会议的评估结果
+type meetingScoreResult struct {
+	scored   ScoredMeeting
+	notFound bool
+	err      error
+}
+
+// scoreMeeting 评估单个会议，会议不存在时 notFound 为 true
+func scoreMeeting(ctx context.Context, meetingID string) meetingScoreResult {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return meetingScoreResult{notFound: errors.Is(err, ErrMeetingNotFound), err: err}
+	}
+
+	score, cached, err := GetCachedMeetingScore(ctx, meetingID)
+	if err != nil {
+		return meetingScoreResult{notFound: errors.Is(err, ErrMeetingNotFound), err: err}
+	}
+
+	scored := ScoredMeeting{MeetingID: meetingID, Score: score, Cached: cached}
+	metadata, _ := GetMeetingMetadata(meetingData)
+	scored.Title = metadata.Title
+	return meetingScoreResult{scored: scored}
+}
+
+// CompareMeetingScores 并发评估多个会议并按总分降序排列，不存在或评估失败的会议单独列出而不影响其他会议。
+// 所有评估共享 ctx，ctx 取消后尚未开始的评估不再进行
+func CompareMeetingScores(ctx context.Context, meetingIDs []string) *ScoreComparison {
+	// 去除重复的会议ID，保持请求中的顺序
+	seen := make(map[string]bool)
+	ids := make([]string, 0, len(meetingIDs))
+	for _, id := range meetingIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	results := make([]meetingScoreResult, len(ids))
+
+	workers := scoreCompareWorkers
+	if len(ids) < workers {
+		workers = len(ids)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = scoreMeeting(ctx, ids[i])
+			}
+		}()
+	}
+
+	for i := range ids {
+		if ctx.Err() != nil {
+			results[i] = meetingScoreResult{err: ctx.Err()}
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	comparison := &ScoreComparison{
+		Meetings: []ScoredMeeting{},
+		NotFound: []string{},
+		Failed:   []ScoreFailure{},
+	}
+	for i, result := range results {
+		switch {
+		case result.notFound:
+			comparison.NotFound = append(comparison.NotFound, ids[i])
+		case result.err != nil:
+			comparison.Failed = append(comparison.Failed, ScoreFailure{MeetingID: ids[i], Error: result.err.Error()})
+		default:
+			comparison.Meetings = append(comparison.Meetings, result.scored)
+		}
+	}
+
+	// 总分相同时保持请求中的顺序
+	sort.SliceStable(comparison.Meetings, func(i, j int) bool {
+		return comparison.Meetings[i].Score.TotalScore > comparison.Meetings[j].Score.TotalScore
+	})
+
+	if n := float64(len(comparison.Meetings)); n > 0 {
+		average := &ScoreAverage{}
+		for _, m := range comparison.Meetings {
+			average.GoalAchievement += float64(m.Score.GoalAchievement) / n
+			average.TopicFocus += float64(m.Score.TopicFocus) / n
+			average.ParticipantEngagement += float64(m.Score.ParticipantEngagement) / n
+			average.TotalScore += float64(m.Score.TotalScore) / n
+			average.ScorePercentage += m.Score.ScorePercentage / n
+		}
+		// 平均值保留两位小数
+		for _, v := range []*float64{&average.GoalAchievement, &average.TopicFocus, &average.ParticipantEngagement, &average.TotalScore, &average.ScorePercentage} {
+			*v = math.Round(*v*100) / 100
+		}
+		average.Grade = ScoreGrade(average.ScorePercentage)
+		comparison.Average = average
+	}
+
+	return comparison
+}
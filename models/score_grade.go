@@ -0,0 +1,48 @@
+package models
+
+import "sort"
+
+// GradeThreshold 会议评分等级及其最低得分百分比
+type GradeThreshold struct {
+	Grade         string  `json:"grade"`
+	MinPercentage float64 `json:"min_percentage"`
+}
+
+// defaultGradeThresholds 未配置时使用的等级阈值
+var defaultGradeThresholds = []GradeThreshold{
+	{Grade: "A", MinPercentage: 85},
+	{Grade: "B", MinPercentage: 70},
+	{Grade: "C", MinPercentage: 50},
+	{Grade: "D", MinPercentage: 0},
+}
+
+// GetGradeThresholds 获取评分等级阈值，按最低得分百分比降序排列，未配置时使用默认值
+func GetGradeThresholds() []GradeThreshold {
+	cfg, err := LoadConfig()
+	if err != nil || len(cfg.Score.GradeThresholds) == 0 {
+		return defaultGradeThresholds
+	}
+
+	thresholds := make([]GradeThreshold, 0, len(cfg.Score.GradeThresholds))
+	for grade, minPercentage := range cfg.Score.GradeThresholds {
+		thresholds = append(thresholds, GradeThreshold{Grade: grade, MinPercentage: minPercentage})
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		if thresholds[i].MinPercentage != thresholds[j].MinPercentage {
+			return thresholds[i].MinPercentage > thresholds[j].MinPercentage
+		}
+		return thresholds[i].Grade < thresholds[j].Grade
+	})
+	return thresholds
+}
+
+// ScoreGrade 返回得分百分比对应的等级，低于所有阈值时取最低一档
+func ScoreGrade(percentage float64) string {
+	thresholds := GetGradeThresholds()
+	for _, threshold := range thresholds {
+		if percentage >= threshold.MinPercentage {
+			return threshold.Grade
+		}
+	}
+	return thresholds[len(thresholds)-1].Grade
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// criterionScorePattern 匹配已生成完整的指标得分，数字之后必须出现分隔符，避免把 "3" 之后还未生成的 ".5" 截断
+var criterionScorePattern = regexp.MustCompile(`"(goal_achievement|topic_focus|participant_engagement)"\s*:\s*(\d+(?:\.\d+)?)\s*[,}\n]`)
+
+// StreamEvaluateMeeting 使用评分提示流式评估会议：生成过程中以 {"data": 片段} 推送模型输出，
+// 每个指标的得分生成完成后推送一次 {"criterion": 指标, "score": 得分}，
+// 最后推送 {"done": true, "score": 完整评分}，完整评分的计算方式与 EvaluateMeeting 相同。
+// ctx 取消时停止生成并返回 ctx.Err()
+func StreamEvaluateMeeting(ctx context.Context, documentText, meetingType string, stream EventPublisher) error {
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureScore, 0.2) // 低温度以获得一致的评估结果
+	if err != nil {
+		Logf(ctx, "创建LLM客户端失败: %v\n", err)
+		return publishStreamError(stream, "错误: 创建聊天模型失败")
+	}
+
+	systemPrompt, err := RenderPrompt(PromptScore, PromptData{MeetingContent: documentText})
+	if err != nil {
+		return publishStreamError(stream, "评估会议失败: "+err.Error())
+	}
+	systemPrompt = withMeetingTypeRubric(systemPrompt, meetingType)
+
+	messages := []*schema.Message{
+		schema.SystemMessage(withLocaleInstruction(ctx, systemPrompt)),
+		schema.UserMessage(documentText),
+	}
+
+	// 使用流式生成评估结果，超时后停止读取上游流
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	reader, err := chatModel.Stream(callCtx, messages)
+	recordLLMCall(ctx, nil, err)
+	if err != nil {
+		Logf(ctx, "failed to generate streaming response: %v", err)
+		return publishStreamError(stream, "错误: 生成流式回答失败")
+	}
+	defer reader.Close()
+
+	var content strings.Builder
+	guard := newOutputGuard()
+	published := make(map[string]bool)
+	for {
+		chunk, err := reader.Recv()
+		recordLLMUsage(ctx, chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			Logf(ctx, "接收流式评估结果失败: %v\n", err)
+			return publishStreamError(stream, streamErrorMessage(callCtx, err))
+		}
+
+		output, exceeded := guard.Push(chunk.Content)
+		if err := publishChatChunk(stream, &content, output); err != nil {
+			return err
+		}
+		if err := publishCriterionScores(stream, content.String(), published); err != nil {
+			return err
+		}
+		if exceeded {
+			break
+		}
+	}
+
+	var evaluation map[string]interface{}
+	if err := parseJSONObject(content.String(), &evaluation); err != nil {
+		return publishStreamError(stream, fmt.Sprintf("解析评估结果失败: %v", err))
+	}
+
+	// 缺少指标时的补全重试与非流式评估相同
+	score, err := buildMeetingScore(ctx, chatModel, systemPrompt, documentText, evaluation)
+	if err != nil {
+		return publishStreamError(stream, err.Error())
+	}
+	return publishStreamDone(stream, map[string]interface{}{"score": score})
+}
+
+// publishCriterionScores 推送已生成完成且尚未推送过的指标得分，不在1到4之间的得分不推送
+func publishCriterionScores(stream EventPublisher, content string, published map[string]bool) error {
+	for _, match := range criterionScorePattern.FindAllStringSubmatch(content, -1) {
+		criterion := match[1]
+		if published[criterion] {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[2], 64)
+		if err != nil || value < 1 || value > maxCriterionScore {
+			continue
+		}
+		published[criterion] = true
+
+		data, _ := json.Marshal(map[string]interface{}{"criterion": criterion, "score": int(math.Round(value))})
+		if err := stream.Publish(NewStreamEvent(StreamEventScore, data)); err != nil {
+			fmt.Printf("发送SSE事件失败: %v", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// 会议搜索的方式
+const (
+	SearchModeKeyword  = "keyword"
+	SearchModeSemantic = "semantic"
+)
+
+// 会议搜索返回条数的默认值和上限
+const (
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 50
+)
+
+// MeetingSearchOptions 会议搜索参数
+type MeetingSearchOptions struct {
+	Semantic        bool // 按向量相似度排序，向量不可用时退回关键词搜索
+	Limit           int
+	IncludeArchived bool
+}
+
+// MeetingSearchResult 一条搜索结果，Score 为关键词命中比例或余弦相似度
+type MeetingSearchResult struct {
+	MeetingID string  `json:"meeting_id"`
+	Title     string  `json:"title"`
+	Summary   string  `json:"summary"`
+	Score     float64 `json:"score"`
+}
+
+// MeetingSearchResponse 会议搜索结果，FallbackReason 说明语义搜索退回关键词搜索的原因
+type MeetingSearchResponse struct {
+	Query          string                `json:"query"`
+	Mode           string                `json:"mode"`
+	FallbackReason string                `json:"fallback_reason,omitempty"`
+	Results        []MeetingSearchResult `json:"results"`
+}
+
+// searchCandidate 参与搜索的会议
+type searchCandidate struct {
+	meetingID string
+	metadata  MeetingMetadata
+	tags      []string
+}
+
+// SearchMeetings 搜索会议。默认按关键词匹配标题、描述、摘要、参会人员和标签；
+// Semantic 为 true 时按查询与会议标题和摘要的向量相似度排序，缺少向量的会议在搜索时补算，
+// 未配置向量模型或向量接口调用失败时退回关键词搜索。默认不搜索已归档的会议
+func SearchMeetings(ctx context.Context, query string, opts MeetingSearchOptions) (*MeetingSearchResponse, error) {
+	candidates, err := loadSearchCandidates(opts.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MeetingSearchResponse{Query: query, Mode: SearchModeKeyword}
+	var results []MeetingSearchResult
+	if opts.Semantic {
+		results, err = semanticSearch(ctx, query, candidates)
+		if err != nil {
+			Logf(ctx, "语义搜索失败，使用关键词搜索: %v\n", err)
+			response.FallbackReason = err.Error()
+		} else {
+			response.Mode = SearchModeSemantic
+		}
+	}
+	if response.Mode == SearchModeKeyword {
+		results = keywordSearch(query, candidates)
+	}
+
+	// 分数相同时较新的会议在前，会议ID以创建时间开头
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].MeetingID > results[j].MeetingID
+	})
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	response.Results = results
+	return response, nil
+}
+
+// loadSearchCandidates 读取所有有元数据的会议，读取失败的会议跳过
+func loadSearchCandidates(includeArchived bool) ([]searchCandidate, error) {
+	meetingIDs, err := ListMeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]searchCandidate, 0, len(meetingIDs))
+	for _, meetingID := range meetingIDs {
+		meetingData, err := LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("读取会议 %s 失败: %v\n", meetingID, err)
+			continue
+		}
+		raw, ok := meetingData["metadata"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if IsMeetingArchived(raw) && !includeArchived {
+			continue
+		}
+		candidates = append(candidates, searchCandidate{
+			meetingID: meetingID,
+			metadata:  DecodeMeetingMetadata(raw),
+			tags:      GetMeetingTags(raw),
+		})
+	}
+	return candidates, nil
+}
+
+// keywordSearch 按空白拆分查询词，忽略大小写匹配会议文本，分数为命中的查询词比例，没有命中的会议不返回
+func keywordSearch(query string, candidates []searchCandidate) []MeetingSearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	results := []MeetingSearchResult{}
+	if len(terms) == 0 {
+		return results
+	}
+
+	for _, candidate := range candidates {
+		metadata := candidate.metadata
+		text := strings.ToLower(strings.Join([]string{
+			metadata.Title,
+			metadata.Description,
+			metadata.Summary,
+			strings.Join(metadata.Participants, " "),
+			strings.Join(candidate.tags, " "),
+		}, "\n"))
+
+		matched := 0
+		for _, term := range terms {
+			if strings.Contains(text, term) {
+				matched++
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		results = append(results, newSearchResult(candidate, float64(matched)/float64(len(terms))))
+	}
+	return results
+}
+
+// semanticSearch 按向量相似度为所有会议打分，缓存中没有向量的会议一次性补算并写入缓存
+func semanticSearch(ctx context.Context, query string, candidates []searchCandidate) ([]MeetingSearchResult, error) {
+	embedder, err := GetEmbedder()
+	if err != nil {
+		return nil, err
+	}
+	modelName := GetEmbeddingSettings().ModelName
+
+	vectors := make([][]float64, len(candidates))
+	var missing []int
+	var texts []string
+	for i, candidate := range candidates {
+		var vector []float64
+		if LoadCachedArtifact(candidate.meetingID, embeddingArtifactKind, meetingEmbeddingHash(candidate.metadata, modelName), &vector) {
+			vectors[i] = vector
+			continue
+		}
+		missing = append(missing, i)
+		texts = append(texts, meetingEmbeddingText(candidate.metadata))
+	}
+
+	// 查询与缺少向量的会议一起计算，减少请求次数
+	embedded, err := embedder.Embed(ctx, append([]string{query}, texts...))
+	if err != nil {
+		return nil, err
+	}
+	queryVector := embedded[0]
+	for n, i := range missing {
+		vectors[i] = embedded[n+1]
+		candidate := candidates[i]
+		if err := SaveCachedArtifact(candidate.meetingID, embeddingArtifactKind, meetingEmbeddingHash(candidate.metadata, modelName), vectors[i]); err != nil {
+			// 缓存失败不影响本次结果
+			Logf(ctx, "缓存会议 %s 向量失败: %v\n", candidate.meetingID, err)
+		}
+	}
+
+	results := make([]MeetingSearchResult, 0, len(candidates))
+	for i, candidate := range candidates {
+		results = append(results, newSearchResult(candidate, cosineSimilarity(queryVector, vectors[i])))
+	}
+	return results, nil
+}
+
+// newSearchResult 创建搜索结果，分数保留4位小数
+func newSearchResult(candidate searchCandidate, score float64) MeetingSearchResult {
+	return MeetingSearchResult{
+		MeetingID: candidate.meetingID,
+		Title:     candidate.metadata.Title,
+		Summary:   candidate.metadata.Summary,
+		Score:     math.Round(score*10000) / 10000,
+	}
+}
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Slack Block Kit 的限制
+const (
+	slackMaxBlocks      = 50   // 单条消息最多包含的block数
+	slackMaxSectionText = 3000 // section block文本的最大字符数
+	slackMaxHeaderText  = 150  // header block文本的最大字符数
+)
+
+// SlackMessage Slack incoming webhook 消息
+type SlackMessage struct {
+	Text   string       `json:"text"` // 通知预览中显示的纯文本
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock Slack Block Kit 中的一个block
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+// SlackText Slack Block Kit 中的文本对象
+type SlackText struct {
+	Type string `json:"type"` // plain_text 或 mrkdwn
+	Text string `json:"text"`
+}
+
+// SlackNotifier Slack incoming webhook 推送
+type SlackNotifier struct{}
+
+// GetSlackWebhookURL 获取Slack Webhook URL，优先读取配置文件，其次读取 SLACK_WEBHOOK_URL 环境变量
+func GetSlackWebhookURL() (string, error) {
+	if cfg, err := LoadConfig(); err == nil && cfg.Slack.WebhookURL != "" {
+		return cfg.Slack.WebhookURL, nil
+	}
+
+	if webhookURL := os.Getenv("SLACK_WEBHOOK_URL"); webhookURL != "" {
+		return webhookURL, nil
+	}
+
+	return "", fmt.Errorf("Slack Webhook URL未配置")
+}
+
+// Name 返回渠道名称
+func (n *SlackNotifier) Name() string {
+	return NotifierSlack
+}
+
+// SendReport 推送会议报告到Slack，超出block数量限制时拆分为多条消息
+func (n *SlackNotifier) SendReport(report *MeetingReport) error {
+	webhookURL, err := GetSlackWebhookURL()
+	if err != nil {
+		return fmt.Errorf("获取Slack Webhook URL失败: %v", err)
+	}
+
+	for _, message := range BuildSlackReportMessages(report) {
+		if err := postWebhookJSON(webhookURL, message); err != nil {
+			return fmt.Errorf("发送消息到Slack失败: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// BuildReportMessages 返回推送到Slack的会议报告消息，超出block数量限制时为多条
+func (n *SlackNotifier) BuildReportMessages(report *MeetingReport) []interface{} {
+	var messages []interface{}
+	for _, message := range BuildSlackReportMessages(report) {
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// SendText 推送文本通知到Slack
+func (n *SlackNotifier) SendText(title, content string) error {
+	webhookURL, err := GetSlackWebhookURL()
+	if err != nil {
+		return fmt.Errorf("获取Slack Webhook URL失败: %v", err)
+	}
+
+	blocks := []SlackBlock{slackHeaderBlock(title)}
+	blocks = append(blocks, slackSectionBlocks(content)...)
+	if len(blocks) > slackMaxBlocks {
+		blocks = blocks[:slackMaxBlocks]
+	}
+
+	message := SlackMessage{
+		Text:   title,
+		Blocks: blocks,
+	}
+	if err := postWebhookJSON(webhookURL, message); err != nil {
+		return fmt.Errorf("发送消息到Slack失败: %v", err)
+	}
+
+	return nil
+}
+
+// BuildSlackReportMessages 按报告模板将会议报告转换为Block Kit消息。
+// 默认结构为 header、描述/摘要 section、divider、参会人员 context、评分 section，以及按长度分段的待办事项 section
+func BuildSlackReportMessages(report *MeetingReport) []SlackMessage {
+	blocks := []SlackBlock{slackHeaderBlock(report.Title)}
+
+	for _, section := range GetReportSections() {
+		switch section.Type {
+		case ReportSectionDivider:
+			blocks = append(blocks, SlackBlock{Type: "divider"})
+		case ReportSectionParticipants:
+			if len(report.Participants) > 0 {
+				blocks = append(blocks, slackContextBlock(section.Title+"："+strings.Join(report.Participants, "、")))
+			}
+		case ReportSectionTodos:
+			blocks = append(blocks, slackTodoBlocks(section.Title, report.TodoList)...)
+		default:
+			if text := reportSectionText(report, section.Type); text != "" {
+				blocks = append(blocks, slackSectionBlocks("*"+section.Title+"：*\n"+text)...)
+			}
+		}
+	}
+
+	// 按block数量上限拆分为多条消息，后续消息重复标题以便识别
+	var messages []SlackMessage
+	for len(blocks) > 0 {
+		n := len(blocks)
+		if n > slackMaxBlocks {
+			n = slackMaxBlocks
+		}
+		messages = append(messages, SlackMessage{
+			Text:   report.Title,
+			Blocks: blocks[:n],
+		})
+		blocks = blocks[n:]
+		if len(blocks) > 0 {
+			blocks = append([]SlackBlock{slackHeaderBlock(report.Title + "（续）")}, blocks...)
+		}
+	}
+
+	return messages
+}
+
+// slackTodoBlocks 将待办事项按行累积为section，每个section不超过文本长度限制
+func slackTodoBlocks(title string, todoList []string) []SlackBlock {
+	if len(todoList) == 0 {
+		return nil
+	}
+
+	var blocks []SlackBlock
+	var sb strings.Builder
+	sb.WriteString("*" + title + "：*\n")
+	for i, todo := range todoList {
+		line := fmt.Sprintf("%d. %s\n", i+1, todo)
+		if utf8.RuneCountInString(sb.String())+utf8.RuneCountInString(line) > slackMaxSectionText {
+			blocks = append(blocks, slackSectionBlocks(sb.String())...)
+			sb.Reset()
+		}
+		sb.WriteString(line)
+	}
+	if sb.Len() > 0 {
+		blocks = append(blocks, slackSectionBlocks(sb.String())...)
+	}
+	return blocks
+}
+
+// slackHeaderBlock 创建header block
+func slackHeaderBlock(title string) SlackBlock {
+	return SlackBlock{
+		Type: "header",
+		Text: &SlackText{
+			Type: "plain_text",
+			Text: truncateRunes(title, slackMaxHeaderText),
+		},
+	}
+}
+
+// slackSectionBlocks 创建section block，文本超出长度限制时拆分为多个block
+func slackSectionBlocks(text string) []SlackBlock {
+	var blocks []SlackBlock
+	runes := []rune(text)
+	for len(runes) > 0 {
+		n := len(runes)
+		if n > slackMaxSectionText {
+			n = slackMaxSectionText
+		}
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{
+				Type: "mrkdwn",
+				Text: string(runes[:n]),
+			},
+		})
+		runes = runes[n:]
+	}
+	return blocks
+}
+
+// slackContextBlock 创建context block
+func slackContextBlock(text string) SlackBlock {
+	return SlackBlock{
+		Type: "context",
+		Elements: []SlackText{
+			{
+				Type: "mrkdwn",
+				Text: truncateRunes(text, slackMaxSectionText),
+			},
+		},
+	}
+}
+
+// truncateRunes 将字符串截断到不超过maxRunes个字符，截断时以省略号结尾
+func truncateRunes(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes-1]) + "…"
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// slackWebhook 模拟 Slack incoming webhook，记录收到的消息体
+type slackWebhook struct {
+	mu     sync.Mutex
+	bodies []string
+	status int
+}
+
+// newSlackWebhook 启动模拟 webhook 并通过 SLACK_WEBHOOK_URL 环境变量指向它
+func newSlackWebhook(t *testing.T) *slackWebhook {
+	t.Helper()
+	webhook := &slackWebhook{status: http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("webhook 请求 = %s %s，期望 POST application/json", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		webhook.mu.Lock()
+		webhook.bodies = append(webhook.bodies, string(body))
+		status := webhook.status
+		webhook.mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("SLACK_WEBHOOK_URL", server.URL)
+	return webhook
+}
+
+// messages 返回收到的消息，每条消息解析为通用的JSON值，与 Slack 实际收到的内容一致
+func (w *slackWebhook) messages(t *testing.T) []interface{} {
+	t.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	messages := make([]interface{}, 0, len(w.bodies))
+	for _, body := range w.bodies {
+		var message interface{}
+		if err := json.Unmarshal([]byte(body), &message); err != nil {
+			t.Fatalf("webhook 消息不是合法的JSON: %v\n%s", err, body)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// parseJSON 解析期望的JSON，用于与收到的消息比较
+func parseJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("期望的JSON无效: %v", err)
+	}
+	return v
+}
+
+func TestSlackSendReport(t *testing.T) {
+	webhook := newSlackWebhook(t)
+
+	report := &MeetingReport{
+		Title:        "预算评审会",
+		Description:  "评审下季度预算",
+		Summary:      "确定由张三整理预算表",
+		Participants: []string{"张三", "李四"},
+		TodoList:     []string{"整理预算表", "周五前提交"},
+	}
+	if err := (&SlackNotifier{}).SendReport(report); err != nil {
+		t.Fatalf("推送报告失败: %v", err)
+	}
+
+	// 没有时间和评分的报告省略对应的区块
+	want := parseJSON(t, `[{
+		"text": "预算评审会",
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "预算评审会"}},
+			{"type": "section", "text": {"type": "mrkdwn", "text": "*会议描述：*\n评审下季度预算"}},
+			{"type": "section", "text": {"type": "mrkdwn", "text": "*会议摘要：*\n确定由张三整理预算表"}},
+			{"type": "divider"},
+			{"type": "context", "elements": [{"type": "mrkdwn", "text": "参会人员：张三、李四"}]},
+			{"type": "section", "text": {"type": "mrkdwn", "text": "*待办事项：*\n1. 整理预算表\n2. 周五前提交\n"}}
+		]
+	}]`)
+	if got := webhook.messages(t); !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("webhook 收到的消息:\n%s", gotJSON)
+	}
+}
+
+func TestSlackSendReportSplitsMessages(t *testing.T) {
+	webhook := newSlackWebhook(t)
+
+	// 每个待办事项单独占满一个 section，超出单条消息的 block 数量上限
+	todos := make([]string, slackMaxBlocks+10)
+	for i := range todos {
+		todos[i] = strings.Repeat("待", slackMaxSectionText/2)
+	}
+	report := &MeetingReport{Title: "长会议", TodoList: todos}
+	if err := (&SlackNotifier{}).SendReport(report); err != nil {
+		t.Fatalf("推送报告失败: %v", err)
+	}
+
+	messages := webhook.messages(t)
+	if len(messages) != 2 {
+		t.Fatalf("收到 %d 条消息，期望 2", len(messages))
+	}
+	sections := 0
+	for i, message := range messages {
+		blocks := message.(map[string]interface{})["blocks"].([]interface{})
+		if len(blocks) > slackMaxBlocks {
+			t.Errorf("第%d条消息有 %d 个block，超过上限 %d", i+1, len(blocks), slackMaxBlocks)
+		}
+		// 后续消息重复标题以便识别
+		header := blocks[0].(map[string]interface{})
+		wantTitle := "长会议"
+		if i > 0 {
+			wantTitle = "长会议（续）"
+		}
+		if header["type"] != "header" || header["text"].(map[string]interface{})["text"] != wantTitle {
+			t.Errorf("第%d条消息的第一个block = %v，期望标题 %q", i+1, header, wantTitle)
+		}
+		for _, block := range blocks[1:] {
+			block := block.(map[string]interface{})
+			if block["type"] != "section" {
+				continue
+			}
+			sections++
+			if text := block["text"].(map[string]interface{})["text"].(string); len([]rune(text)) > slackMaxSectionText {
+				t.Errorf("section 文本有 %d 个字符，超过上限 %d", len([]rune(text)), slackMaxSectionText)
+			}
+		}
+	}
+	if sections != len(todos) {
+		t.Errorf("共 %d 个待办事项 section，期望 %d", sections, len(todos))
+	}
+}
+
+func TestSlackSendText(t *testing.T) {
+	webhook := newSlackWebhook(t)
+
+	title := strings.Repeat("标", slackMaxHeaderText+10)
+	content := strings.Repeat("内", slackMaxSectionText+1)
+	if err := (&SlackNotifier{}).SendText(title, content); err != nil {
+		t.Fatalf("推送文本失败: %v", err)
+	}
+
+	// 标题截断到 header 长度上限，超长内容拆分为多个 section
+	want := parseJSON(t, `[{
+		"text": "`+title+`",
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "`+truncateRunes(title, slackMaxHeaderText)+`"}},
+			{"type": "section", "text": {"type": "mrkdwn", "text": "`+strings.Repeat("内", slackMaxSectionText)+`"}},
+			{"type": "section", "text": {"type": "mrkdwn", "text": "内"}}
+		]
+	}]`)
+	if got := webhook.messages(t); !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("webhook 收到的消息:\n%s", gotJSON)
+	}
+}
+
+func TestSlackSendReportErrorStatus(t *testing.T) {
+	webhook := newSlackWebhook(t)
+	webhook.status = http.StatusBadRequest
+
+	err := (&SlackNotifier{}).SendReport(&MeetingReport{Title: "预算评审会"})
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("错误 = %v，期望包含状态码 400", err)
+	}
+}
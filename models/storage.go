@@ -0,0 +1,368 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 会议文件的扩展名，启用压缩时以 gzip 压缩后的 .json.gz 保存
+const (
+	meetingFileExt           = ".json"
+	compressedMeetingFileExt = ".json.gz"
+)
+
+// ErrMeetingNotFound 会议不存在
+var ErrMeetingNotFound = errors.New("会议不存在")
+
+// meetingLocks 按会议ID加锁，避免并发读改写同一会议文件时丢失更新
+var meetingLocks sync.Map
+
+// lockMeeting 获取指定会议的写锁，返回解锁函数
+func lockMeeting(meetingID string) func() {
+	value, _ := meetingLocks.LoadOrStore(meetingID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// validMeetingID 会议ID会被拼接到文件路径中，拒绝包含路径分隔符的ID
+func validMeetingID(meetingID string) bool {
+	return meetingID != "" && !strings.ContainsAny(meetingID, `/\`) && !strings.Contains(meetingID, "..")
+}
+
+// meetingsDir 返回会议文件的存储目录
+func meetingsDir() string {
+	return GetStorageSettings().MeetingsDir
+}
+
+// dataDir 返回缓存、模板等其他数据的存储目录
+func dataDir() string {
+	return GetStorageSettings().DataDir
+}
+
+// EnsureStorageDirs 创建数据存储所需的目录，会议文件不保存在本地目录时不创建会议目录
+func EnsureStorageDirs() error {
+	settings := GetStorageSettings()
+	dirs := []string{filepath.Dir(settings.TodoDB), settings.DataDir}
+	if settings.Backend == StorageBackendFilesystem {
+		dirs = append(dirs, settings.MeetingsDir)
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建存储目录 %s 失败: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+// 会议文件的存储后端，对应配置 storage.backend
+const (
+	StorageBackendFilesystem = "filesystem" // 本地目录 storage.meetings_dir（默认）
+	StorageBackendS3         = "s3"         // S3 兼容的对象存储，多个实例可共享同一份会议数据
+	StorageBackendSQLite     = "sqlite"     // 待办事项数据库 storage.todo_db 的 meetings 表，可与待办事项联表查询
+)
+
+// MeetingStore 会议文件的存储后端，保存的是序列化（启用字段加密时已加密）后的会议 JSON。
+// 启用 storage.compress 时由各实现以 gzip 压缩保存，并兼容读取另一种格式
+type MeetingStore interface {
+	// Save 保存会议文件，覆盖已有内容
+	Save(meetingID string, data []byte) error
+	// Load 读取会议文件，会议不存在时返回 ErrMeetingNotFound
+	Load(meetingID string) ([]byte, error)
+	// List 列出所有会议的ID，没有会议时返回空列表
+	List() ([]string, error)
+	// Delete 删除会议文件，会议不存在时返回 ErrMeetingNotFound
+	Delete(meetingID string) error
+}
+
+// meetingStoreOverride 通过 SetMeetingStore 指定的存储后端，为 nil 时按配置选择
+var meetingStoreOverride struct {
+	sync.RWMutex
+	store MeetingStore
+}
+
+// SetMeetingStore 使用指定的存储后端代替 storage.backend 配置，传入 nil 时恢复按配置选择。
+// 用于接入自定义存储，以及在测试中模拟存储失败
+func SetMeetingStore(store MeetingStore) {
+	meetingStoreOverride.Lock()
+	meetingStoreOverride.store = store
+	meetingStoreOverride.Unlock()
+}
+
+// GetMeetingStore 按配置 storage.backend 返回会议文件的存储后端，未配置时使用本地目录。
+// 每次调用按当前配置创建，重新加载配置后立即生效；通过 SetMeetingStore 指定了存储后端时直接返回
+func GetMeetingStore() MeetingStore {
+	meetingStoreOverride.RLock()
+	store := meetingStoreOverride.store
+	meetingStoreOverride.RUnlock()
+	if store != nil {
+		return store
+	}
+
+	settings := GetStorageSettings()
+	switch settings.Backend {
+	case StorageBackendS3:
+		return newS3MeetingStore(GetS3Settings(), settings.Compress)
+	case StorageBackendSQLite:
+		return newSQLiteMeetingStore(settings.TodoDB)
+	}
+	return &fileMeetingStore{dir: settings.MeetingsDir, compress: settings.Compress}
+}
+
+// meetingObjectNames 返回会议文件按当前压缩配置应使用的文件名和另一种格式的文件名
+func meetingObjectNames(meetingID string, compress bool) (current, other string) {
+	plain := meetingID + meetingFileExt
+	compressed := meetingID + compressedMeetingFileExt
+	if compress {
+		return compressed, plain
+	}
+	return plain, compressed
+}
+
+// meetingIDFromObjectName 从会议文件名中提取会议ID（去掉 .json 或 .json.gz 后缀），不是会议文件时返回 false
+func meetingIDFromObjectName(name string) (string, bool) {
+	if meetingID, ok := strings.CutSuffix(name, compressedMeetingFileExt); ok {
+		return meetingID, meetingID != ""
+	}
+	meetingID, ok := strings.CutSuffix(name, meetingFileExt)
+	return meetingID, ok && meetingID != ""
+}
+
+// encodeMeetingObject 按文件名的格式编码会议文件内容，.json.gz 文件以 gzip 压缩并在日志中输出压缩效果
+func encodeMeetingObject(meetingID, name string, data []byte) ([]byte, error) {
+	if !strings.HasSuffix(name, compressedMeetingFileExt) {
+		return data, nil
+	}
+	compressed, err := gzipMeetingData(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		fmt.Printf("会议 %s 压缩保存: %d 字节 -> %d 字节，节省 %.1f%%\n",
+			meetingID, len(data), len(compressed), 100*(1-float64(len(compressed))/float64(len(data))))
+	}
+	return compressed, nil
+}
+
+// decodeMeetingObject 按文件名的格式解码会议文件内容，.json.gz 文件解压后返回
+func decodeMeetingObject(name string, data []byte) ([]byte, error) {
+	if !strings.HasSuffix(name, compressedMeetingFileExt) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解压会议文件失败: %v", err)
+	}
+	defer reader.Close()
+	data, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("解压会议文件失败: %v", err)
+	}
+	return data, nil
+}
+
+// fileMeetingStore 将会议文件保存在本地目录中，单实例部署的默认后端
+type fileMeetingStore struct {
+	dir      string
+	compress bool
+}
+
+// Load 优先读取当前配置格式的文件，不存在时读取另一种格式，兼容切换压缩配置前保存的会议
+func (s *fileMeetingStore) Load(meetingID string) ([]byte, error) {
+	current, other := meetingObjectNames(meetingID, s.compress)
+	name := current
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		name = other
+		data, err = os.ReadFile(filepath.Join(s.dir, name))
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrMeetingNotFound
+		}
+		return nil, err
+	}
+	return decodeMeetingObject(name, data)
+}
+
+// Save 先写临时文件再重命名，避免读取到写了一半的文件；切换压缩配置后第一次保存时删除旧格式的文件
+func (s *fileMeetingStore) Save(meetingID string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("创建存储目录失败: %v", err)
+	}
+
+	current, other := meetingObjectNames(meetingID, s.compress)
+	data, err := encodeMeetingObject(meetingID, current, data)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(s.dir, current)
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("保存会议数据失败: %v", err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("保存会议数据失败: %v", err)
+	}
+	if err := os.Remove(filepath.Join(s.dir, other)); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("删除会议 %s 旧格式文件失败: %v\n", meetingID, err)
+	}
+	return nil
+}
+
+// List 列出目录中的会议文件，目录不存在时返回空列表
+func (s *fileMeetingStore) List() ([]string, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("无法读取会议列表: %v", err)
+	}
+
+	meetingIDs := make([]string, 0, len(files))
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		meetingID, ok := meetingIDFromObjectName(file.Name())
+		// 切换压缩配置时写入新文件和删除旧文件之间可能同时存在两种格式
+		if !ok || seen[meetingID] {
+			continue
+		}
+		seen[meetingID] = true
+		meetingIDs = append(meetingIDs, meetingID)
+	}
+	return meetingIDs, nil
+}
+
+// Delete 删除会议的两种格式的文件
+func (s *fileMeetingStore) Delete(meetingID string) error {
+	deleted := false
+	for _, name := range []string{meetingID + meetingFileExt, meetingID + compressedMeetingFileExt} {
+		err := os.Remove(filepath.Join(s.dir, name))
+		if err == nil {
+			deleted = true
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("删除会议文件失败: %v", err)
+		}
+	}
+	if !deleted {
+		return ErrMeetingNotFound
+	}
+	return nil
+}
+
+// LoadMeeting 读取并解析会议文件，会议不存在时返回 ErrMeetingNotFound
+func LoadMeeting(meetingID string) (map[string]interface{}, error) {
+	if !validMeetingID(meetingID) {
+		return nil, ErrMeetingNotFound
+	}
+
+	data, err := GetMeetingStore().Load(meetingID)
+	if err != nil {
+		if errors.Is(err, ErrMeetingNotFound) {
+			return nil, ErrMeetingNotFound
+		}
+		return nil, fmt.Errorf("无法读取会议信息: %v", err)
+	}
+
+	var meetingData map[string]interface{}
+	if err := json.Unmarshal(data, &meetingData); err != nil {
+		return nil, fmt.Errorf("无法解析会议数据: %v", err)
+	}
+
+	if err := decryptMeetingFields(meetingID, meetingData); err != nil {
+		return nil, fmt.Errorf("无法解密会议数据: %v", err)
+	}
+
+	return meetingData, nil
+}
+
+// ListMeetingIDs 列出所有已保存会议的ID，没有会议时返回空列表
+func ListMeetingIDs() ([]string, error) {
+	return GetMeetingStore().List()
+}
+
+// SaveMeeting 序列化会议数据并写入存储后端
+func SaveMeeting(meetingID string, meetingData map[string]interface{}) error {
+	if !validMeetingID(meetingID) {
+		return fmt.Errorf("无效的会议ID: %s", meetingID)
+	}
+
+	// 启用字段加密时敏感字段以密文落盘
+	storedData, err := encryptMeetingFields(meetingID, meetingData, GetEncryptedFields())
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(storedData)
+	if err != nil {
+		return fmt.Errorf("序列化会议数据失败: %v", err)
+	}
+
+	if err := GetMeetingStore().Save(meetingID, data); err != nil {
+		return err
+	}
+
+	// 新建会议或参会人员变化后重新统计
+	invalidateParticipantIndex()
+
+	return nil
+}
+
+// gzipMeetingData 以 gzip 压缩会议文件内容
+func gzipMeetingData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("压缩会议数据失败: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("压缩会议数据失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UpdateMeeting 在会议锁内读取会议数据并交给 update 修改后写回，
+// update 返回 false 表示无需写回，返回错误时放弃修改并返回该错误
+func UpdateMeeting(meetingID string, update func(meetingData map[string]interface{}) (bool, error)) error {
+	unlock := lockMeeting(meetingID)
+	defer unlock()
+
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return err
+	}
+
+	changed, err := update(meetingData)
+	if err != nil || !changed {
+		return err
+	}
+
+	return SaveMeeting(meetingID, meetingData)
+}
+
+// UpdateMeetingMetadata 在会议锁内读取会议元数据并交给 update 修改后写回，
+// update 返回 false 表示无需写回
+func UpdateMeetingMetadata(meetingID string, update func(metadata map[string]interface{}) bool) error {
+	return UpdateMeeting(meetingID, func(meetingData map[string]interface{}) (bool, error) {
+		metadata, ok := meetingData["metadata"].(map[string]interface{})
+		if !ok {
+			metadata = make(map[string]interface{})
+			meetingData["metadata"] = metadata
+		}
+		return update(metadata), nil
+	})
+}
@@ -0,0 +1,277 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 存储的默认配置
+const (
+	defaultS3Region  = "us-east-1"
+	defaultS3Prefix  = "meetings/"
+	defaultS3Timeout = 30 * time.Second
+)
+
+// S3Settings S3 兼容对象存储的连接参数
+type S3Settings struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string // 对象键前缀，非空时以 / 结尾
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // 临时凭证的会话令牌，来自环境变量 AWS_SESSION_TOKEN
+	PathStyle       bool
+	Timeout         time.Duration
+}
+
+// GetS3Settings 获取对象存储配置，访问密钥未配置时使用 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY 环境变量
+func GetS3Settings() S3Settings {
+	settings := S3Settings{
+		Region:  defaultS3Region,
+		Prefix:  defaultS3Prefix,
+		Timeout: defaultS3Timeout,
+	}
+
+	if cfg, err := LoadConfig(); err == nil {
+		s3 := cfg.Storage.S3
+		settings.Endpoint = strings.TrimRight(strings.TrimSpace(s3.Endpoint), "/")
+		settings.Bucket = strings.TrimSpace(s3.Bucket)
+		if region := strings.TrimSpace(s3.Region); region != "" {
+			settings.Region = region
+		}
+		if prefix := strings.Trim(strings.TrimSpace(s3.Prefix), "/"); prefix != "" {
+			settings.Prefix = prefix + "/"
+		}
+		settings.AccessKeyID = s3.AccessKeyID
+		settings.SecretAccessKey = s3.SecretAccessKey
+		settings.PathStyle = s3.PathStyle
+		if s3.TimeoutSeconds > 0 {
+			settings.Timeout = time.Duration(s3.TimeoutSeconds) * time.Second
+		}
+	}
+
+	if settings.AccessKeyID == "" {
+		settings.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if settings.SecretAccessKey == "" {
+		settings.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	settings.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	return settings
+}
+
+// s3MeetingStore 将会议文件保存为 S3 兼容对象存储中的对象，键为 <prefix><会议ID>.json（启用压缩时为 .json.gz），
+// 多个服务实例可以共享同一个存储桶
+type s3MeetingStore struct {
+	settings S3Settings
+	compress bool
+}
+
+// newS3MeetingStore 创建 S3 会议存储，客户端按连接参数缓存，各次创建的存储共用连接池
+func newS3MeetingStore(settings S3Settings, compress bool) *s3MeetingStore {
+	return &s3MeetingStore{settings: settings, compress: compress}
+}
+
+// s3Clients 按连接参数缓存的 S3 客户端，重新加载配置后按新参数创建
+var s3Clients sync.Map // S3Settings -> *minio.Client
+
+// client 返回连接参数对应的 S3 客户端，首次使用时创建
+func (s *s3MeetingStore) client() (*minio.Client, error) {
+	if client, ok := s3Clients.Load(s.settings); ok {
+		return client.(*minio.Client), nil
+	}
+
+	endpoint, err := url.Parse(s.settings.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("S3接口地址无效: %s", s.settings.Endpoint)
+	}
+	// 虚拟主机形式的地址将存储桶放在域名中，路径形式的地址将存储桶放在路径的第一段
+	lookup := minio.BucketLookupDNS
+	if s.settings.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(s.settings.AccessKeyID, s.settings.SecretAccessKey, s.settings.SessionToken),
+		Secure:       endpoint.Scheme == "https",
+		Region:       s.settings.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建S3客户端失败: %v", err)
+	}
+
+	actual, _ := s3Clients.LoadOrStore(s.settings, client)
+	return actual.(*minio.Client), nil
+}
+
+// context 返回单次请求使用的 context，超时时间为 storage.s3.timeout_seconds
+func (s *s3MeetingStore) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.settings.Timeout)
+}
+
+// Load 优先读取当前配置格式的对象，不存在时读取另一种格式
+func (s *s3MeetingStore) Load(meetingID string) ([]byte, error) {
+	current, other := meetingObjectNames(meetingID, s.compress)
+	for _, name := range []string{current, other} {
+		data, found, err := s.getObject(s.settings.Prefix + name)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return decodeMeetingObject(name, data)
+		}
+	}
+	return nil, ErrMeetingNotFound
+}
+
+// Save 写入当前配置格式的对象并删除另一种格式的对象。S3 的 PUT 是原子的，读取方不会看到写了一半的内容
+func (s *s3MeetingStore) Save(meetingID string, data []byte) error {
+	current, other := meetingObjectNames(meetingID, s.compress)
+	data, err := encodeMeetingObject(meetingID, current, data)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+	_, err = client.PutObject(ctx, s.settings.Bucket, s.settings.Prefix+current, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: "application/json"})
+	if err != nil {
+		return fmt.Errorf("保存会议数据失败: %v", err)
+	}
+
+	if err := s.deleteObject(s.settings.Prefix + other); err != nil {
+		fmt.Printf("删除会议 %s 旧格式对象失败: %v\n", meetingID, err)
+	}
+	return nil
+}
+
+// List 列出前缀下的会议对象，分页由客户端处理，忽略前缀下子目录中的对象
+func (s *s3MeetingStore) List() ([]string, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, fmt.Errorf("无法读取会议列表: %v", err)
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	meetingIDs := []string{}
+	seen := make(map[string]bool)
+	for object := range client.ListObjects(ctx, s.settings.Bucket, minio.ListObjectsOptions{Prefix: s.settings.Prefix}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("无法读取会议列表: %v", object.Err)
+		}
+		name := strings.TrimPrefix(object.Key, s.settings.Prefix)
+		if strings.Contains(name, "/") {
+			continue
+		}
+		meetingID, ok := meetingIDFromObjectName(name)
+		if !ok || seen[meetingID] {
+			continue
+		}
+		seen[meetingID] = true
+		meetingIDs = append(meetingIDs, meetingID)
+	}
+	return meetingIDs, nil
+}
+
+// Delete 删除会议的两种格式的对象。S3 删除不存在的对象同样返回成功，因此先确认会议存在
+func (s *s3MeetingStore) Delete(meetingID string) error {
+	names := []string{meetingID + meetingFileExt, meetingID + compressedMeetingFileExt}
+	exists := false
+	for _, name := range names {
+		found, err := s.headObject(s.settings.Prefix + name)
+		if err != nil {
+			return fmt.Errorf("删除会议文件失败: %v", err)
+		}
+		exists = exists || found
+	}
+	if !exists {
+		return ErrMeetingNotFound
+	}
+
+	for _, name := range names {
+		if err := s.deleteObject(s.settings.Prefix + name); err != nil {
+			return fmt.Errorf("删除会议文件失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// getObject 读取对象内容，对象不存在时 found 为 false
+func (s *s3MeetingStore) getObject(key string) (data []byte, found bool, err error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, false, err
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	// GetObject 在读取时才发出请求，对象不存在的错误由读取返回
+	object, err := client.GetObject(ctx, s.settings.Bucket, key, minio.GetObjectOptions{})
+	if err == nil {
+		defer object.Close()
+		data, err = io.ReadAll(object)
+	}
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("读取对象 %s 失败: %v", key, err)
+	}
+	return data, true, nil
+}
+
+// headObject 判断对象是否存在
+func (s *s3MeetingStore) headObject(key string) (bool, error) {
+	client, err := s.client()
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	if _, err := client.StatObject(ctx, s.settings.Bucket, key, minio.StatObjectOptions{}); err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteObject 删除对象，对象不存在时同样成功
+func (s *s3MeetingStore) deleteObject(key string) error {
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	if err := client.RemoveObject(ctx, s.settings.Bucket, key, minio.RemoveObjectOptions{}); err != nil && !isS3NotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isS3NotFound 判断错误是否为对象不存在
+func isS3NotFound(err error) bool {
+	return minio.ToErrorResponse(err).StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,227 @@
+package models
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 在内存中模拟 S3 接口的对象读写、删除和分页列举，只支持路径形式的地址
+type fakeS3 struct {
+	mu       sync.Mutex
+	bucket   string
+	objects  map[string][]byte
+	pageSize int // 每页最多返回的对象数，用于测试分页
+	lists    int // 收到的列举请求数
+}
+
+func newFakeS3(t *testing.T, pageSize int) (*fakeS3, S3Settings) {
+	t.Helper()
+	fake := &fakeS3{bucket: "meetings-bucket", objects: make(map[string][]byte), pageSize: pageSize}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, S3Settings{
+		Endpoint:        server.URL,
+		Region:          defaultS3Region,
+		Bucket:          fake.bucket,
+		Prefix:          defaultS3Prefix,
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		PathStyle:       true,
+		Timeout:         5 * time.Second,
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-access-key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key = strings.TrimPrefix(key, "/")
+
+	if key == "" && r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+		f.list(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			data = decodeAWSChunked(data)
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			f.notFound(w, r, key)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// decodeAWSChunked 去掉流式签名上传（aws-chunked）的分块头，得到对象内容
+func decodeAWSChunked(body []byte) []byte {
+	var data []byte
+	for len(body) > 0 {
+		header, rest, ok := strings.Cut(string(body), "\r\n")
+		if !ok {
+			break
+		}
+		sizeHex, _, _ := strings.Cut(header, ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || size == 0 || int(size) > len(rest) {
+			break
+		}
+		data = append(data, rest[:size]...)
+		body = []byte(strings.TrimPrefix(rest[size:], "\r\n"))
+	}
+	return data
+}
+
+func (f *fakeS3) notFound(w http.ResponseWriter, r *http.Request, key string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusNotFound)
+	if r.Method != http.MethodHead {
+		fmt.Fprintf(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message><Key>%s</Key></Error>`, key)
+	}
+}
+
+// list 按键排序后分页返回前缀下的对象，continuation-token 为上一页最后一个键
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	f.lists++
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) && key > query.Get("continuation-token") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	type content struct {
+		Key          string
+		Size         int
+		ETag         string
+		LastModified string
+	}
+	result := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		KeyCount              int
+		MaxKeys               int
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+		Contents              []content
+	}{Name: f.bucket, Prefix: prefix, MaxKeys: f.pageSize}
+	if len(keys) > f.pageSize {
+		keys = keys[:f.pageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{
+			Key: key, Size: len(f.objects[key]), ETag: `"etag"`, LastModified: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func TestS3MeetingStoreSaveLoadDelete(t *testing.T) {
+	fake, settings := newFakeS3(t, 1000)
+	store := newS3MeetingStore(settings, false)
+
+	const meetingID = "meeting_20250421112041"
+	data := []byte(`{"metadata":{"title":"周会"}}`)
+	if err := store.Save(meetingID, data); err != nil {
+		t.Fatalf("保存会议失败: %v", err)
+	}
+	if _, ok := fake.objects["meetings/"+meetingID+".json"]; !ok {
+		t.Fatalf("存储桶中的对象 = %v，期望写入 meetings/%s.json", fake.objects, meetingID)
+	}
+
+	loaded, err := store.Load(meetingID)
+	if err != nil {
+		t.Fatalf("读取会议失败: %v", err)
+	}
+	if string(loaded) != string(data) {
+		t.Errorf("读取的会议 = %s，期望 %s", loaded, data)
+	}
+
+	if _, err := store.Load("meeting_missing"); !errors.Is(err, ErrMeetingNotFound) {
+		t.Errorf("读取不存在的会议错误 = %v，期望 ErrMeetingNotFound", err)
+	}
+
+	if err := store.Delete(meetingID); err != nil {
+		t.Fatalf("删除会议失败: %v", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("删除后存储桶中仍有对象: %v", fake.objects)
+	}
+	if err := store.Delete(meetingID); !errors.Is(err, ErrMeetingNotFound) {
+		t.Errorf("重复删除的错误 = %v，期望 ErrMeetingNotFound", err)
+	}
+}
+
+func TestS3MeetingStoreListPaginates(t *testing.T) {
+	fake, settings := newFakeS3(t, 2)
+	store := newS3MeetingStore(settings, false)
+
+	fake.objects["meetings/meeting_1.json"] = []byte("{}")
+	fake.objects["meetings/meeting_2.json.gz"] = []byte("{}")
+	fake.objects["meetings/meeting_3.json"] = []byte("{}")
+	fake.objects["meetings/meeting_3.json.gz"] = []byte("{}")
+	fake.objects["meetings/meeting_4.json"] = []byte("{}")
+	fake.objects["meetings/archive/meeting_5.json"] = []byte("{}")
+	fake.objects["meetings/notes.txt"] = []byte("")
+	fake.objects["other/meeting_6.json"] = []byte("{}")
+
+	meetingIDs, err := store.List()
+	if err != nil {
+		t.Fatalf("列出会议失败: %v", err)
+	}
+	sort.Strings(meetingIDs)
+	want := []string{"meeting_1", "meeting_2", "meeting_3", "meeting_4"}
+	if !reflect.DeepEqual(meetingIDs, want) {
+		t.Errorf("会议列表 = %v，期望 %v", meetingIDs, want)
+	}
+	if fake.lists < 2 {
+		t.Errorf("收到 %d 次列举请求，期望按页读取多次", fake.lists)
+	}
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"meetingagent/sql"
+)
+
+// sqliteMeetingStore 将会议保存在待办事项数据库（storage.todo_db）的 meetings 表中，
+// 会议与待办事项在同一个数据库里，可以用 SQL 按 meeting_id 联表查询。
+// 元数据和原始内容单独成列以便查询，因此不使用 storage.compress 压缩
+type sqliteMeetingStore struct {
+	dbName string
+}
+
+// newSQLiteMeetingStore 创建 SQLite 会议存储，meetings 表在服务启动时由 InitMeetingStore 创建
+func newSQLiteMeetingStore(dbName string) *sqliteMeetingStore {
+	return &sqliteMeetingStore{dbName: dbName}
+}
+
+// InitMeetingStore 在服务启动时准备会议存储后端：storage.backend 为 sqlite 时创建 meetings 表，
+// 其他后端不需要初始化。通过重新加载配置切换到 sqlite 时需重启服务或先执行 -import-meetings 创建该表
+func InitMeetingStore() error {
+	settings := GetStorageSettings()
+	if settings.Backend != StorageBackendSQLite {
+		return nil
+	}
+	if err := sql.InitMeetingTable(settings.TodoDB); err != nil {
+		return fmt.Errorf("初始化会议表失败: %v", err)
+	}
+	return nil
+}
+
+// Save 将会议文件拆分为元数据、原始内容、标签和其他字段后写入 meetings 表
+func (s *sqliteMeetingStore) Save(meetingID string, data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("解析会议数据失败: %v", err)
+	}
+
+	record := &sql.MeetingRecord{ID: meetingID, Metadata: "{}", Tags: "[]"}
+	if metadata, ok := fields["metadata"]; ok {
+		record.Metadata = string(metadata)
+		delete(fields, "metadata")
+
+		// 元数据整体加密时是一个字符串，此时没有可查询的标签
+		var tagged struct {
+			Tags []string `json:"tags"`
+		}
+		if json.Unmarshal(metadata, &tagged) == nil && len(tagged.Tags) > 0 {
+			tags, _ := json.Marshal(tagged.Tags)
+			record.Tags = string(tags)
+		}
+	}
+	if rawContent, ok := fields["raw_content"]; ok {
+		var content string
+		if json.Unmarshal(rawContent, &content) == nil {
+			record.RawContent = &content
+			delete(fields, "raw_content")
+		}
+	}
+	extra, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("序列化会议数据失败: %v", err)
+	}
+	record.Extra = string(extra)
+	if createdAt, ok := MeetingCreatedAt(meetingID); ok {
+		record.CreatedAt = createdAt
+	}
+
+	if err := sql.SaveMeetingRecord(s.dbName, record); err != nil {
+		return fmt.Errorf("保存会议数据失败: %v", err)
+	}
+	return nil
+}
+
+// Load 读取 meetings 表中的会议并还原为会议文件的 JSON
+func (s *sqliteMeetingStore) Load(meetingID string) ([]byte, error) {
+	record, err := sql.GetMeetingRecord(s.dbName, meetingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrMeetingRecordNotFound) {
+			return nil, ErrMeetingNotFound
+		}
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(record.Extra), &fields); err != nil {
+		return nil, fmt.Errorf("解析会议数据失败: %v", err)
+	}
+	fields["metadata"] = json.RawMessage(record.Metadata)
+	if record.RawContent != nil {
+		rawContent, err := json.Marshal(*record.RawContent)
+		if err != nil {
+			return nil, fmt.Errorf("序列化会议数据失败: %v", err)
+		}
+		fields["raw_content"] = rawContent
+	}
+	return json.Marshal(fields)
+}
+
+// List 列出 meetings 表中所有会议的ID
+func (s *sqliteMeetingStore) List() ([]string, error) {
+	meetingIDs, err := sql.ListMeetingRecordIDs(s.dbName)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取会议列表: %v", err)
+	}
+	return meetingIDs, nil
+}
+
+// Delete 删除 meetings 表中的会议，会议的待办事项保留
+func (s *sqliteMeetingStore) Delete(meetingID string) error {
+	err := sql.DeleteMeetingRecord(s.dbName, meetingID)
+	if errors.Is(err, sql.ErrMeetingRecordNotFound) {
+		return ErrMeetingNotFound
+	}
+	return err
+}
+
+// ImportMeetingFiles 将 storage.meetings_dir 中的会议文件（包括压缩的 .json.gz）导入 SQLite 的 meetings 表，
+// 已导入的会议按文件内容覆盖，可重复执行；会议文件保持不变。返回导入的会议数
+func ImportMeetingFiles() (int, error) {
+	settings := GetStorageSettings()
+	if err := sql.InitMeetingTable(settings.TodoDB); err != nil {
+		return 0, err
+	}
+
+	source := &fileMeetingStore{dir: settings.MeetingsDir, compress: settings.Compress}
+	target := newSQLiteMeetingStore(settings.TodoDB)
+
+	meetingIDs, err := source.List()
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	imported := 0
+	for _, meetingID := range meetingIDs {
+		unlock := lockMeeting(meetingID)
+		data, err := source.Load(meetingID)
+		if err == nil {
+			err = target.Save(meetingID, data)
+		}
+		unlock()
+		if err != nil {
+			return imported, fmt.Errorf("导入会议 %s 失败: %v", meetingID, err)
+		}
+		imported++
+	}
+
+	fmt.Printf("已从 %s 导入 %d 个会议到 %s，耗时 %v\n", settings.MeetingsDir, imported, settings.TodoDB, time.Since(start).Round(time.Millisecond))
+	return imported, nil
+}
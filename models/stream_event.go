@@ -0,0 +1,30 @@
+package models
+
+import "github.com/hertz-contrib/sse"
+
+// SSE 事件名称，开启 stream.event_names 后写入事件的 event 字段，客户端可通过 addEventListener 按名称分别处理
+const (
+	StreamEventMessage = "message" // 聊天、角色扮演和流式评分的回答片段，以及多角色扮演中参会者的发言
+	StreamEventHandoff = "handoff" // 多角色扮演中切换到下一位参会者发言
+	StreamEventSystem  = "system"  // 多角色扮演中讨论开始、轮次开始、提前结束等系统消息
+	StreamEventSummary = "summary" // 多角色扮演的讨论总结
+	StreamEventScore   = "score"   // 流式评分中单个指标的得分
+	StreamEventError   = "error"   // 生成失败，流随即结束
+	StreamEventDone    = "done"    // 回答正常结束
+)
+
+// IsStreamEventNamesEnabled 是否为流式接口的事件设置名称，默认关闭，
+// 使用 EventSource.onmessage 的客户端只能收到未命名（或名为 message）的事件
+func IsStreamEventNamesEnabled() bool {
+	cfg, err := LoadConfig()
+	return err == nil && cfg.Stream.EventNames
+}
+
+// NewStreamEvent 创建流式接口的事件，开启 stream.event_names 时设置事件名称，数据内容不受影响
+func NewStreamEvent(name string, data []byte) *sse.Event {
+	event := &sse.Event{Data: data}
+	if IsStreamEventNamesEnabled() {
+		event.Event = name
+	}
+	return event
+}
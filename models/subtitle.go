@@ -0,0 +1,215 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// 支持的字幕格式
+const (
+	SubtitleFormatVTT = "vtt"
+	SubtitleFormatSRT = "srt"
+)
+
+// ErrMalformedSubtitle 字幕文件格式错误
+var ErrMalformedSubtitle = errors.New("字幕文件格式错误")
+
+var (
+	// vttTimingPattern WebVTT 时间轴行，例如 "00:01.000 --> 00:04.000 align:start"
+	vttTimingPattern = regexp.MustCompile(`^(\d{2,}:)?\d{2}:\d{2}\.\d{3}[ \t]+-->[ \t]+(\d{2,}:)?\d{2}:\d{2}\.\d{3}([ \t].*)?$`)
+	// srtTimingPattern SRT 时间轴行，例如 "00:00:01,000 --> 00:00:04,000"，兼容使用 "." 分隔毫秒的文件
+	srtTimingPattern = regexp.MustCompile(`^\d{1,}:\d{2}:\d{2}[,.]\d{3}[ \t]+-->[ \t]+\d{1,}:\d{2}:\d{2}[,.]\d{3}([ \t].*)?$`)
+	// srtIndexPattern SRT 字幕序号
+	srtIndexPattern = regexp.MustCompile(`^\d+$`)
+	// vttVoicePattern WebVTT 说话人标签，例如 "<v 张三>" 或 "<v.loud 张三>"
+	vttVoicePattern = regexp.MustCompile(`<v(\.[^ \t>]*)?[ \t]+([^>]*)>`)
+	// subtitleTagPattern 字幕文本中的样式标签和内嵌时间戳，例如 "<i>"、"</c>"、"<00:00:01.000>"
+	subtitleTagPattern = regexp.MustCompile(`</?[a-zA-Z][^>]*>|<\d[\d:.]*>`)
+)
+
+// DetectSubtitleFormat 根据文件名扩展名或内容判断字幕格式，不是字幕时返回空字符串。
+// 内容以 WEBVTT 开头视为 VTT，第一段为序号加时间轴视为 SRT
+func DetectSubtitleFormat(filename, content string) string {
+	switch strings.ToLower(path.Ext(strings.TrimSpace(filename))) {
+	case ".vtt":
+		return SubtitleFormatVTT
+	case ".srt":
+		return SubtitleFormatSRT
+	}
+
+	lines := subtitleLines(content)
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "WEBVTT") {
+		return SubtitleFormatVTT
+	}
+
+	var head []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			head = append(head, line)
+		}
+		if len(head) == 2 {
+			break
+		}
+	}
+	if len(head) == 2 && srtIndexPattern.MatchString(head[0]) && srtTimingPattern.MatchString(head[1]) {
+		return SubtitleFormatSRT
+	}
+	return ""
+}
+
+// ParseSubtitle 去除字幕的序号、时间轴和样式标签，还原为按说话人分行的纯文本。
+// VTT 中的 <v 说话人> 标签转换为 "说话人: 内容"，同一说话人连续的字幕合并为一行；
+// 字幕块缺少时间轴、时间轴格式错误或没有任何字幕时返回 ErrMalformedSubtitle
+func ParseSubtitle(format, content string) (string, error) {
+	var cues []subtitleCue
+	var err error
+	switch format {
+	case SubtitleFormatVTT:
+		cues, err = parseVTTCues(content)
+	case SubtitleFormatSRT:
+		cues, err = parseSRTCues(content)
+	default:
+		return "", fmt.Errorf("不支持的字幕格式: %s", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(cues) == 0 {
+		return "", fmt.Errorf("%w: 没有任何字幕", ErrMalformedSubtitle)
+	}
+
+	var lines []string
+	lastSpeaker := ""
+	for _, cue := range cues {
+		if cue.speaker != "" && cue.speaker == lastSpeaker {
+			lines[len(lines)-1] += " " + cue.text
+			continue
+		}
+		if cue.speaker != "" {
+			lines = append(lines, cue.speaker+": "+cue.text)
+		} else {
+			lines = append(lines, cue.text)
+		}
+		lastSpeaker = cue.speaker
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// subtitleCue 一条字幕，speaker 为空表示字幕中没有说话人标签
+type subtitleCue struct {
+	speaker string
+	text    string
+}
+
+// parseVTTCues 解析 WebVTT 字幕，跳过文件头、NOTE、STYLE 和 REGION 块
+func parseVTTCues(content string) ([]subtitleCue, error) {
+	blocks := subtitleBlocks(content)
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0].lines[0], "WEBVTT") {
+		return nil, fmt.Errorf("%w: VTT 文件必须以 WEBVTT 开头", ErrMalformedSubtitle)
+	}
+
+	var cues []subtitleCue
+	for _, block := range blocks[1:] {
+		first := block.lines[0]
+		if first == "NOTE" || strings.HasPrefix(first, "NOTE ") || first == "STYLE" || first == "REGION" {
+			continue
+		}
+
+		// 时间轴之前可以有一行字幕标识
+		timing := 0
+		if !strings.Contains(first, "-->") && len(block.lines) > 1 {
+			timing = 1
+		}
+		if !vttTimingPattern.MatchString(block.lines[timing]) {
+			return nil, fmt.Errorf("%w: 第 %d 行缺少有效的时间轴", ErrMalformedSubtitle, block.start+timing)
+		}
+		cues = append(cues, vttCues(block.lines[timing+1:])...)
+	}
+	return cues, nil
+}
+
+// vttCues 解析一个字幕块的文本，一行中出现多个 <v> 标签时按说话人拆分
+func vttCues(payload []string) []subtitleCue {
+	var cues []subtitleCue
+	speaker := ""
+	for _, line := range payload {
+		matches := vttVoicePattern.FindAllStringSubmatchIndex(line, -1)
+		if len(matches) == 0 {
+			cues = appendSubtitleCue(cues, speaker, line)
+			continue
+		}
+		cues = appendSubtitleCue(cues, speaker, line[:matches[0][0]])
+		for i, match := range matches {
+			speaker = strings.TrimSpace(line[match[4]:match[5]])
+			end := len(line)
+			if i+1 < len(matches) {
+				end = matches[i+1][0]
+			}
+			cues = appendSubtitleCue(cues, speaker, line[match[1]:end])
+		}
+	}
+	return cues
+}
+
+// parseSRTCues 解析 SRT 字幕，每个字幕块为可选的序号、时间轴和文本
+func parseSRTCues(content string) ([]subtitleCue, error) {
+	var cues []subtitleCue
+	for _, block := range subtitleBlocks(content) {
+		timing := 0
+		if srtIndexPattern.MatchString(block.lines[0]) {
+			timing = 1
+		}
+		if timing >= len(block.lines) || !srtTimingPattern.MatchString(block.lines[timing]) {
+			return nil, fmt.Errorf("%w: 第 %d 行缺少有效的时间轴", ErrMalformedSubtitle, block.start+timing)
+		}
+		for _, line := range block.lines[timing+1:] {
+			cues = appendSubtitleCue(cues, "", line)
+		}
+	}
+	return cues, nil
+}
+
+// appendSubtitleCue 去除样式标签并合并空白后追加字幕文本，空文本忽略
+func appendSubtitleCue(cues []subtitleCue, speaker, text string) []subtitleCue {
+	text = strings.Join(strings.Fields(subtitleTagPattern.ReplaceAllString(text, "")), " ")
+	if text == "" {
+		return cues
+	}
+	return append(cues, subtitleCue{speaker: speaker, text: text})
+}
+
+// subtitleBlock 以空行分隔的字幕块，start 为第一行的行号（从1开始）
+type subtitleBlock struct {
+	start int
+	lines []string
+}
+
+// subtitleBlocks 按空行拆分字幕块，每行去除首尾空白
+func subtitleBlocks(content string) []subtitleBlock {
+	var blocks []subtitleBlock
+	inBlock := false
+	for i, line := range subtitleLines(content) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			blocks = append(blocks, subtitleBlock{start: i + 1})
+			inBlock = true
+		}
+		blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+	}
+	return blocks
+}
+
+// subtitleLines 去除 UTF-8 BOM 并统一换行符后按行拆分
+func subtitleLines(content string) []string {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	return strings.Split(content, "\n")
+}
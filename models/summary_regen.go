@@ -0,0 +1,144 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 重新生成摘要时的长度选项，以会议类型对应的默认摘要长度为基准
+const (
+	SummaryLengthShort  = "short"  // 默认长度的一半
+	SummaryLengthMedium = "medium" // 默认长度
+	SummaryLengthLong   = "long"   // 默认长度的两倍
+)
+
+// summaryLengthScale 各长度选项相对默认摘要长度的倍数
+var summaryLengthScale = map[string]float64{
+	SummaryLengthShort:  0.5,
+	SummaryLengthMedium: 1,
+	SummaryLengthLong:   2,
+}
+
+// ErrInvalidSummaryLength 不支持的摘要长度选项
+var ErrInvalidSummaryLength = errors.New("无效的摘要长度")
+
+// ParseSummaryLength 解析摘要长度选项，空值使用 medium
+func ParseSummaryLength(value string) (string, error) {
+	length := strings.ToLower(strings.TrimSpace(value))
+	if length == "" {
+		return SummaryLengthMedium, nil
+	}
+	if _, ok := summaryLengthScale[length]; !ok {
+		return "", fmt.Errorf("%w: %s，可选值: %s, %s, %s", ErrInvalidSummaryLength, value,
+			SummaryLengthShort, SummaryLengthMedium, SummaryLengthLong)
+	}
+	return length, nil
+}
+
+// RegeneratedSummary 重新生成的会议摘要
+type RegeneratedSummary struct {
+	MeetingID       string    `json:"meeting_id"`
+	Summary         string    `json:"summary"`
+	PreviousSummary string    `json:"previous_summary"`
+	Length          string    `json:"length"`
+	MaxChars        int       `json:"max_chars"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
+
+// RegenerateMeetingSummary 只按会议原始内容重新生成摘要并写回元数据，标题、参会人员和待办事项保持不变。
+// length 为 ParseSummaryLength 解析后的长度选项；摘要变化后清除会议的分析缓存并重新计算搜索向量
+func RegenerateMeetingSummary(ctx context.Context, meetingID, length string) (*RegeneratedSummary, error) {
+	meetingData, err := LoadMeeting(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, _ := GetMeetingMetadata(meetingData)
+
+	maxChars := int(float64(getMeetingTypeProfile(metadata.MeetingType).SummaryChars) * summaryLengthScale[length])
+	summary, err := generateMeetingSummary(ctx, MeetingDataContent(meetingData), maxChars)
+	if err != nil {
+		return nil, fmt.Errorf("生成会议摘要失败: %v", err)
+	}
+
+	result := &RegeneratedSummary{
+		MeetingID:   meetingID,
+		Summary:     summary,
+		Length:      length,
+		MaxChars:    maxChars,
+		GeneratedAt: time.Now(),
+	}
+	err = UpdateMeetingMetadata(meetingID, func(rawMetadata map[string]interface{}) bool {
+		result.PreviousSummary, _ = rawMetadata["summary"].(string)
+		rawMetadata["summary"] = summary
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 纪要、评分等缓存的输入包含摘要，直接清除，避免继续返回按旧摘要生成的结果
+	if err := InvalidateCachedArtifacts(meetingID); err != nil {
+		Logf(ctx, "清除会议 %s 缓存失败: %v\n", meetingID, err)
+	}
+	metadata.Summary = summary
+	if err := IndexMeetingEmbedding(ctx, meetingID, metadata); err != nil {
+		Logf(ctx, "计算会议 %s 向量失败: %v\n", meetingID, err)
+	}
+
+	return result, nil
+}
+
+// generateMeetingSummary 生成不超过 maxChars 字的会议摘要，超过 extraction.chunk_chars 的内容分段摘要后再合并
+func generateMeetingSummary(ctx context.Context, content string, maxChars int) (string, error) {
+	chunks, err := SplitContent(content, GetExtractionSettings().ChunkChars)
+	if err != nil {
+		return "", err
+	}
+
+	chatModel, err := GetFeatureChatModel(ctx, ModelFeatureExtract, 0.3)
+	if err != nil {
+		return "", fmt.Errorf("创建LLM客户端失败: %v", err)
+	}
+
+	summaries := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		text := chunk
+		if len(chunks) > 1 {
+			text = fmt.Sprintf("以下是会议记录的第 %d/%d 部分：\n%s", i+1, len(chunks), chunk)
+		}
+
+		messages := []*schema.Message{
+			schema.SystemMessage(fmt.Sprintf("你是一个专业的会议分析助手。请为以下会议内容写一段不超过%d字的摘要，概括会议讨论的主要内容、结论和后续安排，只输出摘要内容。", maxChars)),
+			schema.UserMessage(text),
+		}
+
+		callCtx, cancel := withLLMTimeout(ctx)
+		response, err := chatModel.Generate(callCtx, messages)
+		cancel()
+		recordLLMCall(ctx, response, err)
+		if err != nil {
+			return "", err
+		}
+		if summary := strings.TrimSpace(response.Content); summary != "" {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	switch len(summaries) {
+	case 0:
+		return "", fmt.Errorf("模型没有返回摘要")
+	case 1:
+		summary, exceeded := newOutputGuard().Push(summaries[0])
+		if exceeded {
+			summary += truncatedMarker
+		}
+		return summary, nil
+	default:
+		return mergeChunkSummaries(ctx, summaries, maxChars)
+	}
+}
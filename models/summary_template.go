@@ -0,0 +1,286 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultSummaryTemplateName 内置摘要模板名称
+const DefaultSummaryTemplateName = "default"
+
+// emptySectionContent 会议中没有对应内容的章节填充值
+const emptySectionContent = "无"
+
+// SummaryTemplate 结构化摘要模板，由有序的章节列表组成
+type SummaryTemplate struct {
+	Name     string   `json:"name"`
+	Sections []string `json:"sections"`
+}
+
+// SummarySection 结构化摘要中的一个章节
+type SummarySection struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// StructuredSummary 按模板生成的结构化摘要
+type StructuredSummary struct {
+	Template string           `json:"template"`
+	Sections []SummarySection `json:"sections"` // 与模板章节顺序一致
+	Cached   bool             `json:"cached"`
+}
+
+// defaultSummaryTemplate 内置的摘要模板
+var defaultSummaryTemplate = SummaryTemplate{
+	Name:     DefaultSummaryTemplateName,
+	Sections: []string{"背景", "讨论", "决议", "待办", "风险"},
+}
+
+// summaryTemplatesMutex 保护模板文件的读写
+var summaryTemplatesMutex sync.Mutex
+
+// summaryTemplatesPath 返回摘要模板文件路径
+func summaryTemplatesPath() string {
+	return filepath.Join(dataDir(), "summary_templates.json")
+}
+
+// loadSummaryTemplates 读取用户自定义的摘要模板，调用方需持有锁
+func loadSummaryTemplates() (map[string]SummaryTemplate, error) {
+	templates := make(map[string]SummaryTemplate)
+
+	data, err := os.ReadFile(summaryTemplatesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return templates, nil
+		}
+		return nil, fmt.Errorf("读取摘要模板失败: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("解析摘要模板失败: %v", err)
+	}
+
+	return templates, nil
+}
+
+// ListSummaryTemplates 列出所有摘要模板（含内置模板），按名称排序
+func ListSummaryTemplates() ([]SummaryTemplate, error) {
+	summaryTemplatesMutex.Lock()
+	defer summaryTemplatesMutex.Unlock()
+
+	templates, err := loadSummaryTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := templates[DefaultSummaryTemplateName]; !ok {
+		templates[DefaultSummaryTemplateName] = defaultSummaryTemplate
+	}
+
+	result := make([]SummaryTemplate, 0, len(templates))
+	for _, tmpl := range templates {
+		result = append(result, tmpl)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// GetSummaryTemplate 按名称获取摘要模板
+func GetSummaryTemplate(name string) (*SummaryTemplate, error) {
+	summaryTemplatesMutex.Lock()
+	defer summaryTemplatesMutex.Unlock()
+
+	templates, err := loadSummaryTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	if tmpl, ok := templates[name]; ok {
+		return &tmpl, nil
+	}
+	if name == DefaultSummaryTemplateName {
+		tmpl := defaultSummaryTemplate
+		return &tmpl, nil
+	}
+
+	return nil, fmt.Errorf("摘要模板 %s 不存在", name)
+}
+
+// SaveSummaryTemplate 保存（新增或覆盖）摘要模板
+func SaveSummaryTemplate(tmpl SummaryTemplate) error {
+	tmpl.Name = strings.TrimSpace(tmpl.Name)
+	if tmpl.Name == "" {
+		return fmt.Errorf("模板名称不能为空")
+	}
+
+	// 去除空白和重复章节，保持原有顺序
+	seen := make(map[string]bool)
+	sections := make([]string, 0, len(tmpl.Sections))
+	for _, section := range tmpl.Sections {
+		section = strings.TrimSpace(section)
+		if section == "" || seen[section] {
+			continue
+		}
+		seen[section] = true
+		sections = append(sections, section)
+	}
+	if len(sections) == 0 {
+		return fmt.Errorf("模板至少需要一个章节")
+	}
+	tmpl.Sections = sections
+
+	summaryTemplatesMutex.Lock()
+	defer summaryTemplatesMutex.Unlock()
+
+	templates, err := loadSummaryTemplates()
+	if err != nil {
+		return err
+	}
+	templates[tmpl.Name] = tmpl
+
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化摘要模板失败: %v", err)
+	}
+
+	filePath := summaryTemplatesPath()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("创建模板目录失败: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("保存摘要模板失败: %v", err)
+	}
+
+	return nil
+}
+
+// GetStructuredSummary 按模板生成会议的结构化摘要，结果按会议内容和模板章节缓存
+func GetStructuredSummary(ctx context.Context, meetingID string, tmpl *SummaryTemplate) (*StructuredSummary, error) {
+	meetingContent, meetingInfo, err := getMeetingContent(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 不同回答语言的摘要分别缓存
+	kind := "summary_" + HashContent(append([]string{LocaleFromContext(ctx)}, tmpl.Sections...)...)[:16]
+	sourceHash := HashContent(meetingInfo, meetingContent)
+
+	var cached StructuredSummary
+	if LoadCachedArtifact(meetingID, kind, sourceHash, &cached) {
+		cached.Template = tmpl.Name
+		cached.Cached = true
+		return &cached, nil
+	}
+
+	sections, err := GenerateSectionedSummary(ctx, meetingInfo+"\n会议内容:\n"+meetingContent, tmpl.Sections)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &StructuredSummary{
+		Template: tmpl.Name,
+		Sections: sections,
+	}
+
+	if err := SaveCachedArtifact(meetingID, kind, sourceHash, summary); err != nil {
+		Logf(ctx, "缓存结构化摘要失败: %v\n", err)
+	}
+
+	return summary, nil
+}
+
+// GenerateSectionedSummary 使用LLM按给定章节生成摘要，返回的章节顺序与 sections 一致，
+// 会议中没有对应内容的章节填充为"无"
+func GenerateSectionedSummary(ctx context.Context, documentText string, sections []string) ([]SummarySection, error) {
+	chatModel, err := GetChatModel(ctx, 0.3)
+	if err != nil {
+		return nil, fmt.Errorf("创建LLM客户端失败: %v", err)
+	}
+
+	sectionKeys := make([]string, 0, len(sections))
+	for _, section := range sections {
+		sectionKeys = append(sectionKeys, fmt.Sprintf("  %q: \"该章节内容...\"", section))
+	}
+
+	systemPrompt := fmt.Sprintf(`你是一个专业的会议纪要助手。请根据会议内容，按以下章节整理会议纪要：%s
+
+要求：
+1. 每个章节只写与该章节相关的内容，简洁准确，不要编造会议中没有的信息
+2. 如果会议中没有与某个章节对应的内容，该章节内容填写"%s"
+3. 必须包含所有章节
+
+以下是你必须返回的JSON格式（不要输出其他内容）：
+{
+%s
+}`, strings.Join(sections, "、"), emptySectionContent, strings.Join(sectionKeys, ",\n"))
+
+	messages := []*schema.Message{
+		schema.SystemMessage(withLocaleInstruction(ctx, systemPrompt)),
+		schema.UserMessage(documentText),
+	}
+
+	callCtx, cancel := withLLMTimeout(ctx)
+	defer cancel()
+	response, err := chatModel.Generate(callCtx, messages)
+	recordLLMCall(ctx, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("生成结构化摘要失败: %v", err)
+	}
+
+	// 解析JSON响应
+	var contents map[string]interface{}
+	if err := parseJSONObject(response.Content, &contents); err != nil {
+		return nil, fmt.Errorf("解析结构化摘要失败: %v", err)
+	}
+
+	// 按模板章节顺序组装结果，忽略模型额外输出的章节；各章节内容合计超出最大输出字符数时截断
+	result := make([]SummarySection, 0, len(sections))
+	guard := newOutputGuard()
+	exceeded := false
+	for _, section := range sections {
+		if exceeded {
+			result = append(result, SummarySection{Title: section, Content: truncatedMarker})
+			continue
+		}
+
+		content := emptySectionContent
+		if value, ok := contents[section]; ok {
+			switch v := value.(type) {
+			case string:
+				if strings.TrimSpace(v) != "" {
+					content = strings.TrimSpace(v)
+				}
+			case []interface{}:
+				var items []string
+				for _, item := range v {
+					if itemStr, ok := item.(string); ok && strings.TrimSpace(itemStr) != "" {
+						items = append(items, "- "+strings.TrimSpace(itemStr))
+					}
+				}
+				if len(items) > 0 {
+					content = strings.Join(items, "\n")
+				}
+			}
+		}
+		if content, exceeded = guard.Push(content); exceeded {
+			content += truncatedMarker
+		}
+		result = append(result, SummarySection{
+			Title:   section,
+			Content: content,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 会议标签的数量和长度限制
+const (
+	maxMeetingTags = 20
+	maxTagLength   = 32
+)
+
+// NormalizeTags 去除标签首尾空白、空标签和重复标签（忽略大小写），保持原有顺序，
+// 标签数量或长度超出限制时返回错误
+func NormalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("标签 %s 超过 %d 个字符", tag, maxTagLength)
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+
+	if len(result) > maxMeetingTags {
+		return nil, fmt.Errorf("标签数量不能超过 %d 个", maxMeetingTags)
+	}
+	return result, nil
+}
+
+// ParseTags 解析请求体中的标签字段，字段缺失时返回空列表，字段不是字符串数组时返回错误
+func ParseTags(value interface{}) ([]string, error) {
+	if value == nil {
+		return []string{}, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("tags 必须是字符串数组")
+	}
+
+	tags := make([]string, 0, len(list))
+	for _, item := range list {
+		tag, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("tags 必须是字符串数组")
+		}
+		tags = append(tags, tag)
+	}
+	return NormalizeTags(tags)
+}
+
+// GetMeetingTags 返回会议 metadata 中记录的标签
+func GetMeetingTags(metadata map[string]interface{}) []string {
+	tags := []string{}
+	list, ok := metadata["tags"].([]interface{})
+	if !ok {
+		return tags
+	}
+	for _, item := range list {
+		if tag, ok := item.(string); ok && tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag 判断会议 metadata 中是否包含指定标签，忽略大小写
+func HasTag(metadata map[string]interface{}, tag string) bool {
+	tag = strings.TrimSpace(tag)
+	for _, t := range GetMeetingTags(metadata) {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMeetingTags 替换会议的标签，返回规范化后的标签
+func SetMeetingTags(meetingID string, tags []string) ([]string, error) {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	err = UpdateMeetingMetadata(meetingID, func(metadata map[string]interface{}) bool {
+		values := make([]interface{}, 0, len(normalized))
+		for _, tag := range normalized {
+			values = append(values, tag)
+		}
+		metadata["tags"] = values
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// ListAllTags 返回所有会议使用过的标签（忽略大小写去重），按名称排序
+func ListAllTags() ([]string, error) {
+	meetingIDs, err := ListMeetingIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, meetingID := range meetingIDs {
+		meetingData, err := LoadMeeting(meetingID)
+		if err != nil {
+			fmt.Printf("读取会议 %s 失败: %v\n", meetingID, err)
+			continue
+		}
+		metadata, _ := meetingData["metadata"].(map[string]interface{})
+		for _, tag := range GetMeetingTags(metadata) {
+			key := strings.ToLower(tag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+	return tags, nil
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExtractedTodo 从会议中抽取的一条待办事项，负责人或截止日期无法确定时为空
+type ExtractedTodo struct {
+	Task     string `json:"task"`
+	Assignee string `json:"assignee"`
+	DueDate  string `json:"due_date"` // 格式 YYYY-MM-DD
+}
+
+// dueDateLayouts 可识别的截止日期格式，模型偶尔会带上时间
+var dueDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02",
+}
+
+// ParseExtractedTodos 解析会议 metadata 中的 todo_list，
+// 同时兼容旧会议的字符串数组和新的 {task, assignee, due_date} 对象数组
+func ParseExtractedTodos(value interface{}) []ExtractedTodo {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	todos := make([]ExtractedTodo, 0, len(list))
+	for _, item := range list {
+		var todo ExtractedTodo
+		switch v := item.(type) {
+		case string:
+			todo.Task = v
+		case map[string]interface{}:
+			todo.Task = firstString(v, "task", "title", "content")
+			todo.Assignee = firstString(v, "assignee", "assigned_to", "owner")
+			todo.DueDate = firstString(v, "due_date", "deadline")
+		}
+
+		todo.Task = strings.TrimSpace(todo.Task)
+		if todo.Task == "" {
+			continue
+		}
+		todo.Assignee = strings.TrimSpace(todo.Assignee)
+		// 模型偶尔输出"待定"之类无法识别的日期，按未确定处理
+		if ParseDueDate(todo.DueDate).IsZero() {
+			todo.DueDate = ""
+		}
+		todos = append(todos, todo)
+	}
+	return todos
+}
+
+// ParseDueDate 解析抽取出的截止日期，无法识别时返回零值
+func ParseDueDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range dueDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// FormatTodoItem 将待办事项格式化为一行文本，附带负责人和截止日期
+func FormatTodoItem(todo ExtractedTodo) string {
+	var extra []string
+	if todo.Assignee != "" {
+		extra = append(extra, "负责人: "+todo.Assignee)
+	}
+	if todo.DueDate != "" {
+		extra = append(extra, "截止: "+todo.DueDate)
+	}
+	if len(extra) == 0 {
+		return todo.Task
+	}
+	return todo.Task + "（" + strings.Join(extra, "，") + "）"
+}
+
+// firstString 返回对象中第一个非空的字符串字段
+func firstString(object map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := object[key].(string); ok && strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// capTodoList 只保留前 max 条待办（模型通常按重要程度和讨论顺序输出），超出的待办移到 UntrackedTodos，
+// 避免一次异常的抽取向待办表写入大量记录
+func (m *MeetingMetadata) capTodoList(max int) {
+	m.UntrackedTodos = nil
+	if max <= 0 || len(m.TodoList) <= max {
+		return
+	}
+	fmt.Printf("会议待办事项共 %d 条，超过上限（%d 条），其余 %d 条不自动创建\n", len(m.TodoList), max, len(m.TodoList)-max)
+	m.UntrackedTodos = append([]ExtractedTodo{}, m.TodoList[max:]...)
+	m.TodoList = m.TodoList[:max]
+}
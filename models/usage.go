@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// LLMUsage 一段时间内模型调用的次数和token用量
+type LLMUsage struct {
+	Calls            int64   `json:"calls"`
+	Errors           int64   `json:"errors"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	Cost             float64 `json:"cost"` // 按配置的单价估算的费用，未配置单价时为0
+}
+
+// UsageTracker 累计一个请求或任务内所有模型调用的用量，可被并发调用
+type UsageTracker struct {
+	mu    sync.Mutex
+	usage LLMUsage
+}
+
+// usageTrackerKey 用量统计在 context 中的键
+type usageTrackerKey struct{}
+
+// WithUsageTracker 返回携带用量统计的 context，之后使用该 context 的模型调用都会计入统计
+func WithUsageTracker(ctx context.Context) (context.Context, *UsageTracker) {
+	tracker := &UsageTracker{}
+	return context.WithValue(ctx, usageTrackerKey{}, tracker), tracker
+}
+
+// Snapshot 返回当前累计的用量
+func (t *UsageTracker) Snapshot() LLMUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.usage
+	promptPrice, completionPrice := GetLLMPricing()
+	usage.Cost = float64(usage.PromptTokens)/1000*promptPrice + float64(usage.CompletionTokens)/1000*completionPrice
+	return usage
+}
+
+// recordLLMCall 记录一次模型调用及其结果，流式调用在创建流后调用，msg 传 nil
+func recordLLMCall(ctx context.Context, msg *schema.Message, err error) {
+	tracker, ok := ctx.Value(usageTrackerKey{}).(*UsageTracker)
+	if !ok {
+		return
+	}
+
+	tracker.mu.Lock()
+	tracker.usage.Calls++
+	if err != nil {
+		tracker.usage.Errors++
+	}
+	tracker.mu.Unlock()
+
+	recordLLMUsage(ctx, msg)
+}
+
+// recordLLMUsage 累计模型返回的token用量，流式调用的用量只出现在最后一个分片中
+func recordLLMUsage(ctx context.Context, msg *schema.Message) {
+	if msg == nil || msg.ResponseMeta == nil || msg.ResponseMeta.Usage == nil {
+		return
+	}
+	tracker, ok := ctx.Value(usageTrackerKey{}).(*UsageTracker)
+	if !ok {
+		return
+	}
+
+	usage := msg.ResponseMeta.Usage
+	tracker.mu.Lock()
+	tracker.usage.PromptTokens += int64(usage.PromptTokens)
+	tracker.usage.CompletionTokens += int64(usage.CompletionTokens)
+	tracker.usage.TotalTokens += int64(usage.TotalTokens)
+	tracker.mu.Unlock()
+}
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// wechatWorkMarkdownLimit 企业微信markdown消息内容的最大字节数
+const wechatWorkMarkdownLimit = 4096
+
+// WeChatWorkMessage 企业微信群机器人markdown消息
+type WeChatWorkMessage struct {
+	MsgType  string             `json:"msgtype"`
+	Markdown WeChatWorkMarkdown `json:"markdown"`
+}
+
+// WeChatWorkMarkdown 企业微信markdown消息内容
+type WeChatWorkMarkdown struct {
+	Content string `json:"content"`
+}
+
+// wechatWorkResponse 企业微信Webhook的响应
+type wechatWorkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// WeChatWorkNotifier 企业微信群机器人推送
+type WeChatWorkNotifier struct{}
+
+// GetWeChatWorkWebhookURL 从配置中获取企业微信Webhook URL
+func GetWeChatWorkWebhookURL() (string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.WeChatWork.WebhookURL == "" {
+		return "", fmt.Errorf("企业微信Webhook URL未配置")
+	}
+
+	return cfg.WeChatWork.WebhookURL, nil
+}
+
+// Name 返回渠道名称
+func (n *WeChatWorkNotifier) Name() string {
+	return NotifierWeChatWork
+}
+
+// SendReport 推送会议报告到企业微信。
+// 内容超出markdown长度限制时，摘要会被截断，待办事项拆分为后续的独立消息发送
+func (n *WeChatWorkNotifier) SendReport(report *MeetingReport) error {
+	webhookURL, err := GetWeChatWorkWebhookURL()
+	if err != nil {
+		return fmt.Errorf("获取企业微信Webhook URL失败: %v", err)
+	}
+
+	for _, content := range BuildWeChatWorkReportContents(report) {
+		if err := postWeChatWorkMarkdown(webhookURL, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BuildReportMessages 返回推送到企业微信的会议报告markdown消息，内容过长时为多条
+func (n *WeChatWorkNotifier) BuildReportMessages(report *MeetingReport) []interface{} {
+	var messages []interface{}
+	for _, content := range BuildWeChatWorkReportContents(report) {
+		messages = append(messages, newWeChatWorkMarkdownMessage(content))
+	}
+	return messages
+}
+
+// SendText 推送文本通知到企业微信
+func (n *WeChatWorkNotifier) SendText(title, content string) error {
+	webhookURL, err := GetWeChatWorkWebhookURL()
+	if err != nil {
+		return fmt.Errorf("获取企业微信Webhook URL失败: %v", err)
+	}
+
+	text := fmt.Sprintf("## %s\n%s", title, content)
+	return postWeChatWorkMarkdown(webhookURL, truncateUTF8(text, wechatWorkMarkdownLimit))
+}
+
+// BuildWeChatWorkReportContents 将会议报告按报告模板转换为一条或多条markdown消息内容，
+// 每条内容均不超过企业微信的长度限制。待办事项放在最后，以便内容过长时拆分为独立消息；markdown 不支持分割线，模板中的分割线忽略
+func BuildWeChatWorkReportContents(report *MeetingReport) []string {
+	sections := GetReportSections()
+	todoSection, hasTodos := findReportSection(sections, ReportSectionTodos)
+	todoList := report.TodoList
+	if !hasTodos {
+		todoList = nil
+	}
+
+	// 优先尝试将全部内容放在一条消息中
+	full := buildWeChatWorkReportHeader(report, sections, report.Summary) + buildWeChatWorkTodoSection(todoSection.Title, todoList)
+	if len(full) <= wechatWorkMarkdownLimit {
+		return []string{full}
+	}
+
+	// 放不下时，主消息只包含待办事项以外的区块，必要时截断摘要
+	header := buildWeChatWorkReportHeader(report, sections, report.Summary)
+	if len(header) > wechatWorkMarkdownLimit {
+		// 摘要为空时整个区块被跳过，开销需要加上摘要区块的标题和换行，使截断只作用于摘要正文
+		overhead := len(buildWeChatWorkReportHeader(report, sections, ""))
+		if summarySection, ok := findReportSection(sections, ReportSectionSummary); ok {
+			overhead += len(formatWeChatWorkSection(summarySection.Title, ""))
+		}
+		summary := truncateUTF8(report.Summary, wechatWorkMarkdownLimit-overhead)
+		header = buildWeChatWorkReportHeader(report, sections, summary)
+		// 描述等其他字段本身过长时，直接截断整条消息
+		header = truncateUTF8(header, wechatWorkMarkdownLimit)
+	}
+
+	contents := []string{header}
+
+	// 待办事项拆分为独立消息，每条消息尽可能多地容纳待办
+	var chunk []string
+	chunkLen := 0
+	flush := func() {
+		if len(chunk) > 0 {
+			contents = append(contents, strings.Join(chunk, ""))
+			chunk = nil
+			chunkLen = 0
+		}
+	}
+
+	titleLine := fmt.Sprintf("**%s - %s：**\n", report.Title, todoSection.Title)
+	for i, todo := range todoList {
+		line := fmt.Sprintf("%d. %s\n", i+1, todo)
+		if len(chunk) == 0 {
+			chunk = append(chunk, titleLine)
+			chunkLen = len(titleLine)
+		}
+		if chunkLen+len(line) > wechatWorkMarkdownLimit {
+			flush()
+			chunk = append(chunk, titleLine)
+			chunkLen = len(titleLine)
+			line = truncateUTF8(line, wechatWorkMarkdownLimit-chunkLen)
+		}
+		chunk = append(chunk, line)
+		chunkLen += len(line)
+	}
+	flush()
+
+	return contents
+}
+
+// buildWeChatWorkReportHeader 按报告模板构建报告主体部分（不含待办事项）的markdown内容，summary 为可能被截断的摘要
+func buildWeChatWorkReportHeader(report *MeetingReport, sections []ReportSection, summary string) string {
+	var sb strings.Builder
+	sb.WriteString("## " + report.Title + "\n")
+
+	for _, section := range sections {
+		if section.Type == ReportSectionTodos || section.Type == ReportSectionDivider {
+			continue
+		}
+		text := reportSectionText(report, section.Type)
+		if section.Type == ReportSectionSummary {
+			text = summary
+		}
+		if text != "" {
+			sb.WriteString(formatWeChatWorkSection(section.Title, text))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatWeChatWorkSection 构建单个区块的markdown内容：加粗的标题行和正文
+func formatWeChatWorkSection(title, text string) string {
+	return "**" + title + "：**\n" + text + "\n"
+}
+
+// buildWeChatWorkTodoSection 构建待办事项部分的markdown内容
+func buildWeChatWorkTodoSection(title string, todoList []string) string {
+	if len(todoList) == 0 {
+		return ""
+	}
+	return "**" + title + "：**\n" + reportTodoLines(todoList)
+}
+
+// truncateUTF8 将字符串截断到不超过maxBytes字节，截断时以省略号结尾且不会切断多字节字符
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+
+	const ellipsis = "…"
+	limit := maxBytes - len(ellipsis)
+	if limit <= 0 {
+		return ""
+	}
+
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit] + ellipsis
+}
+
+// newWeChatWorkMarkdownMessage 构建企业微信markdown消息
+func newWeChatWorkMarkdownMessage(content string) WeChatWorkMessage {
+	return WeChatWorkMessage{
+		MsgType: "markdown",
+		Markdown: WeChatWorkMarkdown{
+			Content: content,
+		},
+	}
+}
+
+// postWeChatWorkMarkdown 发送一条markdown消息到企业微信
+func postWeChatWorkMarkdown(webhookURL, content string) error {
+	message := newWeChatWorkMarkdownMessage(content)
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(messageJSON))
+	if err != nil {
+		return fmt.Errorf("发送消息到企业微信失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("企业微信返回错误状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// 企业微信在HTTP 200时通过errcode表示业务错误
+	var result wechatWorkResponse
+	if err := json.Unmarshal(bodyBytes, &result); err == nil && result.ErrCode != 0 {
+		return fmt.Errorf("企业微信返回错误: %d %s", result.ErrCode, result.ErrMsg)
+	}
+
+	return nil
+}
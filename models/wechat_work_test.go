@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildWeChatWorkReportContentsTruncatesOnlySummary(t *testing.T) {
+	report := &MeetingReport{
+		Title:        "预算评审会",
+		Description:  "讨论下季度预算",
+		Summary:      strings.Repeat("讨论了预算分配。", 400),
+		Participants: []string{"张三", "李四"},
+		StartTime:    "2025-04-21 10:00",
+		EndTime:      "2025-04-21 11:00",
+		TodoList:     []string{"整理预算表（负责人: 张三）"},
+	}
+
+	contents := BuildWeChatWorkReportContents(report)
+	if len(contents) != 2 {
+		t.Fatalf("得到 %d 条消息，期望主消息和待办消息共 2 条", len(contents))
+	}
+	header := contents[0]
+	if len(header) > wechatWorkMarkdownLimit {
+		t.Errorf("主消息长度 = %d，超过限制 %d", len(header), wechatWorkMarkdownLimit)
+	}
+	if !strings.Contains(header, "…") {
+		t.Error("摘要过长时应被截断并以省略号结尾")
+	}
+	// 只截断摘要正文，摘要之后的区块完整保留
+	for _, want := range []string{"**会议描述：**\n讨论下季度预算\n", "**参会人员：**\n张三、李四\n", "2025-04-21 10:00 - 2025-04-21 11:00"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("主消息缺少 %q", want)
+		}
+	}
+	if !strings.HasSuffix(header, "**参会人员：**\n张三、李四\n") {
+		t.Errorf("主消息应以参会人员区块结尾，实际结尾为 %q", header[len(header)-40:])
+	}
+}
@@ -0,0 +1,139 @@
+package sql
+
+import (
+	"sync"
+	"time"
+)
+
+// 待办事项变更事件类型
+const (
+	TodoEventCreated = "created" // 新建
+	TodoEventUpdated = "updated" // 更新
+	TodoEventDeleted = "deleted" // 删除
+	TodoEventResync  = "resync"  // 历史事件已过期，订阅者需要重新拉取完整列表
+)
+
+const (
+	todoEventBacklogSize    = 512 // 内存中保留的最近事件数量，用于断线重连后补发
+	todoSubscriberQueueSize = 64  // 每个订阅者的事件缓冲区大小
+)
+
+// TodoEvent 待办事项变更事件
+type TodoEvent struct {
+	ID        int64     `json:"id"`         // 事件序号，全局单调递增
+	Type      string    `json:"type"`       // 事件类型: created / updated / deleted / resync
+	MeetingID string    `json:"meeting_id"` // 关联的会议ID
+	Todo      *Todo     `json:"todo,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TodoSubscription 一个待办事项变更的订阅
+type TodoSubscription struct {
+	// C 接收变更事件的通道。当订阅者消费过慢导致缓冲区溢出时通道会被关闭，
+	// 订阅者应携带最后收到的事件ID重新订阅以补发遗漏的事件
+	C <-chan TodoEvent
+
+	meetingID string
+	ch        chan TodoEvent
+	closeOnce sync.Once
+}
+
+// todoEventBroker 内存中的待办事项事件广播器
+type todoEventBroker struct {
+	mu          sync.Mutex
+	seq         int64
+	backlog     []TodoEvent
+	subscribers map[*TodoSubscription]struct{}
+}
+
+var todoBroker = &todoEventBroker{
+	subscribers: make(map[*TodoSubscription]struct{}),
+}
+
+// SubscribeTodoEvents 订阅指定会议的待办事项变更。
+// lastEventID 大于0时，会先补发该ID之后仍保留在内存中的历史事件；
+// 如果所需的历史事件已被淘汰，则先推送一条 resync 事件
+func SubscribeTodoEvents(meetingID string, lastEventID int64) *TodoSubscription {
+	ch := make(chan TodoEvent, todoSubscriberQueueSize+todoEventBacklogSize)
+	sub := &TodoSubscription{
+		C:         ch,
+		meetingID: meetingID,
+		ch:        ch,
+	}
+
+	todoBroker.mu.Lock()
+	defer todoBroker.mu.Unlock()
+
+	// 在持锁状态下补发历史事件并注册订阅，保证补发与实时事件之间没有空隙
+	if lastEventID > 0 {
+		if len(todoBroker.backlog) > 0 && todoBroker.backlog[0].ID > lastEventID+1 {
+			ch <- TodoEvent{
+				ID:        todoBroker.seq,
+				Type:      TodoEventResync,
+				MeetingID: meetingID,
+				Timestamp: time.Now(),
+			}
+		} else {
+			for _, event := range todoBroker.backlog {
+				if event.ID > lastEventID && event.MeetingID == meetingID {
+					ch <- event
+				}
+			}
+		}
+	}
+
+	todoBroker.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Close 取消订阅并释放资源，可重复调用
+func (s *TodoSubscription) Close() {
+	todoBroker.mu.Lock()
+	defer todoBroker.mu.Unlock()
+	todoBroker.removeLocked(s)
+}
+
+// removeLocked 移除订阅者并关闭其通道，调用方需持有锁
+func (b *todoEventBroker) removeLocked(s *TodoSubscription) {
+	delete(b.subscribers, s)
+	s.closeOnce.Do(func() {
+		close(s.ch)
+	})
+}
+
+// publish 广播一条待办事项变更事件
+func (b *todoEventBroker) publish(eventType string, todo *Todo) {
+	if todo == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	snapshot := *todo
+	event := TodoEvent{
+		ID:        b.seq,
+		Type:      eventType,
+		MeetingID: todo.MeetingID,
+		Todo:      &snapshot,
+		Timestamp: time.Now(),
+	}
+
+	b.backlog = append(b.backlog, event)
+	if len(b.backlog) > todoEventBacklogSize {
+		b.backlog = b.backlog[len(b.backlog)-todoEventBacklogSize:]
+	}
+
+	for sub := range b.subscribers {
+		if sub.meetingID != event.MeetingID {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// 订阅者消费过慢，断开该订阅，由其重连后通过 lastEventID 补发
+			b.removeLocked(sub)
+		}
+	}
+}
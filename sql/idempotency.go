@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// IdempotencyRecord 幂等键对应的已创建资源
+type IdempotencyRecord struct {
+	Key       string
+	MeetingID string
+	JobID     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// InitIdempotencyTable 初始化幂等键表
+func InitIdempotencyTable(dbName string) error {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key TEXT PRIMARY KEY,
+		meeting_id TEXT NOT NULL,
+		job_id TEXT,
+		created_at TIMESTAMP NOT NULL,
+		expires_at INTEGER NOT NULL -- 过期时间的Unix秒数，便于直接比较
+	);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return fmt.Errorf("创建幂等键表失败: %w", err)
+	}
+
+	return nil
+}
+
+// ReserveIdempotencyKey 为幂等键登记新的会议ID，同时清理已过期的幂等键。
+// 幂等键已被登记且未过期时返回已有记录，reserved 为 false；否则登记成功，返回的记录即本次登记的内容
+func ReserveIdempotencyKey(dbName, key, meetingID string, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return nil, false, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("开始事务失败: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= ?1;`, now.Unix()); err != nil {
+		tx.Rollback()
+		return nil, false, fmt.Errorf("清理过期幂等键失败: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+	INSERT OR IGNORE INTO idempotency_keys (key, meeting_id, job_id, created_at, expires_at)
+	VALUES (?1, ?2, '', ?3, ?4);`,
+		key, meetingID, now, now.Add(ttl).Unix(),
+	); err != nil {
+		tx.Rollback()
+		return nil, false, fmt.Errorf("登记幂等键失败: %w", err)
+	}
+
+	var record IdempotencyRecord
+	var jobID sql.NullString
+	var expiresAt int64
+	err = tx.QueryRow(`
+	SELECT key, meeting_id, job_id, created_at, expires_at
+	FROM idempotency_keys WHERE key = ?1;`, key,
+	).Scan(&record.Key, &record.MeetingID, &jobID, &record.CreatedAt, &expiresAt)
+	if err != nil {
+		tx.Rollback()
+		return nil, false, fmt.Errorf("查询幂等键失败: %w", err)
+	}
+	record.JobID = jobID.String
+	record.ExpiresAt = time.Unix(expiresAt, 0)
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return &record, record.MeetingID == meetingID, nil
+}
+
+// SetIdempotencyJobID 记录幂等键对应的会议处理任务ID，便于重复请求返回同一任务
+func SetIdempotencyJobID(dbName, key, jobID string) error {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`UPDATE idempotency_keys SET job_id = ?1 WHERE key = ?2;`, jobID, key); err != nil {
+		return fmt.Errorf("更新幂等键失败: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey 删除幂等键，用于创建失败后允许客户端使用同一幂等键重试
+func ReleaseIdempotencyKey(dbName, key string) error {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`DELETE FROM idempotency_keys WHERE key = ?1;`, key); err != nil {
+		return fmt.Errorf("删除幂等键失败: %w", err)
+	}
+	return nil
+}
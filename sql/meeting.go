@@ -0,0 +1,168 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MeetingRecord meetings 表中的一条会议。会议文件中的 metadata 和 raw_content 单独成列，
+// 其他字段（如 speaker_content）以 JSON 对象保存在 Extra 中
+type MeetingRecord struct {
+	ID         string
+	Metadata   string  // 会议元数据 JSON
+	RawContent *string // 会议原始内容，会议文件中没有该字段时为 nil
+	Tags       string  // 标签 JSON 数组，与 metadata.tags 一致，便于用 json_each 按标签查询
+	Extra      string  // 其他字段的 JSON 对象
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ErrMeetingRecordNotFound 会议不存在
+var ErrMeetingRecordNotFound = errors.New("会议不存在")
+
+// meetingDBs 按数据库文件缓存的连接池。会议在每次请求中都会读取，不像待办操作那样每次重新打开数据库
+var meetingDBs sync.Map
+
+// meetingDatabase 返回数据库文件的共享连接池，第一次使用时打开
+func meetingDatabase(dbName string) (*sql.DB, error) {
+	if db, ok := meetingDBs.Load(dbName); ok {
+		return db.(*sql.DB), nil
+	}
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+	if existing, loaded := meetingDBs.LoadOrStore(dbName, db); loaded {
+		db.Close()
+		return existing.(*sql.DB), nil
+	}
+	return db, nil
+}
+
+// InitMeetingTable 初始化会议表，会议与待办事项保存在同一个数据库中，可以按 meeting_id 联表查询
+func InitMeetingTable(dbName string) error {
+	db, err := meetingDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS meetings (
+		id TEXT PRIMARY KEY,
+		metadata TEXT NOT NULL DEFAULT '{}',
+		raw_content TEXT,
+		tags TEXT NOT NULL DEFAULT '[]',
+		extra TEXT NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_meetings_created_at ON meetings(created_at);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return fmt.Errorf("创建会议表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveMeetingRecord 保存会议，已存在时更新内容和更新时间，保留原有的创建时间
+func SaveMeetingRecord(dbName string, record *MeetingRecord) error {
+	db, err := meetingDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	record.UpdatedAt = time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = record.UpdatedAt
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO meetings (id, metadata, raw_content, tags, extra, created_at, updated_at)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	ON CONFLICT(id) DO UPDATE SET
+		metadata = excluded.metadata,
+		raw_content = excluded.raw_content,
+		tags = excluded.tags,
+		extra = excluded.extra,
+		updated_at = excluded.updated_at;
+	`, record.ID, record.Metadata, record.RawContent, record.Tags, record.Extra, record.CreatedAt, record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("保存会议失败: %w", err)
+	}
+	return nil
+}
+
+// GetMeetingRecord 读取会议，不存在时返回 ErrMeetingRecordNotFound
+func GetMeetingRecord(dbName, id string) (*MeetingRecord, error) {
+	db, err := meetingDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	var record MeetingRecord
+	var rawContent sql.NullString
+	err = db.QueryRow(`SELECT id, metadata, raw_content, tags, extra, created_at, updated_at
+	FROM meetings WHERE id = ?1;`, id).Scan(&record.ID, &record.Metadata, &rawContent, &record.Tags, &record.Extra, &record.CreatedAt, &record.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: ID为%s", ErrMeetingRecordNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询会议失败: %w", err)
+	}
+	if rawContent.Valid {
+		record.RawContent = &rawContent.String
+	}
+	return &record, nil
+}
+
+// ListMeetingRecordIDs 按ID顺序列出所有会议的ID，没有会议时返回空列表
+func ListMeetingRecordIDs(dbName string) ([]string, error) {
+	db, err := meetingDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT id FROM meetings ORDER BY id;`)
+	if err != nil {
+		return nil, fmt.Errorf("查询会议列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("读取会议列表失败: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取会议列表失败: %w", err)
+	}
+	return ids, nil
+}
+
+// DeleteMeetingRecord 删除会议，不存在时返回 ErrMeetingRecordNotFound。会议的待办事项不会一并删除
+func DeleteMeetingRecord(dbName, id string) error {
+	db, err := meetingDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.Exec(`DELETE FROM meetings WHERE id = ?1;`, id)
+	if err != nil {
+		return fmt.Errorf("删除会议失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("获取删除结果失败: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: ID为%s", ErrMeetingRecordNotFound, id)
+	}
+	return nil
+}
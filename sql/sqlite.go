@@ -1,10 +1,13 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	_ "github.com/glebarez/go-sqlite" // 引入纯Go实现的sqlite驱动
@@ -12,16 +15,58 @@ import (
 
 // Todo 待办事项
 type Todo struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    int       `json:"priority"`
-	DueDate     time.Time `json:"due_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	MeetingID   string    `json:"meeting_id"`
-	AssignedTo  string    `json:"assigned_to"`
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    int        `json:"priority"`
+	DueDate     time.Time  `json:"due_date"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	MeetingID   string     `json:"meeting_id"`
+	AssignedTo  string     `json:"assigned_to"`
+	CompletedAt *time.Time `json:"completed_at"` // 完成时间，未完成的待办为 nil
+	ParentID    *int64     `json:"parent_id"`    // 父待办ID，子任务全部完成后父待办才能完成；顶层待办为 nil
+	Extracted   bool       `json:"extracted"`    // 是否由会议抽取生成，同步会议待办时只会删除抽取生成的待办
+}
+
+// ErrTodoNotFound 待办事项不存在
+var ErrTodoNotFound = errors.New("待办事项不存在")
+
+// todoColumns 查询待办事项时读取的列，顺序与 scanTodo 一致
+const todoColumns = `id, title, description, status, priority, due_date,
+	       created_at, updated_at, meeting_id, assigned_to, completed_at, parent_id, extracted`
+
+// rowScanner 可以读取一行查询结果，*sql.Row 和 *sql.Rows 都满足
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTodo 按 todoColumns 的顺序读取一行待办事项，处理可为 NULL 的截止日期、完成时间和父待办
+func scanTodo(row rowScanner) (*Todo, error) {
+	var todo Todo
+	var dueDate, completedAt sql.NullTime // 处理NULL值
+	var parentID sql.NullInt64
+
+	err := row.Scan(
+		&todo.ID, &todo.Title, &todo.Description, &todo.Status, &todo.Priority,
+		&dueDate, &todo.CreatedAt, &todo.UpdatedAt, &todo.MeetingID, &todo.AssignedTo, &completedAt, &parentID, &todo.Extracted,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// 处理截止日期
+	if dueDate.Valid {
+		todo.DueDate = dueDate.Time
+	}
+	if completedAt.Valid {
+		todo.CompletedAt = &completedAt.Time
+	}
+	if parentID.Valid {
+		todo.ParentID = &parentID.Int64
+	}
+	return &todo, nil
 }
 
 // 打开数据库连接
@@ -54,6 +99,26 @@ func openDatabase(dbName string) (*sql.DB, error) {
 	return db, nil
 }
 
+// PingDatabase 检查数据库文件存在且 todos 表可查询，不会创建数据库文件，也不输出日志，适合频繁调用
+func PingDatabase(ctx context.Context, dbName string) error {
+	if _, err := os.Stat(dbName); err != nil {
+		return fmt.Errorf("数据库文件不可访问: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", dbName)
+	if err != nil {
+		return fmt.Errorf("打开数据库失败: %w", err)
+	}
+	defer db.Close()
+
+	var one int
+	err = db.QueryRowContext(ctx, "SELECT 1 FROM todos LIMIT 1").Scan(&one)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("查询数据库失败: %w", err)
+	}
+	return nil
+}
+
 // InitTodoTable 初始化Todo表
 func InitTodoTable(dbName string) error {
 	db, err := openDatabase(dbName)
@@ -74,13 +139,16 @@ func InitTodoTable(dbName string) error {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		title TEXT NOT NULL,
 		description TEXT,
-		status TEXT NOT NULL DEFAULT '未开始',
+		status TEXT NOT NULL DEFAULT 'NOT_STARTED',
 		priority INTEGER DEFAULT 3,
 		due_date TIMESTAMP,
 		created_at TIMESTAMP NOT NULL,
 		updated_at TIMESTAMP NOT NULL,
 		meeting_id TEXT,
-		assigned_to TEXT
+		assigned_to TEXT,
+		completed_at TIMESTAMP,
+		parent_id INTEGER,
+		extracted INTEGER NOT NULL DEFAULT 0
 	);
 	`
 
@@ -89,10 +157,105 @@ func InitTodoTable(dbName string) error {
 		return fmt.Errorf("创建Todo表失败: %w", err)
 	}
 
+	// 状态迁移需在补充完成时间之前，补充完成时间按状态代码查找已完成的待办
+	if err := migrateTodoStatusCodes(db); err != nil {
+		return err
+	}
+	if err := migrateTodoCompletedAt(db); err != nil {
+		return err
+	}
+	if err := migrateTodoParentID(db); err != nil {
+		return err
+	}
+	if err := migrateTodoExtracted(db); err != nil {
+		return err
+	}
+
+	if err := checkTodoValues(db); err != nil {
+		return err
+	}
+
 	fmt.Println("成功初始化Todo表")
 	return nil
 }
 
+// migrateTodoCompletedAt 为旧版本创建的表补充 completed_at 列。已完成的历史待办没有记录完成时间，
+// 以最后更新时间近似
+func migrateTodoCompletedAt(db *sql.DB) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('todos') WHERE name = 'completed_at';`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("读取Todo表结构失败: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec("ALTER TABLE todos ADD COLUMN completed_at TIMESTAMP;"); err != nil {
+		return fmt.Errorf("添加 completed_at 列失败: %w", err)
+	}
+	if _, err := db.Exec("UPDATE todos SET completed_at = updated_at WHERE status = ?1;", string(TodoStatusCompleted)); err != nil {
+		return fmt.Errorf("回填待办完成时间失败: %w", err)
+	}
+
+	fmt.Println("已为Todo表添加 completed_at 列")
+	return nil
+}
+
+// migrateTodoParentID 为旧版本创建的表补充 parent_id 列，已有待办都为顶层待办
+func migrateTodoParentID(db *sql.DB) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('todos') WHERE name = 'parent_id';`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("读取Todo表结构失败: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec("ALTER TABLE todos ADD COLUMN parent_id INTEGER;"); err != nil {
+		return fmt.Errorf("添加 parent_id 列失败: %w", err)
+	}
+
+	fmt.Println("已为Todo表添加 parent_id 列")
+	return nil
+}
+
+// migrateTodoExtracted 为旧版本创建的表补充 extracted 列。旧版本没有记录待办来源，
+// 会议抽取生成的待办描述固定为"来自会议: <标题>"，按此回填；其余待办视为手动创建
+func migrateTodoExtracted(db *sql.DB) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('todos') WHERE name = 'extracted';`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("读取Todo表结构失败: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec("ALTER TABLE todos ADD COLUMN extracted INTEGER NOT NULL DEFAULT 0;"); err != nil {
+		return fmt.Errorf("添加 extracted 列失败: %w", err)
+	}
+	if _, err := db.Exec(`UPDATE todos SET extracted = 1 WHERE meeting_id != '' AND description LIKE '来自会议: %';`); err != nil {
+		return fmt.Errorf("回填待办来源失败: %w", err)
+	}
+
+	fmt.Println("已为Todo表添加 extracted 列")
+	return nil
+}
+
+// completionTime 根据状态计算待办的完成时间：已完成的待办保留原有完成时间，没有时记为 now；
+// 其他状态的完成时间为空
+func completionTime(status string, previous *time.Time, now time.Time) *time.Time {
+	if status != string(TodoStatusCompleted) {
+		return nil
+	}
+	if previous != nil {
+		return previous
+	}
+	return &now
+}
+
 // AddTodo 添加新的待办事项
 func AddTodo(dbName string, todo *Todo) (int64, error) {
 	db, err := openDatabase(dbName)
@@ -105,18 +268,19 @@ func AddTodo(dbName string, todo *Todo) (int64, error) {
 	now := time.Now()
 	todo.CreatedAt = now
 	todo.UpdatedAt = now
+	todo.CompletedAt = completionTime(todo.Status, nil, now)
 
 	// 插入数据
 	insertSQL := `
 	INSERT INTO todos (
 		title, description, status, priority, due_date, 
-		created_at, updated_at, meeting_id, assigned_to
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+		created_at, updated_at, meeting_id, assigned_to, completed_at, parent_id, extracted
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 	`
 
 	result, err := db.Exec(insertSQL,
 		todo.Title, todo.Description, todo.Status, todo.Priority, todo.DueDate,
-		todo.CreatedAt, todo.UpdatedAt, todo.MeetingID, todo.AssignedTo)
+		todo.CreatedAt, todo.UpdatedAt, todo.MeetingID, todo.AssignedTo, todo.CompletedAt, todo.ParentID, todo.Extracted)
 	if err != nil {
 		return 0, fmt.Errorf("添加待办事项失败: %w", err)
 	}
@@ -128,10 +292,14 @@ func AddTodo(dbName string, todo *Todo) (int64, error) {
 	}
 
 	todo.ID = id
+
+	// 广播变更事件
+	todoBroker.publish(TodoEventCreated, todo)
+
 	return id, nil
 }
 
-// GetTodoByID 根据ID获取待办事项
+// GetTodoByID 根据ID获取待办事项，不存在时返回 ErrTodoNotFound
 func GetTodoByID(dbName string, id int64) (*Todo, error) {
 	db, err := openDatabase(dbName)
 	if err != nil {
@@ -139,20 +307,15 @@ func GetTodoByID(dbName string, id int64) (*Todo, error) {
 	}
 	defer db.Close()
 
-	// 使用ListTodos查找匹配ID的待办事项
-	todos, err := ListTodos(dbName, "", "", 0)
-	if err != nil {
-		return nil, fmt.Errorf("查询待办事项列表失败: %w", err)
+	row := db.QueryRow(`SELECT `+todoColumns+` FROM todos WHERE id = ?1;`, id)
+	todo, err := scanTodo(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: ID为%d", ErrTodoNotFound, id)
 	}
-
-	// 查找匹配的ID
-	for _, todo := range todos {
-		if todo.ID == id {
-			return todo, nil
-		}
+	if err != nil {
+		return nil, fmt.Errorf("查询待办事项失败: %w", err)
 	}
-
-	return nil, fmt.Errorf("找不到ID为%d的待办事项", id)
+	return todo, nil
 }
 
 // UpdateTodo 更新待办事项
@@ -166,17 +329,34 @@ func UpdateTodo(dbName string, todo *Todo) error {
 	// 设置更新时间
 	todo.UpdatedAt = time.Now()
 
-	// 更新数据
+	// 记录更新前关联的会议ID，用于会议变更时通知原会议的订阅者；
+	// 记录原完成时间，重复标记完成时保留第一次完成的时间
+	var previousMeetingID string
+	var previousCompletedAt sql.NullTime
+	_ = db.QueryRow(`SELECT meeting_id, completed_at FROM todos WHERE id = ?1;`, todo.ID).Scan(&previousMeetingID, &previousCompletedAt)
+
+	var completedAt *time.Time
+	if previousCompletedAt.Valid {
+		completedAt = &previousCompletedAt.Time
+	}
+	todo.CompletedAt = completionTime(todo.Status, completedAt, todo.UpdatedAt)
+
+	// 更新数据。修改 parent_id 时在同一条语句中检查新的父待办存在且不是待办自身或其子孙，
+	// 避免并发的两个请求（A→B 与 B→A）各自通过 ValidateTodoParent 后形成循环
 	updateSQL := `
 	UPDATE todos
 	SET title = ?1, description = ?2, status = ?3, priority = ?4, due_date = ?5,
-	    updated_at = ?6, meeting_id = ?7, assigned_to = ?8
-	WHERE id = ?9;
+	    updated_at = ?6, meeting_id = ?7, assigned_to = ?8, completed_at = ?9, parent_id = ?10
+	WHERE id = ?11
+	  AND (?10 IS NULL
+	       OR ?10 IS (SELECT parent_id FROM todos WHERE id = ?11)
+	       OR (EXISTS (SELECT 1 FROM todos WHERE id = ?10)
+	           AND NOT EXISTS (` + todoAncestorsCTE("?10") + ` SELECT 1 FROM ancestors WHERE id = ?11)));
 	`
 
 	result, err := db.Exec(updateSQL,
 		todo.Title, todo.Description, todo.Status, todo.Priority, todo.DueDate,
-		todo.UpdatedAt, todo.MeetingID, todo.AssignedTo, todo.ID)
+		todo.UpdatedAt, todo.MeetingID, todo.AssignedTo, todo.CompletedAt, todo.ParentID, todo.ID)
 	if err != nil {
 		return fmt.Errorf("更新待办事项失败: %w", err)
 	}
@@ -187,9 +367,24 @@ func UpdateTodo(dbName string, todo *Todo) error {
 		return fmt.Errorf("获取更新行数失败: %w", err)
 	}
 	if rowsAffected == 0 {
+		// 待办存在时说明新的父待办未通过检查，返回具体原因
+		var exists bool
+		_ = db.QueryRow(`SELECT EXISTS (SELECT 1 FROM todos WHERE id = ?1);`, todo.ID).Scan(&exists)
+		if exists && todo.ParentID != nil {
+			if err := checkTodoParent(db, todo.ID, *todo.ParentID); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %d", ErrTodoParentCycle, *todo.ParentID)
+		}
 		return fmt.Errorf("找不到ID为%d的待办事项", todo.ID)
 	}
 
+	// 广播变更事件
+	if previousMeetingID != todo.MeetingID {
+		todoBroker.publish(TodoEventDeleted, &Todo{ID: todo.ID, MeetingID: previousMeetingID})
+	}
+	todoBroker.publish(TodoEventUpdated, todo)
+
 	return nil
 }
 
@@ -201,6 +396,10 @@ func DeleteTodo(dbName string, id int64) error {
 	}
 	defer db.Close()
 
+	// 记录删除前关联的会议ID，用于广播变更事件
+	var meetingID string
+	_ = db.QueryRow(`SELECT meeting_id FROM todos WHERE id = ?1;`, id).Scan(&meetingID)
+
 	// 删除数据
 	deleteSQL := `DELETE FROM todos WHERE id = ?1;`
 
@@ -218,82 +417,233 @@ func DeleteTodo(dbName string, id int64) error {
 		return fmt.Errorf("找不到ID为%d的待办事项", id)
 	}
 
+	// 被删除待办的子任务成为顶层待办
+	if _, err := db.Exec(`UPDATE todos SET parent_id = NULL WHERE parent_id = ?1;`, id); err != nil {
+		return fmt.Errorf("解除子任务关联失败: %w", err)
+	}
+
+	// 广播变更事件
+	todoBroker.publish(TodoEventDeleted, &Todo{ID: id, MeetingID: meetingID})
+
 	return nil
 }
 
+// DeleteTodosByMeetingID 用一条语句删除会议的全部待办事项并返回删除数量，会议没有待办时返回0。
+// meetingID 不能为空，避免误删所有未关联会议的待办；其他会议中以被删除待办为父待办的子任务成为顶层待办
+func DeleteTodosByMeetingID(dbName string, meetingID string) (int64, error) {
+	if meetingID == "" {
+		return 0, fmt.Errorf("会议ID不能为空")
+	}
+
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("开始事务失败: %w", err)
+	}
+
+	// RETURNING 在删除的同时返回被删除的ID，用于解除子任务关联和广播变更事件
+	rows, err := tx.Query(`DELETE FROM todos WHERE meeting_id = ?1 RETURNING id;`, meetingID)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("删除会议待办事项失败: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return 0, fmt.Errorf("读取删除的待办事项失败: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("删除会议待办事项失败: %w", err)
+	}
+
+	if len(ids) > 0 {
+		if _, err := tx.Exec(`UPDATE todos SET parent_id = NULL WHERE parent_id IS NOT NULL AND parent_id NOT IN (SELECT id FROM todos);`); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("解除子任务关联失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	// 事务提交成功后再广播变更事件
+	for _, id := range ids {
+		todoBroker.publish(TodoEventDeleted, &Todo{ID: id, MeetingID: meetingID})
+	}
+
+	return int64(len(ids)), nil
+}
+
+// TodoFilter 待办事项的筛选条件，零值字段表示不按该条件筛选，多个条件之间为"且"的关系
+type TodoFilter struct {
+	MeetingID  string
+	Status     string
+	Priority   int
+	AssignedTo string
+	DueBefore  time.Time // 只返回截止日期早于该时间的待办，未设置截止日期的待办不会被返回
+	Sort       string    // 排序方式，取值为 TodoSorts 之一，为空时按优先级升序、截止日期升序
+}
+
+// TodoSorts 待办列表允许的排序方式，"-" 前缀表示降序
+var TodoSorts = []string{
+	"priority", "-priority",
+	"due_date", "-due_date",
+	"created_at", "-created_at",
+	"status", "-status",
+}
+
+// defaultTodoOrder 未指定排序方式时的排序
+const defaultTodoOrder = "priority ASC, due_date ASC"
+
+// IsValidTodoSort 判断排序方式是否在允许的集合中，空字符串表示默认排序
+func IsValidTodoSort(sort string) bool {
+	if sort == "" {
+		return true
+	}
+	for _, s := range TodoSorts {
+		if s == sort {
+			return true
+		}
+	}
+	return false
+}
+
+// todoOrderClause 将排序方式转换为 ORDER BY 子句。列名只来自白名单，不拼接用户输入；
+// 状态按流转顺序而不是文本排序，相同值时按 id 排序保证结果稳定
+func todoOrderClause(sort string) string {
+	if !IsValidTodoSort(sort) || sort == "" {
+		return defaultTodoOrder
+	}
+
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = sort[1:]
+	}
+
+	column := sort
+	if sort == "status" {
+		var cases strings.Builder
+		cases.WriteString("CASE status")
+		for i, status := range TodoStatuses {
+			fmt.Fprintf(&cases, " WHEN '%s' THEN %d", status, i)
+		}
+		fmt.Fprintf(&cases, " ELSE %d END", len(TodoStatuses))
+		column = cases.String()
+	}
+
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction)
+}
+
 // ListTodos 列出待办事项，可按条件筛选
 func ListTodos(dbName string, meetingID string, status string, priority int) ([]*Todo, error) {
-	db, err := openDatabase(dbName)
+	return ListTodosWithFilter(dbName, TodoFilter{
+		MeetingID: meetingID,
+		Status:    status,
+		Priority:  priority,
+	})
+}
+
+// ListTodosWithFilter 按筛选条件列出待办事项，结果按 filter.Sort 排序，默认按优先级升序、截止日期升序排列
+func ListTodosWithFilter(dbName string, filter TodoFilter) ([]*Todo, error) {
+	var todos []*Todo
+	err := IterateTodosWithFilter(dbName, filter, func(todo *Todo) error {
+		todos = append(todos, todo)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return todos, nil
+}
+
+// IterateTodosWithFilter 按筛选条件逐条读取待办事项并交给 fn 处理，不在内存中缓存整个结果集，
+// 顺序与 ListTodosWithFilter 相同。fn 返回错误时停止遍历并返回该错误
+func IterateTodosWithFilter(dbName string, filter TodoFilter, fn func(todo *Todo) error) error {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return err
+	}
 	defer db.Close()
 
 	// 构建查询条件
 	querySQL := `
-	SELECT id, title, description, status, priority, due_date, 
-	       created_at, updated_at, meeting_id, assigned_to
+	SELECT ` + todoColumns + `
 	FROM todos
 	WHERE 1=1
 	`
 	var args []interface{}
 	paramIndex := 1
 
-	if meetingID != "" {
+	if filter.MeetingID != "" {
 		querySQL += fmt.Sprintf(" AND meeting_id = ?%d", paramIndex)
-		args = append(args, meetingID)
+		args = append(args, filter.MeetingID)
 		paramIndex++
 	}
 
-	if status != "" {
+	if filter.Status != "" {
 		querySQL += fmt.Sprintf(" AND status = ?%d", paramIndex)
-		args = append(args, status)
+		args = append(args, filter.Status)
 		paramIndex++
 	}
 
-	if priority > 0 {
+	if filter.Priority > 0 {
 		querySQL += fmt.Sprintf(" AND priority = ?%d", paramIndex)
-		args = append(args, priority)
+		args = append(args, filter.Priority)
 		paramIndex++
 	}
 
-	querySQL += " ORDER BY priority ASC, due_date ASC;"
+	if filter.AssignedTo != "" {
+		querySQL += fmt.Sprintf(" AND assigned_to = ?%d", paramIndex)
+		args = append(args, filter.AssignedTo)
+		paramIndex++
+	}
+
+	querySQL += " ORDER BY " + todoOrderClause(filter.Sort) + ";"
 
 	// 执行查询
 	rows, err := db.Query(querySQL, args...)
 	if err != nil {
-		return nil, fmt.Errorf("查询待办事项列表失败: %w", err)
+		return fmt.Errorf("查询待办事项列表失败: %w", err)
 	}
 	defer rows.Close()
 
 	// 遍历结果集
-	var todos []*Todo
 	for rows.Next() {
-		var todo Todo
-		var dueDate sql.NullTime // 处理NULL值
-
-		err := rows.Scan(
-			&todo.ID, &todo.Title, &todo.Description, &todo.Status, &todo.Priority,
-			&dueDate, &todo.CreatedAt, &todo.UpdatedAt, &todo.MeetingID, &todo.AssignedTo,
-		)
+		todo, err := scanTodo(rows)
 		if err != nil {
-			return nil, fmt.Errorf("读取待办事项数据失败: %w", err)
+			return fmt.Errorf("读取待办事项数据失败: %w", err)
 		}
 
-		// 处理截止日期
-		if dueDate.Valid {
-			todo.DueDate = dueDate.Time
+		// 截止时间在数据库中以带时区的文本存储，在Go中比较以避免时区格式差异
+		if !filter.DueBefore.IsZero() && (todo.DueDate.IsZero() || !todo.DueDate.Before(filter.DueBefore)) {
+			continue
 		}
 
-		todos = append(todos, &todo)
+		if err := fn(todo); err != nil {
+			return err
+		}
 	}
 
 	// 检查遍历错误
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("遍历待办事项数据失败: %w", err)
+		return fmt.Errorf("遍历待办事项数据失败: %w", err)
 	}
 
-	return todos, nil
+	return nil
 }
 
 // GetTodosByMeetingID 根据会议ID获取待办事项
@@ -301,6 +651,11 @@ func GetTodosByMeetingID(dbName string, meetingID string) ([]*Todo, error) {
 	return ListTodos(dbName, meetingID, "", 0)
 }
 
+// GetTodosByAssignee 根据负责人获取待办事项
+func GetTodosByAssignee(dbName string, assignedTo string) ([]*Todo, error) {
+	return ListTodosWithFilter(dbName, TodoFilter{AssignedTo: assignedTo})
+}
+
 // GetTodosByStatus 根据状态获取待办事项
 func GetTodosByStatus(dbName string, status string) ([]*Todo, error) {
 	return ListTodos(dbName, "", status, 0)
@@ -329,8 +684,8 @@ func BatchAddTodos(dbName string, todos []*Todo) error {
 	insertSQL := `
 	INSERT INTO todos (
 		title, description, status, priority, due_date, 
-		created_at, updated_at, meeting_id, assigned_to
-	) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9);
+		created_at, updated_at, meeting_id, assigned_to, completed_at, parent_id, extracted
+	) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12);
 	`
 
 	stmt, err := tx.Prepare(insertSQL)
@@ -348,15 +703,19 @@ func BatchAddTodos(dbName string, todos []*Todo) error {
 		// 设置创建和更新时间
 		todo.CreatedAt = now
 		todo.UpdatedAt = now
+		todo.CompletedAt = completionTime(todo.Status, nil, now)
 
-		_, err := stmt.Exec(
+		result, err := stmt.Exec(
 			todo.Title, todo.Description, todo.Status, todo.Priority, todo.DueDate,
-			todo.CreatedAt, todo.UpdatedAt, todo.MeetingID, todo.AssignedTo,
+			todo.CreatedAt, todo.UpdatedAt, todo.MeetingID, todo.AssignedTo, todo.CompletedAt, todo.ParentID, todo.Extracted,
 		)
 		if err != nil {
 			tx.Rollback()
 			return fmt.Errorf("批量插入待办事项失败: %w", err)
 		}
+		if id, err := result.LastInsertId(); err == nil {
+			todo.ID = id
+		}
 	}
 
 	// 提交事务
@@ -364,5 +723,95 @@ func BatchAddTodos(dbName string, todos []*Todo) error {
 		return fmt.Errorf("提交事务失败: %w", err)
 	}
 
+	// 事务提交成功后再广播变更事件
+	for _, todo := range todos {
+		todoBroker.publish(TodoEventCreated, todo)
+	}
+
+	return nil
+}
+
+// ApplyTodoChanges 在同一事务中新增和删除待办事项，任一操作失败时全部回滚
+func ApplyTodoChanges(dbName string, add []*Todo, remove []*Todo) error {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+
+	now := time.Now()
+	for _, todo := range add {
+		todo.CreatedAt = now
+		todo.UpdatedAt = now
+		todo.CompletedAt = completionTime(todo.Status, nil, now)
+
+		result, err := tx.Exec(`
+		INSERT INTO todos (
+			title, description, status, priority, due_date,
+			created_at, updated_at, meeting_id, assigned_to, completed_at, parent_id, extracted
+		) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12);`,
+			todo.Title, todo.Description, todo.Status, todo.Priority, todo.DueDate,
+			todo.CreatedAt, todo.UpdatedAt, todo.MeetingID, todo.AssignedTo, todo.CompletedAt, todo.ParentID, todo.Extracted,
+		)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("添加待办事项失败: %w", err)
+		}
+		if id, err := result.LastInsertId(); err == nil {
+			todo.ID = id
+		}
+	}
+
+	for _, todo := range remove {
+		if _, err := tx.Exec(`DELETE FROM todos WHERE id = ?1;`, todo.ID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("删除待办事项失败: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE todos SET parent_id = NULL WHERE parent_id = ?1;`, todo.ID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("解除子任务关联失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	// 事务提交成功后再广播变更事件
+	for _, todo := range add {
+		todoBroker.publish(TodoEventCreated, todo)
+	}
+	for _, todo := range remove {
+		todoBroker.publish(TodoEventDeleted, &Todo{ID: todo.ID, MeetingID: todo.MeetingID})
+	}
+
 	return nil
 }
+
+// GetOverdueTodos 获取已逾期或将在 within 时间内到期、且尚未完成的待办事项。
+// 未设置截止日期的待办事项不会被返回
+func GetOverdueTodos(dbName string, within time.Duration) ([]*Todo, error) {
+	todos, err := ListTodos(dbName, "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// 截止时间在数据库中以带时区的文本存储，在Go中比较以避免时区格式差异
+	deadline := time.Now().Add(within)
+	var overdue []*Todo
+	for _, todo := range todos {
+		if todo.Status == string(TodoStatusCompleted) || todo.DueDate.IsZero() {
+			continue
+		}
+		if todo.DueDate.Before(deadline) {
+			overdue = append(overdue, todo)
+		}
+	}
+
+	return overdue, nil
+}
@@ -0,0 +1,172 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TodoStatus 待办事项状态，数据库和接口中使用稳定的英文代码，显示名称按语言由 TodoStatusLabel 提供
+type TodoStatus string
+
+// 允许的待办事项状态
+const (
+	TodoStatusNotStarted TodoStatus = "NOT_STARTED"
+	TodoStatusInProgress TodoStatus = "IN_PROGRESS"
+	TodoStatusCompleted  TodoStatus = "DONE"
+)
+
+// TodoStatuses 所有允许的待办事项状态，按流转顺序排列
+var TodoStatuses = []TodoStatus{
+	TodoStatusNotStarted,
+	TodoStatusInProgress,
+	TodoStatusCompleted,
+}
+
+// 允许的待办事项优先级，数值越小优先级越高
+const (
+	TodoPriorityHigh   = 1
+	TodoPriorityMedium = 2
+	TodoPriorityLow    = 3
+)
+
+// labelLocaleDefault 显示名称的默认语言，请求的语言没有对应名称时使用
+const labelLocaleDefault = "zh"
+
+// todoStatusLabels 各语言的状态显示名称，中文名称同时是旧版本数据库中存储的状态值
+var todoStatusLabels = map[string]map[TodoStatus]string{
+	"zh": {TodoStatusNotStarted: "未开始", TodoStatusInProgress: "进行中", TodoStatusCompleted: "已完成"},
+	"en": {TodoStatusNotStarted: "Not started", TodoStatusInProgress: "In progress", TodoStatusCompleted: "Done"},
+	"ja": {TodoStatusNotStarted: "未着手", TodoStatusInProgress: "進行中", TodoStatusCompleted: "完了"},
+}
+
+// todoPriorityLabels 各语言的优先级显示名称
+var todoPriorityLabels = map[string]map[int]string{
+	"zh": {TodoPriorityHigh: "高", TodoPriorityMedium: "中", TodoPriorityLow: "低"},
+	"en": {TodoPriorityHigh: "High", TodoPriorityMedium: "Medium", TodoPriorityLow: "Low"},
+	"ja": {TodoPriorityHigh: "高", TodoPriorityMedium: "中", TodoPriorityLow: "低"},
+}
+
+// TodoStatusOption 状态代码及其显示名称
+type TodoStatusOption struct {
+	Value TodoStatus `json:"value"`
+	Label string     `json:"label"`
+}
+
+// TodoPriorityOption 优先级取值及其显示名称
+type TodoPriorityOption struct {
+	Value int    `json:"value"`
+	Label string `json:"label"`
+}
+
+// TodoStatusOptions 返回所有允许的状态及指定语言的显示名称，按流转顺序排列
+func TodoStatusOptions(locale string) []TodoStatusOption {
+	options := make([]TodoStatusOption, 0, len(TodoStatuses))
+	for _, status := range TodoStatuses {
+		options = append(options, TodoStatusOption{Value: status, Label: TodoStatusLabel(string(status), locale)})
+	}
+	return options
+}
+
+// TodoPriorities 返回所有允许的优先级及指定语言的显示名称
+func TodoPriorities(locale string) []TodoPriorityOption {
+	options := make([]TodoPriorityOption, 0, TodoPriorityLow-TodoPriorityHigh+1)
+	for priority := TodoPriorityHigh; priority <= TodoPriorityLow; priority++ {
+		options = append(options, TodoPriorityOption{Value: priority, Label: TodoPriorityLabel(priority, locale)})
+	}
+	return options
+}
+
+// TodoStatusLabel 返回状态在指定语言下的显示名称，语言不支持时使用中文，状态不在允许范围内时原样返回
+func TodoStatusLabel(status, locale string) string {
+	if label, ok := todoStatusLabels[locale][TodoStatus(status)]; ok {
+		return label
+	}
+	if label, ok := todoStatusLabels[labelLocaleDefault][TodoStatus(status)]; ok {
+		return label
+	}
+	return status
+}
+
+// TodoPriorityLabel 返回优先级在指定语言下的显示名称，语言不支持时使用中文，优先级不在允许范围内时返回空字符串
+func TodoPriorityLabel(priority int, locale string) string {
+	if label, ok := todoPriorityLabels[locale][priority]; ok {
+		return label
+	}
+	return todoPriorityLabels[labelLocaleDefault][priority]
+}
+
+// ParseTodoStatus 将状态代码（忽略大小写）或任一语言的显示名称转换为状态代码，
+// 兼容仍提交中文状态的旧客户端和配置
+func ParseTodoStatus(status string) (TodoStatus, bool) {
+	status = strings.TrimSpace(status)
+	for _, s := range TodoStatuses {
+		if strings.EqualFold(string(s), status) {
+			return s, true
+		}
+	}
+	for _, labels := range todoStatusLabels {
+		for s, label := range labels {
+			if strings.EqualFold(label, status) {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsValidTodoStatus 判断状态是否为允许的状态代码
+func IsValidTodoStatus(status string) bool {
+	for _, s := range TodoStatuses {
+		if string(s) == status {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateTodoStatusCodes 将旧版本以中文存储的状态转换为状态代码
+func migrateTodoStatusCodes(db *sql.DB) error {
+	for status, label := range todoStatusLabels[labelLocaleDefault] {
+		result, err := db.Exec("UPDATE todos SET status = ?1 WHERE status = ?2;", string(status), label)
+		if err != nil {
+			return fmt.Errorf("迁移待办事项状态失败: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			fmt.Printf("已将 %d 条待办事项的状态从 %s 迁移为 %s\n", n, label, status)
+		}
+	}
+	return nil
+}
+
+// IsValidTodoPriority 判断优先级是否在 1-3 之间
+func IsValidTodoPriority(priority int) bool {
+	return priority >= TodoPriorityHigh && priority <= TodoPriorityLow
+}
+
+// checkTodoValues 统计历史数据中不在允许范围内的状态和优先级并输出警告。
+// SQLite 无法为已有表追加 CHECK 约束，且约束会让历史数据在更新时失败，因此只在应用层校验新写入的数据
+func checkTodoValues(db *sql.DB) error {
+	placeholders := make([]string, len(TodoStatuses))
+	args := make([]interface{}, 0, len(TodoStatuses)+2)
+	for i, s := range TodoStatuses {
+		placeholders[i] = "?"
+		args = append(args, string(s))
+	}
+	args = append(args, TodoPriorityHigh, TodoPriorityLow)
+
+	query := fmt.Sprintf(
+		"SELECT COUNT(*) FROM todos WHERE status NOT IN (%s) OR priority < ? OR priority > ?",
+		strings.Join(placeholders, ", "),
+	)
+
+	var count int
+	if err := db.QueryRow(query, args...).Scan(&count); err != nil {
+		return fmt.Errorf("检查待办事项数据失败: %w", err)
+	}
+
+	if count > 0 {
+		fmt.Printf("警告: 有 %d 条待办事项的状态或优先级不在允许范围内，更新这些待办时需指定有效值\n", count)
+	}
+	return nil
+}
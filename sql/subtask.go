@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// 子任务关系的校验错误
+var (
+	ErrTodoParentNotFound = errors.New("父待办不存在")
+	ErrTodoParentCycle    = errors.New("子任务关系不能形成循环")
+)
+
+// TodoNode 待办及其全部子任务组成的树
+type TodoNode struct {
+	*Todo
+	Children []*TodoNode `json:"children"`
+}
+
+// todoAncestorsCTE 从参数 param 指定的待办沿 parent_id 向上查找，结果包含该待办自身及其全部祖先；
+// UNION 去重避免历史数据中的循环导致无限递归
+func todoAncestorsCTE(param string) string {
+	return `WITH RECURSIVE ancestors(id, parent_id) AS (
+		SELECT id, parent_id FROM todos WHERE id = ` + param + `
+		UNION
+		SELECT todos.id, todos.parent_id FROM todos JOIN ancestors ON todos.id = ancestors.parent_id
+	)`
+}
+
+// ValidateTodoParent 校验将待办 id 的父待办设为 parentID 是否合法：父待办必须存在，且不能是待办自身或其子孙，
+// 否则子任务关系会形成循环。新建的待办 id 传 0。
+// 校验结果只用于提前返回明确的错误，UpdateTodo 写入 parent_id 时会在同一条语句中再次检查
+func ValidateTodoParent(dbName string, id, parentID int64) error {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return checkTodoParent(db, id, parentID)
+}
+
+// checkTodoParent 在已打开的数据库上校验父待办，规则与 ValidateTodoParent 相同
+func checkTodoParent(db *sql.DB, id, parentID int64) error {
+	if id != 0 && parentID == id {
+		return fmt.Errorf("%w: 待办不能是自己的子任务", ErrTodoParentCycle)
+	}
+
+	// 从新的父待办向上查找祖先
+	rows, err := db.Query(todoAncestorsCTE("?1")+`
+	SELECT id FROM ancestors;`, parentID)
+	if err != nil {
+		return fmt.Errorf("查询父待办失败: %w", err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var ancestorID int64
+		if err := rows.Scan(&ancestorID); err != nil {
+			return fmt.Errorf("读取父待办失败: %w", err)
+		}
+		found = true
+		// 祖先中出现待办自身说明父待办是它的子孙
+		if id != 0 && ancestorID == id {
+			return fmt.Errorf("%w: 待办 %d 是待办 %d 的子任务", ErrTodoParentCycle, parentID, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("遍历父待办失败: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("%w: %d", ErrTodoParentNotFound, parentID)
+	}
+	return nil
+}
+
+// GetTodoTree 返回待办及其全部子任务，子任务按默认排序排列
+func GetTodoTree(dbName string, id int64) (*TodoNode, error) {
+	todos, err := listTodoSubtree(dbName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Todo
+	children := make(map[int64][]*Todo)
+	for _, todo := range todos {
+		if todo.ID == id {
+			root = todo
+		} else if todo.ParentID != nil {
+			children[*todo.ParentID] = append(children[*todo.ParentID], todo)
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("找不到ID为%d的待办事项", id)
+	}
+	return buildTodoNode(root, children, map[int64]bool{}), nil
+}
+
+// listTodoSubtree 用递归查询读取待办 id 及其全部子孙任务，按默认排序排列
+func listTodoSubtree(dbName string, id int64) ([]*Todo, error) {
+	db, err := openDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// UNION 去重避免历史数据中的循环导致无限递归
+	rows, err := db.Query(`
+	WITH RECURSIVE subtree(id) AS (
+		SELECT id FROM todos WHERE id = ?1
+		UNION
+		SELECT todos.id FROM todos JOIN subtree ON todos.parent_id = subtree.id
+	)
+	SELECT `+todoColumns+`
+	FROM todos
+	WHERE id IN (SELECT id FROM subtree)
+	ORDER BY `+defaultTodoOrder+`;`, id)
+	if err != nil {
+		return nil, fmt.Errorf("查询子任务失败: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []*Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取待办事项数据失败: %w", err)
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历待办事项数据失败: %w", err)
+	}
+	return todos, nil
+}
+
+// IncompleteSubtasks 返回待办尚未完成的全部子孙任务
+func IncompleteSubtasks(dbName string, id int64) ([]*Todo, error) {
+	tree, err := GetTodoTree(dbName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var incomplete []*Todo
+	var walk func(node *TodoNode)
+	walk = func(node *TodoNode) {
+		for _, child := range node.Children {
+			if child.Status != string(TodoStatusCompleted) {
+				incomplete = append(incomplete, child.Todo)
+			}
+			walk(child)
+		}
+	}
+	walk(tree)
+	return incomplete, nil
+}
+
+// buildTodoNode 递归构建子任务树，visited 防止历史数据中的循环导致无限递归
+func buildTodoNode(todo *Todo, children map[int64][]*Todo, visited map[int64]bool) *TodoNode {
+	visited[todo.ID] = true
+	node := &TodoNode{Todo: todo, Children: []*TodoNode{}}
+	for _, child := range children[todo.ID] {
+		if visited[child.ID] {
+			continue
+		}
+		node.Children = append(node.Children, buildTodoNode(child, children, visited))
+	}
+	return node
+}